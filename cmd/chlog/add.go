@@ -1,8 +1,10 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/ariel-frischer/chlog/pkg/changelog"
@@ -12,16 +14,29 @@ import (
 var (
 	addVersion  string
 	addInternal bool
+	addPR       int
+	addIssues   []string
+	addAuthors  []string
+	addScope    string
+	addBreaking bool
+	addFragment bool
 )
 
 var addCmd = &cobra.Command{
 	Use:   "add <category> [entries...]",
 	Short: "Add entries to the changelog",
-	Long:  "Add one or more entries to a category in the changelog.",
+	Long: `Add one or more entries to a category in the changelog.
+
+With --fragment (the default for unreleased entries when .chlog.yaml sets
+fragments: true), entries go to a fragment file in .chlog/unreleased/ named
+after the current branch, or a random id on main, instead of CHANGELOG.yaml.
+chlog release folds fragments into the new version and deletes them.`,
 	Example: `  chlog add added "Support dark mode"
   chlog add fixed --version 1.2.0 "Fix login timeout"
   chlog add changed --internal "Refactor auth middleware"
-  chlog add added "Feature A" "Feature B"`,
+  chlog add added "Feature A" "Feature B"
+  chlog add added --pr 42 --issue 40 --author alice --scope api "Add pagination"
+  chlog add fixed --fragment "Fix race in cache"`,
 	Args: cobra.MinimumNArgs(2),
 	RunE: runAdd,
 }
@@ -29,6 +44,12 @@ var addCmd = &cobra.Command{
 func init() {
 	addCmd.Flags().StringVarP(&addVersion, "version", "v", "unreleased", "target version")
 	addCmd.Flags().BoolVarP(&addInternal, "internal", "i", false, "add as internal entry")
+	addCmd.Flags().IntVar(&addPR, "pr", 0, "pull request number")
+	addCmd.Flags().StringSliceVar(&addIssues, "issue", nil, "related issue (repeatable)")
+	addCmd.Flags().StringSliceVar(&addAuthors, "author", nil, "entry author (repeatable)")
+	addCmd.Flags().StringVar(&addScope, "scope", "", "component scope")
+	addCmd.Flags().BoolVar(&addBreaking, "breaking", false, "mark as a breaking change")
+	addCmd.Flags().BoolVar(&addFragment, "fragment", false, "write to a fragment file instead of CHANGELOG.yaml")
 }
 
 func runAdd(cmd *cobra.Command, args []string) error {
@@ -44,7 +65,18 @@ func runAdd(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	c, err := changelog.Load(yamlFile)
+	meta := changelog.EntryMeta{
+		PR:       addPR,
+		Issues:   addIssues,
+		Authors:  addAuthors,
+		Scope:    addScope,
+		Breaking: addBreaking,
+	}
+	if useFragment(cmd) {
+		return addToFragment(category, entries, meta)
+	}
+
+	c, err := loadChangelog()
 	if err != nil {
 		if os.IsNotExist(err) {
 			return fmt.Errorf("%s not found — run 'chlog init' first", yamlFile)
@@ -63,21 +95,66 @@ func runAdd(cmd *cobra.Command, args []string) error {
 	}
 
 	for _, text := range entries {
-		changes.Append(category, text)
+		changes.AppendWithMeta(category, text, meta)
 	}
 
 	if err := changelog.Save(c, yamlFile); err != nil {
 		return fmt.Errorf("saving %s: %w", yamlFile, err)
 	}
 
-	label := "public"
+	success("Added %d %s %s entr%s to %s", len(entries), tierLabel(), categoryRef(category), pluralY(len(entries)), versionRef(v.Version))
+	return nil
+}
+
+// useFragment reports whether add writes a fragment: an explicit --fragment
+// flag wins, otherwise unreleased entries do when fragments are enabled.
+func useFragment(cmd *cobra.Command) bool {
+	if addFragment || (cmd != nil && cmd.Flags().Changed("fragment")) {
+		return addFragment
+	}
+	return loadConfig().Fragments && strings.EqualFold(addVersion, "unreleased")
+}
+
+// addToFragment appends entries to the current branch's fragment file.
+func addToFragment(category string, entries []string, meta changelog.EntryMeta) error {
+	if !strings.EqualFold(addVersion, "unreleased") {
+		return fmt.Errorf("fragments only hold unreleased entries — drop --version or --fragment")
+	}
+	cfg := loadConfig()
+	branch, _ := changelog.CurrentBranch()
+	path := filepath.Join(cfg.FragmentDirPath(), changelog.FragmentName(branch))
+	frag, err := changelog.LoadFragment(path)
+	if errors.Is(err, os.ErrNotExist) {
+		frag, err = &changelog.Fragment{Path: path}, nil
+	}
+	if err != nil {
+		return err
+	}
+
+	changes := &frag.Public
 	if addInternal {
-		label = "internal"
+		changes = &frag.Internal
+	}
+	for _, text := range entries {
+		changes.AppendWithMeta(category, text, meta)
+	}
+	if err := frag.Save(); err != nil {
+		return err
+	}
+	success("Added %d %s %s entr%s to %s", len(entries), tierLabel(), categoryRef(category), pluralY(len(entries)), fileRef(path))
+	if !cfg.Fragments {
+		warn("Fragments are not enabled — set fragments: true in %s so chlog reads them", configFile)
 	}
-	success("Added %d %s %s entr%s to %s", len(entries), label, categoryRef(category), pluralY(len(entries)), versionRef(v.Version))
 	return nil
 }
 
+func tierLabel() string {
+	if addInternal {
+		return "internal"
+	}
+	return "public"
+}
+
 func resolveVersionForAdd(c *changelog.Changelog, version string) (*changelog.Version, error) {
 	if strings.EqualFold(version, "unreleased") {
 		u := c.GetUnreleased()
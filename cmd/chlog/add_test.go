@@ -192,6 +192,34 @@ func TestRunAdd_AppendsToExisting(t *testing.T) {
 	}
 }
 
+func TestRunAdd_WithMetadata(t *testing.T) {
+	dir := t.TempDir()
+	yamlFile = filepath.Join(dir, "CHANGELOG.yaml")
+	writeTestChangelog(t, yamlFile, &changelog.Changelog{
+		Project:  "test",
+		Versions: []changelog.Version{{Version: "unreleased"}},
+	})
+
+	addVersion = "unreleased"
+	addInternal = false
+	addPR, addIssues, addScope = 42, []string{"40"}, "api"
+	defer func() { addPR, addIssues, addScope = 0, nil, "" }()
+
+	if err := runAdd(nil, []string{"added", "Pagination"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c := loadTestChangelog(t, yamlFile)
+	cat, ok := c.GetUnreleased().Public.Category("added")
+	if !ok || len(cat.Entries) != 1 {
+		t.Fatalf("expected one added entry, got %+v", cat)
+	}
+	meta := cat.MetaAt(0)
+	if meta.PR != 42 || meta.Scope != "api" || len(meta.Issues) != 1 || meta.Issues[0] != "40" {
+		t.Errorf("meta = %+v", meta)
+	}
+}
+
 func TestPluralY(t *testing.T) {
 	tests := map[string]struct {
 		n    int
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/ariel-frischer/chlog/pkg/changelog"
+	"github.com/spf13/cobra"
+)
+
+var (
+	announceFormat   string
+	announceInternal bool
+	announceWebhook  string
+)
+
+// webhookTimeout bounds a --webhook-url request.
+const webhookTimeout = 30 * time.Second
+
+var announceCmd = &cobra.Command{
+	Use:   "announce <version>",
+	Short: "Render a release announcement for Slack, Discord, Teams or Mattermost",
+	Long: `Render a version as a JSON payload for a chat platform's incoming webhook:
+Slack blocks, a Discord embed, a Teams Adaptive Card or a Mattermost message.
+The payload has a header with the project and version, the release date and
+compare link, and a section per category. Long releases are truncated to the
+platform's limits with a note of how many entries were left out.
+
+The payload is printed to stdout, ready to POST. With --webhook-url chlog
+posts it itself and fails unless the webhook answers 2xx.`,
+	Example: `  chlog announce 1.2.0 --format slack
+  chlog announce 1.2.0 --format discord --webhook-url "$DISCORD_WEBHOOK_URL"
+  chlog announce 1.2.0 --format teams | curl -H 'Content-Type: application/json' -d @- "$TEAMS_WEBHOOK_URL"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAnnounce,
+}
+
+func init() {
+	announceCmd.Flags().StringVar(&announceFormat, "format", "slack", "platform: slack, discord, teams or mattermost")
+	announceCmd.Flags().BoolVar(&announceInternal, "internal", false, "include internal entries")
+	announceCmd.Flags().StringVar(&announceWebhook, "webhook-url", "", "POST the payload to this incoming webhook instead of printing it")
+}
+
+func runAnnounce(cmd *cobra.Command, args []string) error {
+	format, err := changelog.ParseAnnounceFormat(announceFormat)
+	if err != nil {
+		return err
+	}
+	c, err := loadChangelog()
+	if err != nil {
+		return err
+	}
+	cfg := loadConfig()
+	payload, err := changelog.RenderAnnouncement(c, args[0], changelog.AnnounceOptions{
+		Format:          format,
+		IncludeInternal: announceInternal || cfg.IncludeInternal,
+		Config:          cfg,
+	})
+	if err != nil {
+		return err
+	}
+	if announceWebhook == "" {
+		_, err = os.Stdout.Write(payload)
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+	if err := changelog.PostWebhook(ctx, http.DefaultClient, announceWebhook, payload); err != nil {
+		return err
+	}
+	// The rest of a webhook URL is usually a secret token.
+	host := "webhook"
+	if u, err := url.Parse(announceWebhook); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	success("Posted the %s announcement to %s", highlight(args[0]), host)
+	return nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRunAnnounce_Webhook(t *testing.T) {
+	initReleaseRepo(t)
+	writeFile(t, yamlFile, "project: demo\nversions:\n  1.0.0:\n    date: \"2026-03-02\"\n    added:\n      - Login page\n")
+
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got = string(body)
+	}))
+	defer srv.Close()
+
+	announceFormat, announceWebhook = "discord", srv.URL+"/api/webhooks/1/token"
+	t.Cleanup(func() { announceFormat, announceWebhook = "slack", "" })
+	if err := runAnnounce(nil, []string{"1.0.0"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"title": "demo 1.0.0"`, `"name": "+ Added"`, `"value": "- Login page"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %s in posted payload:\n%s", want, got)
+		}
+	}
+}
+
+func TestRunAnnounce_BadFormat(t *testing.T) {
+	announceFormat = "irc"
+	t.Cleanup(func() { announceFormat = "slack" })
+	if err := runAnnounce(nil, []string{"1.0.0"}); err == nil || !strings.Contains(err.Error(), "unknown announce format") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ariel-frischer/chlog/pkg/changelog"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bumpDryRun bool
+	bumpPre    string
+	bumpDate   string
+)
+
+var bumpCmd = &cobra.Command{
+	Use:   "bump",
+	Short: "Compute the next version and release unreleased changes",
+	Long: `Compute the next semantic version from the unreleased changes and the
+latest release, then release it like "chlog release".
+
+Breaking entries require a major bump, otherwise the highest category rule
+applies: added, changed and deprecated → minor; removed → major; fixed,
+security and other categories → patch. Override rules per category with
+bump_rules in .chlog.yaml. Before 1.0.0, a major bump increments the minor
+version.`,
+	Example: `  chlog bump --dry-run         # print the next version only
+  chlog bump                   # release it
+  chlog bump --pre rc          # 1.3.0-rc.1, then 1.3.0-rc.2, ...`,
+	Args: cobra.NoArgs,
+	RunE: runBump,
+}
+
+func init() {
+	bumpCmd.Flags().BoolVar(&bumpDryRun, "dry-run", false, "print the next version without releasing")
+	bumpCmd.Flags().StringVar(&bumpPre, "pre", "", "release a pre-release with this identifier (e.g. rc, beta)")
+	bumpCmd.Flags().StringVar(&bumpDate, "date", "", "release date in YYYY-MM-DD format (default: today)")
+}
+
+func runBump(cmd *cobra.Command, args []string) error {
+	c, err := loadChangelog()
+	if err != nil {
+		return err
+	}
+	rules, err := loadConfig().BumpLevels()
+	if err != nil {
+		return err
+	}
+
+	b, err := c.NextVersion(changelog.BumpOptions{Rules: rules, Pre: bumpPre})
+	if err != nil {
+		return err
+	}
+	latest := b.Latest
+	if latest == "" {
+		latest = "(none)"
+	}
+	fmt.Printf("%s → %s (%s: %s)\n", latest, versionRef(b.Next), highlight(b.Level.String()), b.Reason)
+	if bumpDryRun {
+		return nil
+	}
+
+	date := bumpDate
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+	if err := c.Release(b.Next, date, changelog.ReleaseOptions{Scheme: changelog.SchemeSemver}); err != nil {
+		return err
+	}
+	if err := changelog.Save(c, yamlFile); err != nil {
+		return fmt.Errorf("saving %s: %w", yamlFile, err)
+	}
+	success("Released %s (%s) — unreleased block reset", versionRef(b.Next), highlight(date))
+	return removeFragments(c, b.Next)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ariel-frischer/chlog/pkg/changelog"
+)
+
+func TestRunBump(t *testing.T) {
+	dir := t.TempDir()
+	yamlFile = filepath.Join(dir, "CHANGELOG.yaml")
+	configFile = filepath.Join(dir, ".chlog.yaml")
+	c := &changelog.Changelog{Project: "demo", Versions: []changelog.Version{
+		{Version: "unreleased"},
+		{Version: "1.2.0", Date: "2024-01-01"},
+	}}
+	c.Versions[0].Public.Append("added", "Feature")
+	c.Versions[1].Public.Append("added", "Init")
+	writeTestChangelog(t, yamlFile, c)
+	bumpPre, bumpDate = "rc", "2024-02-01"
+	t.Cleanup(func() { bumpPre, bumpDate, bumpDryRun = "", "", false })
+
+	bumpDryRun = true
+	if err := runBump(nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := loadTestChangelog(t, yamlFile).ListVersions(); len(got) != 2 {
+		t.Fatalf("dry run changed versions: %v", got)
+	}
+
+	bumpDryRun = false
+	if err := runBump(nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := loadTestChangelog(t, yamlFile)
+	if v, err := got.GetVersion("1.3.0-rc.1"); err != nil || v.Date != "2024-02-01" {
+		t.Errorf("expected 1.3.0-rc.1 released on 2024-02-01, got %v", got.ListVersions())
+	}
+}
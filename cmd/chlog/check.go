@@ -4,73 +4,194 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/ariel-frischer/chlog/pkg/changelog"
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
 var (
-	checkInternal bool
-	checkSplit    bool
+	checkInternal  bool
+	checkSplit     bool
+	checkTemplate  string
+	checkSince     string
+	checkLabels    []string
+	checkImmutable string
+	checkAllowEdit []string
+	checkContext   int
+	checkFormat    string
 )
 
 var checkCmd = &cobra.Command{
 	Use:   "check",
 	Short: "Verify CHANGELOG.md matches CHANGELOG.yaml",
-	Long:  "Exit 0 = in sync, exit 1 = out of sync, exit 2 = validation error.",
-	RunE:  runCheck,
+	Long: `Exit 0 = in sync, exit 1 = out of sync, exit 2 = validation error.
+
+When out of sync, a unified diff from the markdown on disk to what 'chlog sync'
+would write is printed. --format json, sarif or junit write a report for CI
+dashboards instead, and --format github emits ::error annotations, pointing at
+the first differing line of each hunk and at each validation error.
+
+With --changed-since <ref>, check instead that an unreleased entry was added
+since the branch forked from ref, for use in pull request CI. Paths matched by
+require.exclude in .chlog.yaml (or not matched by require.include) need no
+entry, and a skip-changelog label (--labels) or "Skip-Changelog: true" commit
+trailer waives it. Exit 1 = entry missing, exit 2 = git or validation error.
+
+With --immutable-since <ref>, check instead that versions already released at
+that ref are unchanged: no entries added, removed or reworded and no dates
+changed. --allow-edit <version> permits edits to a version, e.g. typo fixes.
+Exit 1 = released version edited. Both flags may be combined.`,
+	RunE: runCheck,
 }
 
 func init() {
 	checkCmd.Flags().BoolVar(&checkInternal, "internal", false, "compare with internal entries included")
 	checkCmd.Flags().BoolVar(&checkSplit, "split", false, "verify both public and internal changelogs")
+	checkCmd.Flags().StringVar(&checkTemplate, "template", "", "render with a custom text/template file")
+	checkCmd.Flags().StringVar(&checkSince, "changed-since", "", "require an unreleased entry added since this git ref")
+	checkCmd.Flags().StringSliceVar(&checkLabels, "labels", nil, "pull request labels, comma-separated (with --changed-since)")
+	checkCmd.Flags().StringVar(&checkImmutable, "immutable-since", "", "fail if versions released at this git ref were edited")
+	checkCmd.Flags().StringSliceVar(&checkAllowEdit, "allow-edit", nil, "released version that may be edited (with --immutable-since, repeatable)")
+	checkCmd.Flags().IntVarP(&checkContext, "context", "U", 3, "context lines in the out-of-sync diff")
+	checkCmd.Flags().StringVar(&checkFormat, "format", "text", "output format: text, json, sarif, junit or github")
 }
 
 func runCheck(cmd *cobra.Command, args []string) error {
-	c, err := changelog.Load(yamlFile)
+	format, err := changelog.ParseReportFormat(checkFormat)
+	if err != nil {
+		return err
+	}
+	if checkImmutable != "" || checkSince != "" {
+		if format != changelog.ReportText {
+			return fmt.Errorf("--format %s does not apply to --immutable-since or --changed-since", format)
+		}
+		return runCheckAgainstRef()
+	}
+
+	c, err := loadChangelog()
 	if err != nil {
-		fmt.Fprintln(os.Stderr, errFmt.Sprintf("validation error: %v", err))
+		if format != changelog.ReportText {
+			finishReport(format, "check", changelog.DiagnosticsFromError(yamlFile, err), 2)
+		}
+		if !printValidationErrors(err) {
+			fmt.Fprintln(os.Stderr, errFmt.Sprintf("validation error: %v", err))
+		}
 		os.Exit(2)
 	}
 
 	cfg := loadConfig()
+	tmpl, err := loadTemplate(checkTemplate, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, errFmt.Sprintf("template error: %v", err))
+		os.Exit(2)
+	}
+	opts := changelog.RenderOptions{Config: cfg, Template: tmpl}
 
+	var diags []changelog.Diagnostic
 	if checkSplit {
-		return runCheckSplit(c, cfg)
+		opts.IncludeInternal = false
+		diags = checkFile(c, opts, cfg.PublicFilePath(), format)
+		opts.IncludeInternal = true
+		diags = append(diags, checkFile(c, opts, cfg.InternalFilePath(), format)...)
+	} else {
+		opts.IncludeInternal = checkInternal || cfg.IncludeInternal
+		diags = checkFile(c, opts, defaultMDFile, format)
 	}
+	finishReport(format, "check", diags, 1)
+	return nil
+}
 
-	internal := checkInternal || cfg.IncludeInternal
-	return checkFile(c, cfg, internal, defaultMDFile)
+// runCheckAgainstRef runs the git-based checks; both may be requested.
+func runCheckAgainstRef() error {
+	if checkImmutable != "" {
+		if err := runCheckImmutable(checkImmutable, checkAllowEdit); err != nil || checkSince == "" {
+			return err
+		}
+	}
+	return runCheckChangedSince(checkSince, checkLabels)
 }
 
-func runCheckSplit(c *changelog.Changelog, cfg *changelog.Config) error {
-	if err := checkFile(c, cfg, false, cfg.PublicFilePath()); err != nil {
-		return err
+// finishReport writes diags in a machine-readable format, if one was chosen,
+// and exits with code when there are any.
+func finishReport(format changelog.ReportFormat, command string, diags []changelog.Diagnostic, code int) {
+	if format != changelog.ReportText {
+		if err := changelog.WriteReport(os.Stdout, format, command, diags); err != nil {
+			fmt.Fprintln(os.Stderr, errFmt.Sprintf("writing report: %v", err))
+			os.Exit(2)
+		}
+	}
+	if len(diags) > 0 {
+		os.Exit(code)
 	}
-	return checkFile(c, cfg, true, cfg.InternalFilePath())
 }
 
-func checkFile(c *changelog.Changelog, cfg *changelog.Config, internal bool, path string) error {
-	rendered, err := changelog.RenderMarkdownString(c, changelog.RenderOptions{
-		IncludeInternal: internal,
-		Config:          cfg,
-	})
+// checkFile compares path with the rendered markdown, printing the result in
+// text format, and returns a diagnostic per differing hunk.
+func checkFile(c *changelog.Changelog, opts changelog.RenderOptions, path string, format changelog.ReportFormat) []changelog.Diagnostic {
+	rendered, err := changelog.RenderMarkdownString(c, opts)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, errFmt.Sprintf("render error: %v", err))
 		os.Exit(2)
 	}
+	text := format == changelog.ReportText
 
 	existing, err := os.ReadFile(path)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, errFmt.Sprintf("%s not found — run 'chlog sync' first", fileRef(path)))
-		os.Exit(1)
+		if text {
+			fmt.Fprintln(os.Stderr, errFmt.Sprintf("%s not found — run 'chlog sync' first", fileRef(path)))
+		}
+		return []changelog.Diagnostic{{Rule: changelog.RuleMissingOutput, File: path, Message: path + " not found — run 'chlog sync' first"}}
+	}
+	if bytes.Equal(existing, []byte(rendered)) {
+		if text {
+			success("%s is in sync", fileRef(path))
+		}
+		return nil
 	}
 
-	if !bytes.Equal(existing, []byte(rendered)) {
-		warn("%s is out of sync — run 'chlog sync'", fileRef(path))
-		os.Exit(1)
+	hunks := changelog.UnifiedDiff(string(existing), rendered, checkContext)
+	if text {
+		printOutOfSync(path, hunks)
+	}
+	msg := fmt.Sprintf("%s is out of sync with %s — run 'chlog sync'", path, yamlFile)
+	if len(hunks) == 0 {
+		return []changelog.Diagnostic{{Rule: changelog.RuleOutOfSync, File: path, Line: 1, Message: msg}}
 	}
+	diags := make([]changelog.Diagnostic, len(hunks))
+	for i, h := range hunks {
+		diags[i] = changelog.Diagnostic{Rule: changelog.RuleOutOfSync, File: path, Line: h.Line, Message: msg}
+	}
+	return diags
+}
 
-	success("%s is in sync", fileRef(path))
-	return nil
+// printOutOfSync prints a colored unified diff from the markdown on disk to
+// the rendered output.
+func printOutOfSync(path string, hunks []changelog.DiffHunk) {
+	warn("%s is out of sync — run 'chlog sync'", fileRef(path))
+	printUnifiedDiff(path, path+" (rendered)", hunks)
+}
+
+// printUnifiedDiff prints hunks as a colored unified diff.
+func printUnifiedDiff(oldName, newName string, hunks []changelog.DiffHunk) {
+	diff := changelog.FormatUnifiedDiff(oldName, newName, hunks)
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		fmt.Print(diffLineColor(line).Sprint(line))
+	}
+}
+
+// diffLineColor picks the color of a unified diff line.
+func diffLineColor(line string) *color.Color {
+	switch {
+	case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		return boldFmt
+	case strings.HasPrefix(line, "@@"):
+		return fileFmt
+	case strings.HasPrefix(line, "+"):
+		return successFmt
+	case strings.HasPrefix(line, "-"):
+		return errFmt
+	}
+	return color.New()
 }
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ariel-frischer/chlog/pkg/changelog"
+)
+
+func TestCheckFile_Diagnostics(t *testing.T) {
+	initReleaseRepo(t)
+	c := loadTestChangelog(t, yamlFile)
+	opts := changelog.RenderOptions{Config: loadConfig()}
+	rendered, err := changelog.RenderMarkdownString(c, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		content string
+		rule    string
+	}{
+		"in sync":      {content: rendered},
+		"out of sync":  {content: rendered + "stale\n", rule: changelog.RuleOutOfSync},
+		"missing file": {rule: changelog.RuleMissingOutput},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := strings.ReplaceAll(name, " ", "-") + ".md"
+			if tt.content != "" {
+				writeFile(t, path, tt.content)
+			}
+			diags := checkFile(c, opts, path, changelog.ReportJSON)
+			if tt.rule == "" {
+				if len(diags) > 0 {
+					t.Errorf("unexpected diagnostics: %v", diags)
+				}
+				return
+			}
+			if len(diags) != 1 || diags[0].Rule != tt.rule || diags[0].File != path {
+				t.Errorf("got %v, want one %s diagnostic", diags, tt.rule)
+			}
+		})
+	}
+}
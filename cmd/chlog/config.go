@@ -1,9 +1,12 @@
 package main
 
 import (
+	"cmp"
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -40,7 +43,35 @@ var configSetCmd = &cobra.Command{
   internal_file     Output path for internal CHANGELOG (default: CHANGELOG-internal.md)
   include_internal  Include internal entries in public output (true/false)
   strict_categories Enforce allowed categories (true/false)
-  categories        Comma-separated list of allowed categories`,
+  categories        Comma-separated list of allowed categories
+  template          Path to a text/template file for sync, check and extract
+  version_scheme    semver, calver or none: enforce version syntax and order
+  bump_rules        Comma-separated category=level overrides for chlog bump (e.g. removed=minor)
+  tag_prefix        Prefix for release tags (default: v; "" for bare tags)
+  commit_message    text/template for release --commit, with .Version and .Tag
+  forge             github, gitlab, bitbucket, gitea, azure or custom (default: detect from repo_url)
+  compare_url_template  text/template for compare links, with .RepoURL, .From and .To
+  fragments         Read unreleased entries from fragment files and add to them by default (true/false)
+  fragment_dir      Directory of fragment files (default: .chlog/unreleased)
+  require.include   Comma-separated globs of paths that need a changelog entry (default: all)
+  require.exclude   Comma-separated globs of paths exempt from needing an entry
+  require.skip_label  PR label and commit trailer that waive the entry (default: skip-changelog)
+  lint.disable      Comma-separated lint rule IDs to turn off (e.g. tense,min-length)
+  lint.max_length   Maximum entry length for chlog lint (default: 200)
+  lint.min_length   Minimum entry length for chlog lint (default: 10)
+  lint.punctuation  Trailing punctuation policy: none, period or any (default: none)
+  lint.tense        Entry tense: past, imperative or any (default: any)
+  lint.banned       Comma-separated regular expressions entries must not match
+  packaging.name    Package name for sync --format debian|rpm (default: project)
+  packaging.maintainer  "Full Name <email>" for the packaging changelogs (required by them)
+  packaging.revision    Debian revision / RPM release appended to versions (default: 1)
+  packaging.distribution  Debian distribution (default: unstable)
+  packaging.urgency     Debian urgency: low, medium, high, emergency or critical (default: medium)
+  packaging.debian_file Output path of sync --format debian (default: debian/changelog)
+  packaging.rpm_file    Spec file whose %changelog sync --format rpm replaces (default: <name>.spec)
+  store.order       Comma-separated category priority for store notes (default: categories)
+  store.max_chars   Length limit of extract --format store (default: 4000)
+  store.locales     Comma-separated locales for export fastlane (default: existing locale directories or en-US)`,
 	Args: cobra.ExactArgs(2),
 	RunE: runConfigSet,
 }
@@ -78,7 +109,19 @@ func runConfigInit(cmd *cobra.Command, args []string) error {
 		"# internal_file: CHANGELOG-internal.md\n" +
 		"# include_internal: false\n" +
 		"# categories: [added, changed, deprecated, removed, fixed, security]\n" +
-		"# strict_categories: true\n"
+		"# strict_categories: true\n" +
+		"# template: CHANGELOG.md.tmpl\n" +
+		"# version_scheme: semver\n" +
+		"# bump_rules: {removed: minor}\n" +
+		"# tag_prefix: v\n" +
+		"# commit_message: \"release: {{.Tag}}\"\n" +
+		"# forge: github\n" +
+		"# compare_url_template: \"{{.RepoURL}}/compare/{{.From}}...{{.To}}\"\n" +
+		"# fragments: false\n" +
+		"# fragment_dir: .chlog/unreleased\n" +
+		"# require:\n" +
+		"#   exclude: [\"docs/**\", \"*.md\", \"*_test.go\"]\n" +
+		"#   skip_label: skip-changelog\n"
 
 	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
 		return fmt.Errorf("writing %s: %w", path, err)
@@ -125,11 +168,127 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 	printConfigRow("include_internal", fmt.Sprintf("%v", cfg.IncludeInternal), sourceLabel(cfg.IncludeInternal))
 	printConfigRow("strict_categories", strictStr, strictSource)
 	printConfigRow("categories", catStr, sourceLabel(len(cfg.Categories) > 0))
+	templateStr := cfg.Template
+	if templateStr == "" {
+		templateStr = "(built-in)"
+	}
+	printConfigRow("template", templateStr, sourceLabel(cfg.Template != ""))
+	schemeStr := cfg.VersionScheme
+	if schemeStr == "" {
+		schemeStr = string(changelog.SchemeNone)
+	}
+	printConfigRow("version_scheme", schemeStr, sourceLabel(cfg.VersionScheme != ""))
+	printConfigRow("bump_rules", formatBumpRules(cfg.BumpRules), sourceLabel(len(cfg.BumpRules) > 0))
+	tagPrefix := changelog.DefaultTagPrefix
+	if cfg.TagPrefix != nil {
+		tagPrefix = fmt.Sprintf("%q", *cfg.TagPrefix)
+	}
+	printConfigRow("tag_prefix", tagPrefix, sourceLabel(cfg.TagPrefix != nil))
+	commitMsg := cfg.CommitMessage
+	if commitMsg == "" {
+		commitMsg = changelog.DefaultCommitMessage
+	}
+	printConfigRow("commit_message", commitMsg, sourceLabel(cfg.CommitMessage != ""))
+	forge, forgeSource := cfg.Forge, "file"
+	if forge == "" {
+		forge, forgeSource = string(changelog.DetectForge(changelog.ResolveRepoURL(cfg))), "detected"
+	}
+	printConfigRow("forge", forge, forgeSource)
+	compareTmpl := cfg.CompareURLTemplate
+	if compareTmpl == "" {
+		compareTmpl = "(built-in)"
+	}
+	printConfigRow("compare_url_template", compareTmpl, sourceLabel(cfg.CompareURLTemplate != ""))
+	printConfigRow("fragments", fmt.Sprintf("%v", cfg.Fragments), sourceLabel(cfg.Fragments))
+	printConfigRow("fragment_dir", cfg.FragmentDirPath(), sourceLabel(cfg.FragmentDir != ""))
+	printConfigRow("require.include", globList(cfg.Require.Include, "(all paths)"), sourceLabel(len(cfg.Require.Include) > 0))
+	printConfigRow("require.exclude", globList(cfg.Require.Exclude, "(none)"), sourceLabel(len(cfg.Require.Exclude) > 0))
+	printConfigRow("require.skip_label", cfg.Require.SkipLabelName(), sourceLabel(cfg.Require.SkipLabel != ""))
+	printConfigRow("lint.disable", globList(cfg.Lint.Disable, "(none)"), sourceLabel(len(cfg.Lint.Disable) > 0))
+	printConfigRow("lint.max_length", strconv.Itoa(cmp.Or(cfg.Lint.MaxLength, changelog.DefaultLintMaxLength)), sourceLabel(cfg.Lint.MaxLength != 0))
+	printConfigRow("lint.min_length", strconv.Itoa(cmp.Or(cfg.Lint.MinLength, changelog.DefaultLintMinLength)), sourceLabel(cfg.Lint.MinLength != 0))
+	printConfigRow("lint.punctuation", cmp.Or(cfg.Lint.Punctuation, changelog.PunctuationNone), sourceLabel(cfg.Lint.Punctuation != ""))
+	printConfigRow("lint.tense", cmp.Or(cfg.Lint.Tense, changelog.TenseAny), sourceLabel(cfg.Lint.Tense != ""))
+	printConfigRow("lint.banned", globList(cfg.Lint.Banned, "(none)"), sourceLabel(len(cfg.Lint.Banned) > 0))
+	pkg := cfg.Packaging
+	project := "(project)"
+	if c, err := loadChangelog(); err == nil && c.Project != "" {
+		project = c.Project
+	}
+	printConfigRow("packaging.name", pkg.PackageName(project), sourceLabel(pkg.Name != ""))
+	printConfigRow("packaging.maintainer", cmp.Or(pkg.Maintainer, "(not set)"), sourceLabel(pkg.Maintainer != ""))
+	printConfigRow("packaging.revision", cmp.Or(pkg.Revision, changelog.DefaultPackageRevision), sourceLabel(pkg.Revision != ""))
+	printConfigRow("packaging.distribution", cmp.Or(pkg.Distribution, changelog.DefaultDebianDistribution), sourceLabel(pkg.Distribution != ""))
+	printConfigRow("packaging.urgency", cmp.Or(pkg.Urgency, changelog.DefaultDebianUrgency), sourceLabel(pkg.Urgency != ""))
+	printConfigRow("packaging.debian_file", pkg.DebianFilePath(), sourceLabel(pkg.DebianFile != ""))
+	printConfigRow("packaging.rpm_file", pkg.RPMFilePath(project), sourceLabel(pkg.RPMFile != ""))
+	store := cfg.StoreOptions(0)
+	printConfigRow("store.order", strings.Join(store.Order, ", "), sourceLabel(len(cfg.Store.Order) > 0))
+	printConfigRow("store.max_chars", strconv.Itoa(store.MaxChars), sourceLabel(cfg.Store.MaxChars != 0))
+	printConfigRow("store.locales", globList(cfg.Store.Locales, "(detect)"), sourceLabel(len(cfg.Store.Locales) > 0))
 	return nil
 }
 
+// parseBumpRules parses "category=level,..." into a bump_rules map.
+func parseBumpRules(value string) (map[string]string, error) {
+	rules := map[string]string{}
+	for _, part := range strings.Split(value, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		cat, level, ok := strings.Cut(part, "=")
+		cat, level = strings.TrimSpace(cat), strings.TrimSpace(level)
+		if !ok || cat == "" {
+			return nil, fmt.Errorf("bump_rules expects category=level pairs, got %q", part)
+		}
+		if _, err := changelog.ParseBumpLevel(level); err != nil {
+			return nil, err
+		}
+		rules[cat] = level
+	}
+	return rules, nil
+}
+
+// splitList splits a comma-separated value, dropping blank items.
+func splitList(value string) []string {
+	parts := strings.Split(value, ",")
+	items := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if t := strings.TrimSpace(p); t != "" {
+			items = append(items, t)
+		}
+	}
+	return items
+}
+
+func globList(globs []string, empty string) string {
+	if len(globs) == 0 {
+		return empty
+	}
+	return strings.Join(globs, ", ")
+}
+
+func formatBumpRules(rules map[string]string) string {
+	if len(rules) == 0 {
+		return "(defaults)"
+	}
+	parts := make([]string, 0, len(rules))
+	for _, cat := range slices.Sorted(maps.Keys(rules)) {
+		parts = append(parts, cat+"="+rules[cat])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// configKeyWidth fits the longest key, "packaging.distribution:".
+const configKeyWidth = 24
+
 func printConfigRow(key, value, source string) {
-	fmt.Printf("%-20s %-40s (%s)\n", highlight(key+":")+" ", value, source)
+	fmt.Println(configRow(key, value, source))
+}
+
+func configRow(key, value, source string) string {
+	// Pad before coloring: escape codes would count towards the width.
+	return fmt.Sprintf("%s %-40s (%s)", highlight(fmt.Sprintf("%-*s", configKeyWidth, key+":")), value, source)
 }
 
 func sourceLabel(isCustom bool) string {
@@ -169,16 +328,89 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 		}
 		cfg.StrictCategories = &b
 	case "categories":
-		parts := strings.Split(value, ",")
-		cats := make([]string, 0, len(parts))
-		for _, p := range parts {
-			if t := strings.TrimSpace(p); t != "" {
-				cats = append(cats, t)
-			}
+		cfg.Categories = splitList(value)
+	case "template":
+		cfg.Template = value
+	case "version_scheme":
+		if _, err := changelog.ParseVersionScheme(value); err != nil {
+			return err
+		}
+		cfg.VersionScheme = value
+	case "bump_rules":
+		rules, err := parseBumpRules(value)
+		if err != nil {
+			return err
+		}
+		cfg.BumpRules = rules
+	case "tag_prefix":
+		cfg.TagPrefix = &value
+	case "commit_message":
+		cfg.CommitMessage = value
+	case "forge":
+		cfg.Forge = value
+	case "compare_url_template":
+		cfg.CompareURLTemplate = value
+	case "fragments":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("fragments expects true/false, got %q", value)
+		}
+		cfg.Fragments = b
+	case "fragment_dir":
+		cfg.FragmentDir = value
+	case "require.include":
+		cfg.Require.Include = splitList(value)
+	case "require.exclude":
+		cfg.Require.Exclude = splitList(value)
+	case "require.skip_label":
+		cfg.Require.SkipLabel = value
+	case "lint.disable":
+		cfg.Lint.Disable = splitList(value)
+	case "lint.max_length", "lint.min_length":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s expects a number, got %q", key, value)
+		}
+		if key == "lint.max_length" {
+			cfg.Lint.MaxLength = n
+		} else {
+			cfg.Lint.MinLength = n
+		}
+	case "lint.punctuation":
+		cfg.Lint.Punctuation = value
+	case "lint.tense":
+		cfg.Lint.Tense = value
+	case "lint.banned":
+		cfg.Lint.Banned = splitList(value)
+	case "packaging.name":
+		cfg.Packaging.Name = value
+	case "packaging.maintainer":
+		cfg.Packaging.Maintainer = value
+	case "packaging.revision":
+		cfg.Packaging.Revision = value
+	case "packaging.distribution":
+		cfg.Packaging.Distribution = value
+	case "packaging.urgency":
+		cfg.Packaging.Urgency = value
+	case "packaging.debian_file":
+		cfg.Packaging.DebianFile = value
+	case "packaging.rpm_file":
+		cfg.Packaging.RPMFile = value
+	case "store.order":
+		cfg.Store.Order = splitList(value)
+	case "store.max_chars":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s expects a number, got %q", key, value)
 		}
-		cfg.Categories = cats
+		cfg.Store.MaxChars = n
+	case "store.locales":
+		cfg.Store.Locales = splitList(value)
 	default:
-		return fmt.Errorf("unknown key %q\nvalid keys: repo_url, changelog_file, public_file, internal_file, include_internal, strict_categories, categories", key)
+		return fmt.Errorf("unknown key %q\nvalid keys: repo_url, changelog_file, public_file, internal_file, include_internal, strict_categories, categories, template, version_scheme, bump_rules, tag_prefix, commit_message, forge, compare_url_template, fragments, fragment_dir, require.include, require.exclude, require.skip_label, lint.disable, lint.max_length, lint.min_length, lint.punctuation, lint.tense, lint.banned, packaging.name, packaging.maintainer, packaging.revision, packaging.distribution, packaging.urgency, packaging.debian_file, packaging.rpm_file, store.order, store.max_chars, store.locales", key)
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
 	}
 
 	if err := changelog.SaveConfig(cfg, configFile); err != nil {
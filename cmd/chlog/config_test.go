@@ -3,10 +3,12 @@ package main
 import (
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/ariel-frischer/chlog/pkg/changelog"
+	"github.com/fatih/color"
 )
 
 func TestRunConfigInit_CreatesFile(t *testing.T) {
@@ -154,6 +156,110 @@ func TestRunConfigSet(t *testing.T) {
 				}
 			},
 		},
+		"version_scheme": {
+			key: "version_scheme", value: "semver",
+			check: func(t *testing.T, c *changelog.Config) {
+				if c.VersionScheme != "semver" {
+					t.Errorf("VersionScheme = %q", c.VersionScheme)
+				}
+			},
+		},
+		"bump_rules": {
+			key: "bump_rules", value: "removed=minor, perf=patch",
+			check: func(t *testing.T, c *changelog.Config) {
+				if len(c.BumpRules) != 2 || c.BumpRules["removed"] != "minor" || c.BumpRules["perf"] != "patch" {
+					t.Errorf("BumpRules = %v", c.BumpRules)
+				}
+			},
+		},
+		"tag_prefix empty": {
+			key: "tag_prefix", value: "",
+			check: func(t *testing.T, c *changelog.Config) {
+				if c.TagPrefix == nil || *c.TagPrefix != "" {
+					t.Errorf("TagPrefix = %v, want explicit empty", c.TagPrefix)
+				}
+			},
+		},
+		"version_scheme bad value": {
+			key: "version_scheme", value: "romver",
+			wantErr: true,
+		},
+		"bump_rules bad level": {
+			key: "bump_rules", value: "removed=huge",
+			wantErr: true,
+		},
+		"forge": {
+			key: "forge", value: "bitbucket",
+			check: func(t *testing.T, c *changelog.Config) {
+				if c.Forge != "bitbucket" {
+					t.Errorf("Forge = %q", c.Forge)
+				}
+			},
+		},
+		"forge custom without template": {
+			key: "forge", value: "custom",
+			wantErr: true,
+		},
+		"compare_url_template bad template": {
+			key: "compare_url_template", value: "{{.RepoURL",
+			wantErr: true,
+		},
+		"lint.disable": {
+			key: "lint.disable", value: "tense, min-length",
+			check: func(t *testing.T, c *changelog.Config) {
+				if strings.Join(c.Lint.Disable, ",") != "tense,min-length" {
+					t.Errorf("Lint.Disable = %v", c.Lint.Disable)
+				}
+			},
+		},
+		"lint.max_length": {
+			key: "lint.max_length", value: "120",
+			check: func(t *testing.T, c *changelog.Config) {
+				if c.Lint.MaxLength != 120 {
+					t.Errorf("Lint.MaxLength = %d, want 120", c.Lint.MaxLength)
+				}
+			},
+		},
+		"lint.disable unknown rule": {
+			key: "lint.disable", value: "spelling",
+			wantErr: true,
+		},
+		"lint.tense bad value": {
+			key: "lint.tense", value: "future",
+			wantErr: true,
+		},
+		"packaging.maintainer": {
+			key: "packaging.maintainer", value: "Jane Doe <jane@example.com>",
+			check: func(t *testing.T, c *changelog.Config) {
+				if c.Packaging.Maintainer != "Jane Doe <jane@example.com>" {
+					t.Errorf("Packaging.Maintainer = %q", c.Packaging.Maintainer)
+				}
+			},
+		},
+		"packaging.maintainer without email": {
+			key: "packaging.maintainer", value: "Jane Doe",
+			wantErr: true,
+		},
+		"packaging.urgency bad value": {
+			key: "packaging.urgency", value: "urgent",
+			wantErr: true,
+		},
+		"store.locales": {
+			key: "store.locales", value: "en-US, de-DE",
+			check: func(t *testing.T, c *changelog.Config) {
+				if strings.Join(c.Store.Locales, ",") != "en-US,de-DE" {
+					t.Errorf("Store.Locales = %v", c.Store.Locales)
+				}
+			},
+		},
+		"store.locales bad value": {
+			key: "store.locales", value: "../en",
+			wantErr: true,
+		},
+		"store.max_chars negative": {
+			key: "store.max_chars", value: "-1",
+			wantErr: true,
+		},
 		"unknown key": {
 			key: "bad_key", value: "whatever",
 			wantErr: true,
@@ -240,3 +346,22 @@ func TestRunConfigShow_NoError(t *testing.T) {
 		t.Fatalf("unexpected error with config file: %v", err)
 	}
 }
+
+func TestConfigRow_AlignsColoredKeys(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	t.Cleanup(func() { color.NoColor = noColor })
+
+	ansi := regexp.MustCompile(`\x1b\[[0-9;]*m`)
+	var columns []int
+	for _, key := range []string{"fragments", "compare_url_template", "packaging.distribution"} {
+		row := configRow(key, "value", "file")
+		if !ansi.MatchString(row) {
+			t.Fatalf("row not colored: %q", row)
+		}
+		columns = append(columns, strings.Index(ansi.ReplaceAllString(row, ""), "value"))
+	}
+	if columns[0] != columns[1] || columns[1] != columns[2] {
+		t.Errorf("value columns = %v, want them aligned", columns)
+	}
+}
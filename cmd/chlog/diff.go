@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ariel-frischer/chlog/pkg/changelog"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffFormat   string
+	diffPlain    bool
+	diffInternal bool
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [<ref-or-file> [<ref-or-file>]]",
+	Short: "Show entries added, removed and modified between two changelogs",
+	Long: `Compare two versions of CHANGELOG.yaml entry by entry. Each side is a file
+path or a git revision, read from that revision's CHANGELOG.yaml (and
+fragments, when enabled):
+
+  chlog diff                   HEAD vs the working tree
+  chlog diff main              main vs the working tree
+  chlog diff v1.3.0 v1.4.0     what changed between two tags
+  chlog diff old.yaml new.yaml two files`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().StringVar(&diffFormat, "format", "text", "output format: text, markdown or json")
+	diffCmd.Flags().BoolVar(&diffPlain, "plain", false, "disable colors")
+	diffCmd.Flags().BoolVar(&diffInternal, "internal", false, "include internal entries")
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	from, to := "HEAD", ""
+	switch len(args) {
+	case 1:
+		from = args[0]
+	case 2:
+		from, to = args[0], args[1]
+	}
+	old, err := loadDiffSide(from)
+	if err != nil {
+		return err
+	}
+	updated, err := loadDiffSide(to)
+	if err != nil {
+		return err
+	}
+
+	d := changelog.Diff(old, updated)
+	if !diffInternal && !loadConfig().IncludeInternal {
+		d = d.Public()
+	}
+	switch diffFormat {
+	case "text":
+		fmt.Print(changelog.FormatDiff(d, changelog.FormatOptions{Plain: diffPlain}))
+	case "markdown":
+		fmt.Print(changelog.RenderDiffMarkdown(d))
+	case "json":
+		return printJSON(d)
+	default:
+		return fmt.Errorf("unknown format %q (valid: text, markdown, json)", diffFormat)
+	}
+	return nil
+}
+
+// loadDiffSide loads one side of a diff: the working tree for "", a file if
+// one exists at arg, otherwise a git revision.
+func loadDiffSide(arg string) (*changelog.Changelog, error) {
+	if arg == "" {
+		return loadChangelog()
+	}
+	cfg := loadConfig()
+	if info, err := os.Stat(arg); err == nil && !info.IsDir() {
+		return loadMergeSide(arg, cfg)
+	}
+	if !changelog.RevisionExists(arg) {
+		return nil, fmt.Errorf("%s is neither a file nor a git revision", arg)
+	}
+	return loadChangelogAt(arg, cfg)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ariel-frischer/chlog/pkg/changelog"
+)
+
+func TestLoadDiffSide(t *testing.T) {
+	initReleaseRepo(t)
+	c := loadTestChangelog(t, yamlFile)
+	c.GetUnreleased().Public.Append("fixed", "Crash on start")
+	writeTestChangelog(t, yamlFile, c)
+	writeTestChangelog(t, "other.yaml", &changelog.Changelog{Project: "demo"})
+
+	tests := map[string]struct {
+		arg     string
+		entries int
+		wantErr bool
+	}{
+		"working tree": {arg: "", entries: 2},
+		"revision":     {arg: "HEAD", entries: 1},
+		"file":         {arg: "other.yaml", entries: 0},
+		"unknown":      {arg: "no-such-ref", wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			side, err := loadDiffSide(tt.arg)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := side.GetEntryCount(); got != tt.entries {
+				t.Errorf("entries = %d, want %d", got, tt.entries)
+			}
+		})
+	}
+}
+
+func TestRunDiff_UnknownFormat(t *testing.T) {
+	initReleaseRepo(t)
+	diffFormat = "yaml"
+	defer func() { diffFormat = "text" }()
+	if err := runDiff(nil, nil); err == nil {
+		t.Fatal("expected an error for an unknown format")
+	}
+}
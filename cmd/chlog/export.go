@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ariel-frischer/chlog/pkg/changelog"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportDir      string
+	exportBuild    string
+	exportLocales  []string
+	exportMaxChars int
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write release notes into another tool's file layout",
+}
+
+var exportFastlaneCmd = &cobra.Command{
+	Use:   "fastlane <version>",
+	Short: "Write fastlane changelogs/<build>.txt files per locale",
+	Long: `Write the store notes of a version (see extract --format store) to
+<dir>/<locale>/changelogs/<build>.txt, the layout fastlane supply uploads as
+Google Play release notes.
+
+Locales are --locale, else store.locales in .chlog.yaml, else the locale
+directories already in --dir, else en-US. Without --build the notes go to
+changelogs/default.txt, which fastlane uses for any build. --max-chars defaults
+to 500, the Google Play limit.`,
+	Example: `  chlog export fastlane 1.2.0 --build 10203
+  chlog export fastlane 1.2.0 --dir fastlane/metadata/android --locale en-US --locale de-DE`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExportFastlane,
+}
+
+func init() {
+	exportFastlaneCmd.Flags().StringVar(&exportDir, "dir", "fastlane/metadata", "fastlane metadata directory")
+	exportFastlaneCmd.Flags().StringVar(&exportBuild, "build", changelog.DefaultFastlaneBuild, "build number (version code) naming the file")
+	exportFastlaneCmd.Flags().StringSliceVar(&exportLocales, "locale", nil, "locale to write, repeatable (default: store.locales, existing locale directories or en-US)")
+	exportFastlaneCmd.Flags().IntVar(&exportMaxChars, "max-chars", changelog.PlayStoreMaxChars, "length limit of the notes")
+	exportCmd.AddCommand(exportFastlaneCmd)
+}
+
+func runExportFastlane(cmd *cobra.Command, args []string) error {
+	if exportMaxChars < 0 {
+		return fmt.Errorf("--max-chars must not be negative")
+	}
+	c, err := loadChangelog()
+	if err != nil {
+		return err
+	}
+	v, err := c.GetVersion(args[0])
+	if err != nil {
+		return err
+	}
+	cfg := loadConfig()
+	locales, err := fastlaneLocales(cfg)
+	if err != nil {
+		return err
+	}
+
+	notes := changelog.RenderStoreNotes(v, cfg.StoreOptions(exportMaxChars))
+	paths, err := changelog.WriteFastlaneChangelogs(exportDir, exportBuild, locales, notes)
+	for _, p := range paths {
+		success("Wrote %s", fileRef(p))
+	}
+	return err
+}
+
+// fastlaneLocales returns the --locale flags, store.locales, the locale
+// directories in --dir or DefaultFastlaneLocale, whichever is set first.
+func fastlaneLocales(cfg *changelog.Config) ([]string, error) {
+	if len(exportLocales) > 0 {
+		return exportLocales, nil
+	}
+	if len(cfg.Store.Locales) > 0 {
+		return cfg.Store.Locales, nil
+	}
+	found, err := changelog.FastlaneLocales(exportDir)
+	if err != nil || len(found) > 0 {
+		return found, err
+	}
+	return []string{changelog.DefaultFastlaneLocale}, nil
+}
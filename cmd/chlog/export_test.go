@@ -0,0 +1,29 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunExportFastlane(t *testing.T) {
+	initReleaseRepo(t)
+	writeFile(t, yamlFile, "project: demo\nversions:\n  1.0.0:\n    date: \"2026-03-02\"\n    fixed:\n      - Crash on start\n    added:\n      - Login page\n")
+	writeFile(t, configFile, "store:\n  order: [fixed]\n")
+	if err := os.MkdirAll(filepath.Join("meta", "de-DE"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	exportDir, exportBuild = "meta", "7"
+	t.Cleanup(func() { exportDir, exportBuild = "fastlane/metadata", "default" })
+	if err := runExportFastlane(nil, []string{"1.0.0"}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join("meta", "de-DE", "changelogs", "7.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "• Crash on start\n• Login page"; string(data) != want {
+		t.Errorf("got %q, want %q", data, want)
+	}
+}
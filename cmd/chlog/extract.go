@@ -1,38 +1,86 @@
 package main
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/ariel-frischer/chlog/pkg/changelog"
 	"github.com/spf13/cobra"
 )
 
-var extractInternal bool
+var (
+	extractInternal bool
+	extractTemplate string
+	extractFormat   string
+	extractPre      bool
+	extractMaxChars int
+)
 
 var extractCmd = &cobra.Command{
 	Use:   "extract <version>",
 	Short: "Extract a single version as markdown",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runExtract,
+	Long: `Extract a single version as markdown, for release notes.
+
+--format json prints the version as JSON. --format store prints its public
+entries as plain text for app store "What's New" fields: one line per entry,
+categories in store.order priority, cut at whole entries with a final "…"
+line to stay within --max-chars (default: store.max_chars or 4000, the App
+Store limit; Google Play allows 500).`,
+	Example: `  chlog extract 1.2.0 > notes.md
+  chlog extract 1.2.0 --format json
+  chlog extract 1.2.0 --format store --max-chars 500`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExtract,
 }
 
 func init() {
 	extractCmd.Flags().BoolVar(&extractInternal, "internal", false, "include internal entries")
+	extractCmd.Flags().StringVar(&extractTemplate, "template", "", "render with the \"version\" block of a custom template file")
+	extractCmd.Flags().BoolVar(&extractPre, "with-prereleases", false, "append the notes of the version's pre-releases (e.g. 2.0.0-rc.*)")
+	extractCmd.Flags().StringVar(&extractFormat, "format", "markdown", "output format: markdown, json or store")
+	extractCmd.Flags().IntVar(&extractMaxChars, "max-chars", 0, "length limit of --format store (default: store.max_chars or 4000)")
 }
 
 func runExtract(cmd *cobra.Command, args []string) error {
-	c, err := changelog.Load(yamlFile)
+	c, err := loadChangelog()
 	if err != nil {
 		return err
 	}
 
-	v, err := c.GetVersion(args[0])
+	var v *changelog.Version
+	if extractPre {
+		v, err = c.WithPrereleases(args[0])
+	} else {
+		v, err = c.GetVersion(args[0])
+	}
 	if err != nil {
 		return err
 	}
 
 	cfg := loadConfig()
 	internal := extractInternal || cfg.IncludeInternal
+	switch extractFormat {
+	case "markdown":
+	case "json":
+		return printJSON(changelog.NewJSONVersion(v, changelog.QueryOptions{IncludeInternal: internal}))
+	case "store":
+		if extractMaxChars < 0 {
+			return fmt.Errorf("--max-chars must not be negative")
+		}
+		fmt.Println(changelog.RenderStoreNotes(v, cfg.StoreOptions(extractMaxChars)))
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q (valid: markdown, json, store)", extractFormat)
+	}
+
+	tmpl, err := loadTemplate(extractTemplate, cfg)
+	if err != nil {
+		return err
+	}
 
-	return changelog.RenderVersionMarkdown(v, os.Stdout, changelog.RenderOptions{IncludeInternal: internal})
+	return changelog.RenderVersionMarkdown(v, os.Stdout, changelog.RenderOptions{
+		IncludeInternal: internal,
+		Config:          cfg,
+		Template:        tmpl,
+	})
 }
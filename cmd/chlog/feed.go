@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ariel-frischer/chlog/pkg/changelog"
+	"github.com/spf13/cobra"
+)
+
+var (
+	feedFormat   string
+	feedBaseURL  string
+	feedTitle    string
+	feedAuthor   string
+	feedMaxItems int
+)
+
+var feedCmd = &cobra.Command{
+	Use:   "feed",
+	Short: "Print an Atom, RSS or JSON feed of releases",
+	Long: `Print a feed with one item per released version, newest first as in
+CHANGELOG.yaml; unreleased is skipped. Each item is titled with the version,
+dated with its release date and carries the public entries as HTML.
+
+--base-url is the published changelog, e.g. the page written by
+chlog sync --format html. Items link to its version anchors (#v1.2.0), and
+those links are their IDs, so they stay stable across runs.`,
+	Example: `  chlog feed --base-url https://example.com/changelog.html > releases.atom
+  chlog feed --format rss --base-url https://example.com/changelog --max-items 10
+  chlog feed --format jsonfeed --base-url https://example.com/changelog`,
+	Args: cobra.NoArgs,
+	RunE: runFeed,
+}
+
+func init() {
+	feedCmd.Flags().StringVar(&feedFormat, "format", "atom", "feed format: atom, rss or jsonfeed")
+	feedCmd.Flags().StringVar(&feedBaseURL, "base-url", "", "absolute URL of the published changelog (required)")
+	feedCmd.Flags().StringVar(&feedTitle, "title", "", "feed title (default \"<project> releases\")")
+	feedCmd.Flags().StringVar(&feedAuthor, "author", "", "feed author name (default: the project)")
+	feedCmd.Flags().IntVar(&feedMaxItems, "max-items", 20, "newest releases to include (0 = all)")
+}
+
+func runFeed(cmd *cobra.Command, args []string) error {
+	format, err := changelog.ParseFeedFormat(feedFormat)
+	if err != nil {
+		return err
+	}
+	if feedBaseURL == "" {
+		return fmt.Errorf("--base-url is required")
+	}
+	c, err := loadChangelog()
+	if err != nil {
+		return err
+	}
+	return changelog.RenderFeed(c, os.Stdout, changelog.FeedOptions{
+		Format:   format,
+		BaseURL:  feedBaseURL,
+		Title:    feedTitle,
+		Author:   feedAuthor,
+		MaxItems: feedMaxItems,
+		Config:   loadConfig(),
+	})
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/ariel-frischer/chlog/pkg/changelog"
+	"github.com/spf13/cobra"
+)
+
+var fmtCheck bool
+
+var fmtCmd = &cobra.Command{
+	Use:   "fmt",
+	Short: "Rewrite CHANGELOG.yaml in canonical form",
+	Long: `Rewrite CHANGELOG.yaml in canonical form, like gofmt for changelogs:
+categories in the configured order (categories in .chlog.yaml, or the Keep a
+Changelog order), entries trimmed and quoted only where needed, version keys
+quoted only when YAML would misread them, and date first and internal last in
+each version. Comments and blank lines are kept; version order is left to
+'chlog sort'.
+
+With --check, nothing is written: a diff is printed and the exit code is 1
+when the file is not formatted.`,
+	Example: `  chlog fmt
+  chlog fmt --check`,
+	Args: cobra.NoArgs,
+	RunE: runFmt,
+}
+
+func init() {
+	fmtCmd.Flags().BoolVar(&fmtCheck, "check", false, "report unformatted files with a diff instead of rewriting them")
+}
+
+func runFmt(cmd *cobra.Command, args []string) error {
+	raw, err := os.ReadFile(yamlFile)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", yamlFile, err)
+	}
+	// Fragments are separate files; formatting must not fold them in.
+	cfg := *loadConfig()
+	cfg.Fragments = false
+	c, err := changelog.LoadFromReader(bytes.NewReader(raw), &cfg)
+	if err != nil {
+		return err
+	}
+	out, err := changelog.FormatYAML(c, &cfg)
+	if err != nil {
+		return fmt.Errorf("formatting %s: %w", yamlFile, err)
+	}
+
+	if bytes.Equal(raw, out) {
+		success("%s is formatted", fileRef(yamlFile))
+		return nil
+	}
+	if fmtCheck {
+		warn("%s is not formatted — run 'chlog fmt'", fileRef(yamlFile))
+		printUnifiedDiff(yamlFile, yamlFile+" (formatted)", changelog.UnifiedDiff(string(raw), string(out), 3))
+		os.Exit(1)
+	}
+	if err := os.WriteFile(yamlFile, out, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", yamlFile, err)
+	}
+	success("Formatted %s", fileRef(yamlFile))
+	return nil
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestRunFmt_KeepsFragmentsOut(t *testing.T) {
+	initReleaseRepo(t)
+	writeFile(t, configFile, "fragments: true\n")
+	writeFile(t, yamlFile, "project: demo\nversions:\n  unreleased:\n    fixed:\n      - ' Crash '\n    added:\n      - Login page\n")
+	writeFile(t, ".chlog/unreleased/feat.yaml", "added:\n  - Dark mode\n")
+
+	if err := runFmt(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(yamlFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "project: demo\nversions:\n  unreleased:\n    added:\n      - Login page\n    fixed:\n      - Crash\n"
+	if string(data) != want {
+		t.Errorf("got\n%s\nwant\n%s", data, want)
+	}
+}
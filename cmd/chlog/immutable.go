@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/ariel-frischer/chlog/pkg/changelog"
+)
+
+// runCheckImmutable implements `check --immutable-since`: exit 1 when a
+// version released at ref was edited and not allowed by --allow-edit.
+func runCheckImmutable(ref string, allowed []string) error {
+	edits, err := releaseEdits(ref, allowed)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, errFmt.Sprintf("error: %v", err))
+		os.Exit(2)
+	}
+	if len(edits) == 0 {
+		success("Released versions are unchanged since %s", ref)
+		return nil
+	}
+
+	fmt.Fprintln(os.Stderr, errFmt.Sprintf("%d edit(s) to released versions since %s:", len(edits), ref))
+	for _, e := range edits {
+		fmt.Fprintf(os.Stderr, "  %s\n", e)
+	}
+	fmt.Fprintln(os.Stderr, "Released versions are immutable — move the change to unreleased, or pass --allow-edit <version> for an intended fix")
+	os.Exit(1)
+	return nil
+}
+
+// releaseEdits diffs the released versions at the merge base of ref against
+// the working tree, dropping edits to allowed versions.
+func releaseEdits(ref string, allowed []string) ([]changelog.ReleaseEdit, error) {
+	base, err := changelog.MergeBase(ref)
+	if err != nil {
+		return nil, err
+	}
+	cfg := loadConfig()
+	old, err := loadChangelogAt(base, cfg)
+	if err != nil {
+		return nil, err
+	}
+	head, err := loadChangelog()
+	if err != nil {
+		return nil, err
+	}
+
+	var edits []changelog.ReleaseEdit
+	for _, e := range changelog.DiffChangelogs(old, head) {
+		if !slices.ContainsFunc(allowed, func(v string) bool {
+			return changelog.NormalizeVersion(v) == changelog.NormalizeVersion(e.Version)
+		}) {
+			edits = append(edits, e)
+		}
+	}
+	return edits, nil
+}
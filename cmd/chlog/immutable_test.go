@@ -0,0 +1,38 @@
+package main
+
+import "testing"
+
+func TestReleaseEdits(t *testing.T) {
+	tests := map[string]struct {
+		allowed []string
+		want    int
+	}{
+		"edit reported":     {want: 1},
+		"allowed version":   {allowed: []string{"v1.0.0"}},
+		"other allowlisted": {allowed: []string{"0.9.0"}, want: 1},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			initReleaseRepo(t)
+			c := loadTestChangelog(t, yamlFile)
+			if err := c.Release("1.0.0", "2024-06-01"); err != nil {
+				t.Fatal(err)
+			}
+			writeTestChangelog(t, yamlFile, c)
+			gitOutput(t, "commit", "-qam", "release 1.0.0")
+			base := gitOutput(t, "rev-parse", "HEAD")
+
+			v, _ := c.GetVersion("1.0.0")
+			v.Public.Categories[0].Entries[0] = "Login page with SSO"
+			writeTestChangelog(t, yamlFile, c)
+
+			edits, err := releaseEdits(base, tt.allowed)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(edits) != tt.want {
+				t.Errorf("edits = %v, want %d", edits, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ariel-frischer/chlog/pkg/changelog"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importForce   bool
+	importDryRun  bool
+	importProject string
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import an existing changelog into CHANGELOG.yaml",
+}
+
+var importMarkdownCmd = &cobra.Command{
+	Use:   "markdown <file>",
+	Short: "Import a Keep a Changelog CHANGELOG.md",
+	Long: `Convert a Keep a Changelog markdown file into CHANGELOG.yaml.
+Content that cannot be mapped (prose, unknown categories, undated versions)
+is skipped and reported as a warning.`,
+	Example: `  chlog import markdown CHANGELOG.md
+  chlog import markdown --dry-run docs/CHANGES.md
+  chlog import markdown --project myapp --force CHANGELOG.md`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImportMarkdown,
+}
+
+var importJSONCmd = &cobra.Command{
+	Use:   "json <file>",
+	Short: "Import a JSON document produced by --format json",
+	Long: `Convert a JSON changelog document, as written by
+"chlog show --format json --internal", back into CHANGELOG.yaml.
+Use "-" to read from stdin.`,
+	Example: `  chlog import json changelog.json
+  curl -s https://example.com/changelog.json | chlog import json --dry-run -`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImportJSON,
+}
+
+func init() {
+	importCmd.PersistentFlags().BoolVar(&importForce, "force", false, "overwrite an existing CHANGELOG.yaml")
+	importCmd.PersistentFlags().BoolVar(&importDryRun, "dry-run", false, "print the resulting YAML instead of writing it")
+	importCmd.PersistentFlags().StringVar(&importProject, "project", "", "project name (default: from the document, else directory name)")
+	importCmd.AddCommand(importMarkdownCmd, importJSONCmd)
+}
+
+func runImportMarkdown(cmd *cobra.Command, args []string) error {
+	f, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", args[0], err)
+	}
+	defer func() { _ = f.Close() }()
+
+	res, err := changelog.ImportMarkdown(f, changelog.ImportOptions{Project: importProject, Config: loadConfig()})
+	if err != nil {
+		return err
+	}
+	for _, w := range res.Warnings {
+		fmt.Fprintln(os.Stderr, warnFmt.Sprintf("%s:%d: %s", args[0], w.Line, w.Message))
+	}
+	if err := writeImported(res.Changelog); err != nil {
+		return err
+	}
+	if res.RepoURL != "" && loadConfig().RepoURL == "" && !importDryRun {
+		fmt.Printf("Detected repo URL %s — run `chlog config set repo_url %s` to keep comparison links\n",
+			highlight(res.RepoURL), res.RepoURL)
+	}
+	return nil
+}
+
+func runImportJSON(cmd *cobra.Command, args []string) error {
+	var r io.Reader = os.Stdin
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("opening %s: %w", args[0], err)
+		}
+		defer func() { _ = f.Close() }()
+		r = f
+	}
+
+	c, err := changelog.ImportJSON(r, changelog.ImportOptions{Project: importProject, Config: loadConfig()})
+	if err != nil {
+		return err
+	}
+	return writeImported(c)
+}
+
+// writeImported saves an imported changelog, or prints it with --dry-run.
+func writeImported(c *changelog.Changelog) error {
+	if c.Project == "" {
+		dir, _ := os.Getwd()
+		c.Project = filepath.Base(dir)
+	}
+
+	if importDryRun {
+		data, err := changelog.Marshal(c)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+
+	if _, err := os.Stat(yamlFile); err == nil && !importForce {
+		return fmt.Errorf("%s already exists (use --force to overwrite)", yamlFile)
+	}
+	if err := changelog.Save(c, yamlFile); err != nil {
+		return fmt.Errorf("saving %s: %w", yamlFile, err)
+	}
+	success("Imported %d versions (%d entries) into %s",
+		c.GetVersionCount(), c.GetEntryCount(changelog.QueryOptions{IncludeInternal: true}), fileRef(yamlFile))
+	return nil
+}
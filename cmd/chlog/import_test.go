@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunImportMarkdown(t *testing.T) {
+	dir := t.TempDir()
+	yamlFile = filepath.Join(dir, "CHANGELOG.yaml")
+	configFile = filepath.Join(dir, ".chlog.yaml")
+	md := filepath.Join(dir, "CHANGELOG.md")
+	content := "# Changelog\n\nAll notable changes to demo will be documented in this file.\n\n" +
+		"## [1.0.0] - 2024-01-01\n\n### Added\n\n- First release\n"
+	if err := os.WriteFile(md, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	importForce, importDryRun, importProject = false, false, ""
+
+	if err := runImportMarkdown(nil, []string{md}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c := loadTestChangelog(t, yamlFile)
+	if c.Project != "demo" {
+		t.Errorf("project = %q, want demo", c.Project)
+	}
+	if got := c.Versions[0].Public.Get("added"); len(got) != 1 || got[0] != "First release" {
+		t.Errorf("entries = %v", got)
+	}
+
+	err := runImportMarkdown(nil, []string{md})
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected already exists error, got %v", err)
+	}
+}
+
+func TestRunImportJSON(t *testing.T) {
+	dir := t.TempDir()
+	yamlFile = filepath.Join(dir, "CHANGELOG.yaml")
+	configFile = filepath.Join(dir, ".chlog.yaml")
+	doc := filepath.Join(dir, "changelog.json")
+	content := `{"schema_version": 1, "project": "demo", "versions": [
+		{"version": "1.0.0", "date": "2024-01-01", "public": [{"name": "added", "entries": [{"text": "First", "pr": 4}]}]}]}`
+	if err := os.WriteFile(doc, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	importForce, importDryRun, importProject = false, false, ""
+
+	if err := runImportJSON(nil, []string{doc}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c := loadTestChangelog(t, yamlFile)
+	cat, _ := c.Versions[0].Public.Category("added")
+	if c.Project != "demo" || len(cat.Entries) != 1 || cat.MetaAt(0).PR != 4 {
+		t.Errorf("unexpected import: %+v", c.Versions)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ariel-frischer/chlog/pkg/changelog"
+	"github.com/spf13/cobra"
+)
+
+var (
+	lintFix    bool
+	lintFormat string
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check entry wording and style",
+	Long: `Check the entries of CHANGELOG.yaml for style problems that validation
+allows: length, capitalization, trailing punctuation, tense, banned patterns,
+commit message prefixes and hashes, repeated words and duplicate or
+near-duplicate entries within a version.
+
+Rules are configured under lint in .chlog.yaml; lint.disable turns rules off.
+--fix rewrites entries for the fixable rules (whitespace, commit-prefix,
+repeated-word, tense, capitalization and trailing-punctuation) and reports what
+is left. Entries from fragment files are fixed in those files.
+
+Exit 0 = clean, exit 1 = issues found, exit 2 = validation or config error.`,
+	Example: `  chlog lint
+  chlog lint --fix
+  chlog lint --format github`,
+	Args: cobra.NoArgs,
+	RunE: runLint,
+}
+
+func init() {
+	lintCmd.Flags().BoolVar(&lintFix, "fix", false, "rewrite entries the fixable rules can correct")
+	lintCmd.Flags().StringVar(&lintFormat, "format", "text", "output format: text, json, sarif, junit or github")
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	format, err := changelog.ParseReportFormat(lintFormat)
+	if err != nil {
+		return err
+	}
+	cfg := loadConfig()
+	if lintFix {
+		if err := fixLint(cfg); err != nil {
+			return err
+		}
+	}
+
+	c, err := changelog.Load(yamlFile, cfg)
+	if err != nil {
+		if format != changelog.ReportText {
+			finishReport(format, "lint", changelog.DiagnosticsFromError(yamlFile, err), 2)
+		}
+		if !printValidationErrors(err) {
+			fmt.Fprintln(os.Stderr, errFmt.Sprintf("validation error: %v", err))
+		}
+		os.Exit(2)
+	}
+	issues, err := changelog.Lint(c, cfg.Lint)
+	if err != nil {
+		return err
+	}
+
+	diags := make([]changelog.Diagnostic, len(issues))
+	for i, issue := range issues {
+		diags[i] = issue.Diagnostic(yamlFile)
+	}
+	if format == changelog.ReportText {
+		printLintIssues(issues)
+	}
+	finishReport(format, "lint", diags, 1)
+	return nil
+}
+
+// fixLint applies the fixable rules to CHANGELOG.yaml and, when enabled, to
+// each fragment file. CHANGELOG.yaml is loaded without fragments: their
+// entries are matched by text when saving.
+func fixLint(cfg *changelog.Config) error {
+	own := *cfg
+	own.Fragments = false
+	c, err := changelog.Load(yamlFile, &own)
+	if err != nil {
+		return err
+	}
+	n, err := changelog.FixLint(c, cfg.Lint)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		if err := changelog.Save(c, yamlFile); err != nil {
+			return fmt.Errorf("saving %s: %w", yamlFile, err)
+		}
+		success("Fixed %d entr%s in %s", n, pluralY(n), fileRef(yamlFile))
+	}
+	if !cfg.Fragments {
+		return nil
+	}
+
+	frags, err := changelog.LoadFragments(cfg.FragmentDirPath())
+	if err != nil {
+		return err
+	}
+	for _, f := range frags {
+		n, err := changelog.FixLintFragment(f, cfg.Lint)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			continue
+		}
+		if err := f.Save(); err != nil {
+			return err
+		}
+		success("Fixed %d entr%s in %s", n, pluralY(n), fileRef(f.Path))
+	}
+	return nil
+}
+
+func printLintIssues(issues []changelog.LintIssue) {
+	if len(issues) == 0 {
+		success("%s has no lint issues", fileRef(yamlFile))
+		return
+	}
+	fixable := 0
+	for _, issue := range issues {
+		line := issue.Diagnostic(yamlFile).String() + " " + boldFmt.Sprintf("[%s]", issue.Rule)
+		if issue.Fix != "" {
+			fixable++
+			line += fmt.Sprintf("\n    fix: %s", successFmt.Sprint(issue.Fix))
+		}
+		fmt.Println(line)
+	}
+	msg := fmt.Sprintf("%d issue(s)", len(issues))
+	if fixable > 0 {
+		msg += fmt.Sprintf(", %d fixable with --fix", fixable)
+	}
+	warn("%s", msg)
+}
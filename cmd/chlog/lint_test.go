@@ -0,0 +1,31 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ariel-frischer/chlog/pkg/changelog"
+)
+
+func TestFixLint_FixesFragmentsInPlace(t *testing.T) {
+	initReleaseRepo(t)
+	writeFile(t, yamlFile, "project: demo\nversions:\n  unreleased:\n    added:\n      - login page for admins.\n")
+	writeFile(t, ".chlog/unreleased/feat.yaml", "added:\n  - dark mode for the editor\n")
+	cfg := &changelog.Config{Fragments: true}
+
+	if err := fixLint(cfg); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(yamlFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); !strings.Contains(got, "- Login page for admins\n") || strings.Contains(got, "ark mode") {
+		t.Errorf("unexpected %s:\n%s", yamlFile, got)
+	}
+	frag, err := os.ReadFile(".chlog/unreleased/feat.yaml")
+	if err != nil || !strings.Contains(string(frag), "- Dark mode for the editor\n") {
+		t.Errorf("fragment not fixed: %s (%v)", frag, err)
+	}
+}
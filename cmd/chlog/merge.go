@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ariel-frischer/chlog/pkg/changelog"
+	"github.com/spf13/cobra"
+)
+
+// mergeDriverName is the driver key in .git/config and .gitattributes.
+const mergeDriverName = "chlog"
+
+var mergeDriverCmd = &cobra.Command{
+	Use:   "merge-driver <base> <ours> <theirs>",
+	Short: "Three-way merge CHANGELOG.yaml (git merge driver)",
+	Long: `Merge two versions of CHANGELOG.yaml against their common ancestor and write
+the result to <ours>, as git calls a merge driver with %O %A %B.
+
+Entries are merged per category: both sides' additions are kept and removals
+are honored, so branches that each append to unreleased merge cleanly. True
+conflicts, such as the same version released with different dates, keep our
+side, are reported on stderr and fail the merge. Files that cannot be parsed
+fall back to a textual merge with conflict markers.
+
+Register the driver with 'chlog install-merge-driver'.`,
+	Args: cobra.ExactArgs(3),
+	RunE: runMergeDriver,
+}
+
+var installMergeDriverCmd = &cobra.Command{
+	Use:   "install-merge-driver",
+	Short: "Register the CHANGELOG.yaml merge driver in .git/config and .gitattributes",
+	Args:  cobra.NoArgs,
+	RunE:  runInstallMergeDriver,
+}
+
+func runMergeDriver(cmd *cobra.Command, args []string) error {
+	basePath, oursPath, theirsPath := args[0], args[1], args[2]
+	cfg := loadConfig()
+	var sides [3]*changelog.Changelog
+	for i, path := range args {
+		c, err := loadMergeSide(path, cfg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, warnFmt.Sprintf("chlog merge-driver: %v — falling back to a textual merge", err))
+			return textualMerge(oursPath, basePath, theirsPath)
+		}
+		sides[i] = c
+	}
+
+	merged, conflicts := changelog.Merge(sides[0], sides[1], sides[2])
+	if err := changelog.Save(merged, oursPath); err != nil {
+		return fmt.Errorf("writing merge result: %w", err)
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	for _, c := range conflicts {
+		fmt.Fprintln(os.Stderr, errFmt.Sprintf("conflict: %v", c))
+	}
+	return fmt.Errorf("%d changelog conflict(s) — resolved to our side, review before committing", len(conflicts))
+}
+
+// loadMergeSide loads one side of the merge. An empty file, as git passes for
+// a base that did not exist, is an empty changelog.
+func loadMergeSide(path string, cfg *changelog.Config) (*changelog.Changelog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return &changelog.Changelog{}, nil
+	}
+	c, err := changelog.LoadFromReader(bytes.NewReader(data), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("loading %s: %w", path, err)
+	}
+	return c, nil
+}
+
+func textualMerge(ours, base, theirs string) error {
+	conflicted, err := changelog.MergeFile(ours, base, theirs)
+	if err != nil {
+		return err
+	}
+	if conflicted {
+		return fmt.Errorf("textual merge left conflict markers")
+	}
+	return nil
+}
+
+func runInstallMergeDriver(cmd *cobra.Command, args []string) error {
+	if err := changelog.SetGitConfig("merge."+mergeDriverName+".name", "chlog CHANGELOG.yaml merge driver"); err != nil {
+		return err
+	}
+	driver := "chlog merge-driver %O %A %B"
+	if err := changelog.SetGitConfig("merge."+mergeDriverName+".driver", driver); err != nil {
+		return err
+	}
+	success("Registered merge driver %s in %s", highlight(mergeDriverName), fileRef(".git/config"))
+
+	line := yamlFile + " merge=" + mergeDriverName
+	added, err := appendLineOnce(".gitattributes", line)
+	if err != nil {
+		return err
+	}
+	if added {
+		success("Added %s to %s — commit it so collaborators use the driver", highlight(line), fileRef(".gitattributes"))
+	} else {
+		fmt.Printf("%s already contains %s\n", fileRef(".gitattributes"), highlight(line))
+	}
+	return nil
+}
+
+// appendLineOnce appends line to the file unless an identical line exists,
+// creating the file if needed. It reports whether the line was added.
+func appendLineOnce(path, line string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("reading %s: %w", path, err)
+	}
+	for _, l := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(l) == line {
+			return false, nil
+		}
+	}
+	if len(data) > 0 && !bytes.HasSuffix(data, []byte("\n")) {
+		data = append(data, '\n')
+	}
+	data = append(data, line+"\n"...)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return false, fmt.Errorf("writing %s: %w", path, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeMergeSides(t *testing.T, base, ours, theirs string) []string {
+	t.Helper()
+	dir := t.TempDir()
+	var paths []string
+	for i, content := range []string{base, ours, theirs} {
+		path := filepath.Join(dir, []string{"base", "ours", "theirs"}[i])
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func TestRunMergeDriver(t *testing.T) {
+	configFile = filepath.Join(t.TempDir(), ".chlog.yaml")
+	base := "project: demo\nversions:\n  unreleased:\n    added:\n      - Base\n"
+	paths := writeMergeSides(t, base,
+		base+"      - Ours\n",
+		base+"      - Theirs\n")
+
+	if err := runMergeDriver(nil, paths); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c := loadTestChangelog(t, paths[1])
+	if got := strings.Join(c.GetUnreleased().Public.Get("added"), ","); got != "Base,Ours,Theirs" {
+		t.Errorf("added = %s", got)
+	}
+}
+
+func TestRunMergeDriver_Conflict(t *testing.T) {
+	configFile = filepath.Join(t.TempDir(), ".chlog.yaml")
+	release := "project: demo\nversions:\n  1.0.0:\n    date: %s\n    added:\n      - A\n"
+	paths := writeMergeSides(t, "",
+		fmt.Sprintf(release, "2024-06-01"),
+		fmt.Sprintf(release, "2024-06-02"))
+
+	err := runMergeDriver(nil, paths)
+	if err == nil || !strings.Contains(err.Error(), "conflict") {
+		t.Fatalf("error = %v, want conflict", err)
+	}
+	v, err := loadTestChangelog(t, paths[1]).GetVersion("1.0.0")
+	if err != nil || v.Date != "2024-06-01" {
+		t.Errorf("conflict should keep ours: %+v, %v", v, err)
+	}
+}
+
+func TestRunMergeDriver_FallsBackToTextualMerge(t *testing.T) {
+	initReleaseRepo(t)
+	paths := writeMergeSides(t, "project: [\n", "project: a\n", "project: b\n")
+
+	if err := runMergeDriver(nil, paths); err == nil {
+		t.Fatal("expected a conflicting textual merge")
+	}
+	data, _ := os.ReadFile(paths[1])
+	if !strings.Contains(string(data), "<<<<<<<") {
+		t.Errorf("expected conflict markers, got:\n%s", data)
+	}
+}
+
+func TestRunInstallMergeDriver(t *testing.T) {
+	initReleaseRepo(t)
+	if err := os.WriteFile(".gitattributes", []byte("*.go diff=golang"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for range 2 {
+		if err := runInstallMergeDriver(nil, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got := gitOutput(t, "config", "merge.chlog.driver"); got != "chlog merge-driver %O %A %B" {
+		t.Errorf("driver = %q", got)
+	}
+	data, _ := os.ReadFile(".gitattributes")
+	if got := string(data); got != "*.go diff=golang\nCHANGELOG.yaml merge=chlog\n" {
+		t.Errorf(".gitattributes = %q", got)
+	}
+}
@@ -2,30 +2,62 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/ariel-frischer/chlog/pkg/changelog"
 	"github.com/spf13/cobra"
 )
 
-var releaseDate string
+var (
+	releaseDate     string
+	releaseCollapse bool
+	releaseCommit   bool
+	releaseTag      bool
+	releaseSign     bool
+)
 
 var releaseCmd = &cobra.Command{
 	Use:   "release <version>",
 	Short: "Promote unreleased changes to a versioned release",
-	Long:  "Stamps the unreleased block with the given version and today's date, then creates a fresh unreleased block.",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runRelease,
+	Long: `Stamps the unreleased block with the given version and today's date, then creates a fresh unreleased block.
+Entries from fragment files (fragments: true) are folded into the release and
+the fragment files are deleted.
+
+With --commit, the working tree must be clean; the markdown outputs are synced
+and committed together with CHANGELOG.yaml using commit_message from
+.chlog.yaml. --tag also commits, then creates an annotated tag (tag_prefix +
+version) whose message is the version's release notes.`,
+	Example: `  chlog release 1.2.0
+  chlog release 1.2.0 --tag
+  chlog release 1.2.0 --tag --sign`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRelease,
 }
 
 func init() {
 	releaseCmd.Flags().StringVar(&releaseDate, "date", "", "release date in YYYY-MM-DD format (default: today)")
+	releaseCmd.Flags().BoolVar(&releaseCollapse, "collapse-prereleases", false, "merge the version's pre-release blocks (e.g. 2.0.0-rc.*) into it")
+	releaseCmd.Flags().BoolVar(&releaseCommit, "commit", false, "sync outputs and commit them with CHANGELOG.yaml")
+	releaseCmd.Flags().BoolVar(&releaseTag, "tag", false, "commit, then create an annotated tag with the release notes")
+	releaseCmd.Flags().BoolVar(&releaseSign, "sign", false, "GPG-sign the release commit and tag (with --commit or --tag)")
 }
 
 func runRelease(cmd *cobra.Command, args []string) error {
 	ver := args[0]
+	useGit := releaseCommit || releaseTag
+	if releaseSign && !useGit {
+		return fmt.Errorf("--sign requires --commit or --tag")
+	}
+	cfg := loadConfig()
+	if useGit {
+		if err := checkGitRelease(cfg, ver); err != nil {
+			return err
+		}
+	}
 
-	c, err := changelog.Load(yamlFile)
+	c, err := loadChangelog()
 	if err != nil {
 		return err
 	}
@@ -35,7 +67,8 @@ func runRelease(cmd *cobra.Command, args []string) error {
 		date = time.Now().Format("2006-01-02")
 	}
 
-	if err := c.Release(ver, date); err != nil {
+	collapsed, err := releaseChangelog(c, cfg, ver, date)
+	if err != nil {
 		return err
 	}
 
@@ -44,5 +77,132 @@ func runRelease(cmd *cobra.Command, args []string) error {
 	}
 
 	success("Released %s (%s) — unreleased block reset", versionRef(ver), highlight(date))
+	if collapsed > 0 {
+		success("Collapsed pre-releases into %s (%d blocks removed)", versionRef(ver), collapsed)
+	}
+	if err := removeFragments(c, ver); err != nil {
+		return err
+	}
+	if !useGit {
+		return nil
+	}
+	return commitRelease(c, cfg, ver)
+}
+
+// releaseChangelog runs Release with the configured options and returns the
+// number of collapsed pre-release blocks.
+func releaseChangelog(c *changelog.Changelog, cfg *changelog.Config, ver, date string) (int, error) {
+	scheme, err := cfg.Scheme()
+	if err != nil {
+		return 0, err
+	}
+	var collapsed int
+	if releaseCollapse {
+		pres, err := c.PrereleasesOf(ver)
+		if err != nil {
+			return 0, err
+		}
+		collapsed = len(pres)
+	}
+	opts := changelog.ReleaseOptions{Scheme: scheme, CollapsePrereleases: releaseCollapse}
+	return collapsed, c.Release(ver, date, opts)
+}
+
+// removeFragments deletes the fragment files a release folded into ver.
+func removeFragments(c *changelog.Changelog, ver string) error {
+	paths := c.Fragments()
+	if len(paths) == 0 {
+		return nil
+	}
+	if err := changelog.RemoveFragments(paths); err != nil {
+		return err
+	}
+	success("Folded %d fragment file(s) into %s", len(paths), versionRef(ver))
+	return nil
+}
+
+// checkGitRelease verifies the working tree is clean and, with --tag, that
+// the release tag does not exist yet.
+func checkGitRelease(cfg *changelog.Config, ver string) error {
+	dirty, err := changelog.DirtyFiles()
+	if err != nil {
+		return err
+	}
+	if len(dirty) > 0 {
+		return fmt.Errorf("working tree is not clean (%s) — commit or stash changes first", strings.Join(dirty, ", "))
+	}
+	if tag := cfg.TagName(ver); releaseTag && changelog.TagExists(tag) {
+		return fmt.Errorf("tag %s already exists", tag)
+	}
+	return nil
+}
+
+// commitRelease syncs the markdown outputs, commits them with CHANGELOG.yaml
+// and, with --tag, tags the commit with the version's release notes. When a
+// step fails, the error says what the release left on disk.
+func commitRelease(c *changelog.Changelog, cfg *changelog.Config, ver string) error {
+	paths := append([]string{yamlFile}, c.Fragments()...)
+	uncommitted := func(err error) error {
+		return fmt.Errorf("%w\n%s was released but not committed; changed on disk: %s\n"+
+			"the working tree was clean before, so 'git reset --hard' undoes the release", err, ver, strings.Join(paths, ", "))
+	}
+
+	tmpl, err := loadTemplate("", cfg)
+	if err != nil {
+		return uncommitted(err)
+	}
+	opts := changelog.RenderOptions{Config: cfg, Template: tmpl, IncludeInternal: cfg.IncludeInternal}
+	outputs, err := syncReleaseOutputs(c, opts)
+	paths = append(paths, outputs...)
+	if err != nil {
+		return uncommitted(err)
+	}
+
+	msg, err := cfg.ReleaseCommitMessage(ver)
+	if err != nil {
+		return uncommitted(err)
+	}
+	if err := changelog.CommitFiles(msg, paths, releaseSign); err != nil {
+		return uncommitted(err)
+	}
+	success("Committed %s", highlight(msg))
+	if !releaseTag {
+		return nil
+	}
+
+	v, err := c.GetVersion(ver)
+	if err != nil {
+		return err
+	}
+	var notes strings.Builder
+	if err := changelog.RenderVersionMarkdown(v, &notes, opts); err != nil {
+		return fmt.Errorf("rendering release notes: %w", err)
+	}
+	tag := cfg.TagName(ver)
+	if err := changelog.CreateTag(tag, notes.String(), releaseSign); err != nil {
+		return fmt.Errorf("%w\nthe release commit %q was created but not tagged; "+
+			"tag it by hand or undo it with 'git reset --hard HEAD~1'", err, msg)
+	}
+	success("Tagged %s", versionRef(tag))
 	return nil
 }
+
+// syncReleaseOutputs regenerates the public changelog, and the internal one
+// if the project keeps it, returning the paths written, also on error.
+func syncReleaseOutputs(c *changelog.Changelog, opts changelog.RenderOptions) ([]string, error) {
+	public := opts.Config.PublicFilePath()
+	if err := syncFile(c, opts, public, changelog.RenderMarkdownString); err != nil {
+		return nil, err
+	}
+	paths := []string{public}
+
+	internal := opts.Config.InternalFilePath()
+	if _, err := os.Stat(internal); err == nil {
+		opts.IncludeInternal = true
+		if err := syncFile(c, opts, internal, changelog.RenderMarkdownString); err != nil {
+			return paths, err
+		}
+		paths = append(paths, internal)
+	}
+	return paths, nil
+}
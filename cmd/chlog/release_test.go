@@ -0,0 +1,208 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ariel-frischer/chlog/pkg/changelog"
+)
+
+// initReleaseRepo creates a git repository in a temp dir, changes into it and
+// commits a changelog with one unreleased entry.
+func initReleaseRepo(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Chdir(t.TempDir())
+	yamlFile, configFile = "CHANGELOG.yaml", ".chlog.yaml"
+
+	c := &changelog.Changelog{Project: "demo", Versions: []changelog.Version{{Version: "unreleased"}}}
+	c.Versions[0].Public.Append("added", "Login page")
+	writeTestChangelog(t, yamlFile, c)
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.email", "dev@example.com"},
+		{"config", "user.name", "Dev"},
+		{"config", "commit.gpgsign", "false"},
+		{"add", "."},
+		{"commit", "-qm", "init"},
+	} {
+		gitOutput(t, args...)
+	}
+}
+
+func gitOutput(t *testing.T, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func resetReleaseFlags(t *testing.T) {
+	t.Cleanup(func() {
+		releaseDate, releaseCommit, releaseTag, releaseSign, releaseCollapse = "", false, false, false, false
+	})
+}
+
+func TestRunRelease_Tag(t *testing.T) {
+	initReleaseRepo(t)
+	resetReleaseFlags(t)
+	releaseDate, releaseTag = "2024-06-01", true
+
+	if err := runRelease(nil, []string{"1.0.0"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := gitOutput(t, "log", "-1", "--format=%s"); got != "release: v1.0.0" {
+		t.Errorf("commit subject = %q", got)
+	}
+	if got := gitOutput(t, "status", "--porcelain"); got != "" {
+		t.Errorf("expected clean tree after release, got %q", got)
+	}
+	if got := gitOutput(t, "show", "HEAD:CHANGELOG.md"); !strings.Contains(got, "## [1.0.0] - 2024-06-01") {
+		t.Errorf("CHANGELOG.md not committed:\n%s", got)
+	}
+	msg := gitOutput(t, "tag", "-l", "--format=%(contents)", "v1.0.0")
+	if !strings.Contains(msg, "### Added") || !strings.Contains(msg, "- Login page") {
+		t.Errorf("tag message = %q, want release notes", msg)
+	}
+}
+
+func TestRunRelease_CommitWithCustomConfig(t *testing.T) {
+	initReleaseRepo(t)
+	resetReleaseFlags(t)
+	cfg := "tag_prefix: api/v\ncommit_message: \"chore(release): {{.Version}} ({{.Tag}})\"\n"
+	if err := os.WriteFile(configFile, []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitOutput(t, "add", configFile)
+	gitOutput(t, "commit", "-qm", "config")
+	releaseCommit = true
+
+	if err := runRelease(nil, []string{"1.0.0"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := gitOutput(t, "log", "-1", "--format=%s"); got != "chore(release): 1.0.0 (api/v1.0.0)" {
+		t.Errorf("commit subject = %q", got)
+	}
+	if got := gitOutput(t, "tag"); got != "" {
+		t.Errorf("--commit alone should not tag, got %q", got)
+	}
+}
+
+func TestRunRelease_RequiresCleanTree(t *testing.T) {
+	initReleaseRepo(t)
+	resetReleaseFlags(t)
+	if err := os.WriteFile("scratch.txt", []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	releaseTag = true
+
+	err := runRelease(nil, []string{"1.0.0"})
+	if err == nil || !strings.Contains(err.Error(), "not clean") {
+		t.Fatalf("error = %v, want not clean", err)
+	}
+	if c := loadTestChangelog(t, yamlFile); c.GetUnreleased().Count() != 1 {
+		t.Error("changelog was modified despite the dirty tree")
+	}
+}
+
+func TestRunRelease_SignRequiresGit(t *testing.T) {
+	initReleaseRepo(t)
+	resetReleaseFlags(t)
+	releaseSign = true
+
+	err := runRelease(nil, []string{"1.0.0"})
+	if err == nil || !strings.Contains(err.Error(), "--sign requires") {
+		t.Fatalf("error = %v, want --sign rejected", err)
+	}
+	if c := loadTestChangelog(t, yamlFile); c.GetUnreleased().Count() != 1 {
+		t.Error("changelog was released despite the rejected flag")
+	}
+}
+
+func TestRunRelease_CommitFailureReportsFiles(t *testing.T) {
+	initReleaseRepo(t)
+	resetReleaseFlags(t)
+	writeFile(t, ".git/hooks/pre-commit", "#!/bin/sh\nexit 1\n")
+	if err := os.Chmod(".git/hooks/pre-commit", 0755); err != nil {
+		t.Fatal(err)
+	}
+	releaseCommit = true
+
+	err := runRelease(nil, []string{"1.0.0"})
+	if err == nil || !strings.Contains(err.Error(), "changed on disk: CHANGELOG.yaml, CHANGELOG.md") {
+		t.Fatalf("error = %v, want the files left on disk", err)
+	}
+}
+
+func TestRunRelease_ExistingTag(t *testing.T) {
+	initReleaseRepo(t)
+	resetReleaseFlags(t)
+	gitOutput(t, "tag", "v1.0.0")
+	releaseTag = true
+
+	err := runRelease(nil, []string{"1.0.0"})
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("error = %v, want tag already exists", err)
+	}
+}
+
+func TestLatestTag_TagPrefix(t *testing.T) {
+	initReleaseRepo(t)
+	gitOutput(t, "tag", "api/v1.0.0")
+	gitOutput(t, "commit", "-q", "--allow-empty", "-m", "web")
+	gitOutput(t, "tag", "web/v2.0.0")
+
+	prefix := "api/v"
+	cfg := &changelog.Config{TagPrefix: &prefix}
+	if tag, err := changelog.LatestTag(cfg.TagPattern()); err != nil || tag != "api/v1.0.0" {
+		t.Errorf("LatestTag(%q) = %q, %v; want api/v1.0.0", cfg.TagPattern(), tag, err)
+	}
+	if tag, _ := changelog.LatestTag(); tag != "web/v2.0.0" {
+		t.Errorf("LatestTag() = %q, want web/v2.0.0", tag)
+	}
+}
+
+func TestRunRelease_ConsumesFragments(t *testing.T) {
+	initReleaseRepo(t)
+	resetReleaseFlags(t)
+	if err := os.WriteFile(configFile, []byte("fragments: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitOutput(t, "checkout", "-qb", "feat/cache")
+	addVersion = "unreleased"
+	if err := runAdd(nil, []string{"fixed", "Fix cache race"}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	fragment := filepath.Join(changelog.DefaultFragmentDir, "feat-cache.yaml")
+	if _, err := os.Stat(fragment); err != nil {
+		t.Fatalf("fragment not written: %v", err)
+	}
+	if c := loadTestChangelog(t, yamlFile); c.GetUnreleased().Public.Get("fixed") != nil {
+		t.Error("fragment entry written to CHANGELOG.yaml")
+	}
+	gitOutput(t, "add", ".")
+	gitOutput(t, "commit", "-qm", "fragment")
+	releaseCommit = true
+
+	if err := runRelease(nil, []string{"1.0.0"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(fragment); !os.IsNotExist(err) {
+		t.Errorf("fragment not removed: %v", err)
+	}
+	v, err := loadTestChangelog(t, yamlFile).GetVersion("1.0.0")
+	if err != nil || len(v.Public.Get("fixed")) != 1 {
+		t.Errorf("release missing fragment entry: %+v, %v", v, err)
+	}
+	if got := gitOutput(t, "status", "--porcelain"); got != "" {
+		t.Errorf("fragment removal not committed:\n%s", got)
+	}
+}
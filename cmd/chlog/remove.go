@@ -40,7 +40,7 @@ func runRemove(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("entry text must not be empty")
 	}
 
-	c, err := changelog.Load(yamlFile)
+	c, err := loadChangelog()
 	if err != nil {
 		if os.IsNotExist(err) {
 			return fmt.Errorf("%s not found — run 'chlog init' first", yamlFile)
@@ -62,6 +62,15 @@ func runRemove(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return formatRemoveError(err)
 	}
+	if v.IsUnreleased() {
+		frags, err := c.RemoveFragmentEntry(removeInternal, category, removed)
+		if err != nil {
+			return err
+		}
+		for _, path := range frags {
+			success("Updated fragment %s", fileRef(path))
+		}
+	}
 
 	if err := changelog.Save(c, yamlFile); err != nil {
 		return fmt.Errorf("saving %s: %w", yamlFile, err)
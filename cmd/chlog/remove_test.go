@@ -1,6 +1,7 @@
 package main
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -229,3 +230,39 @@ func TestRunRemove_CleansUpEmptyCategory(t *testing.T) {
 		t.Errorf("categories = %v, want [fixed]", cats)
 	}
 }
+
+func TestRunRemove_FragmentEntry(t *testing.T) {
+	dir := t.TempDir()
+	yamlFile = filepath.Join(dir, "CHANGELOG.yaml")
+	configFile = filepath.Join(dir, ".chlog.yaml")
+	fragDir := filepath.Join(dir, "frags")
+	writeFile(t, configFile, "fragments: true\nfragment_dir: "+fragDir+"\n")
+	writeFile(t, yamlFile, "project: test\nversions:\n  unreleased:\n    added:\n      - In changelog\n")
+	writeFile(t, filepath.Join(fragDir, "a.yaml"), "added:\n  - From fragment\n  - Kept\n")
+	writeFile(t, filepath.Join(fragDir, "b.yaml"), "fixed:\n  - Only entry\n")
+
+	removeVersion = "unreleased"
+	removeInternal = false
+	removeMatch = false
+	if err := runRemove(nil, []string{"added", "From fragment"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := runRemove(nil, []string{"fixed", "Only entry"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c, err := changelog.Load(yamlFile, loadConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := c.GetUnreleased()
+	if got := u.Public.Get("added"); len(got) != 2 || got[0] != "In changelog" || got[1] != "Kept" {
+		t.Errorf("added = %v, want [In changelog Kept]", got)
+	}
+	if got := u.Public.Get("fixed"); got != nil {
+		t.Errorf("fixed = %v, want the fragment entry gone", got)
+	}
+	if _, err := os.Stat(filepath.Join(fragDir, "b.yaml")); !os.IsNotExist(err) {
+		t.Errorf("emptied fragment not deleted: %v", err)
+	}
+}
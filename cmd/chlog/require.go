@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/ariel-frischer/chlog/pkg/changelog"
+)
+
+// maxListedFiles caps the changed files printed when an entry is missing.
+const maxListedFiles = 10
+
+// runCheckChangedSince implements `check --changed-since`: exit 1 when files
+// changed since ref need a changelog entry and no unreleased entry was added.
+func runCheckChangedSince(ref string, labels []string) error {
+	pending, err := requireEntry(ref, labels)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, errFmt.Sprintf("error: %v", err))
+		os.Exit(2)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(os.Stderr, errFmt.Sprintf("No changelog entry added since %s, but %d changed file(s) need one:", ref, len(pending)))
+	for i, f := range pending {
+		if i == maxListedFiles {
+			fmt.Fprintf(os.Stderr, "  … and %d more\n", len(pending)-maxListedFiles)
+			break
+		}
+		fmt.Fprintf(os.Stderr, "  %s\n", fileRef(f))
+	}
+	label := loadConfig().Require.SkipLabelName()
+	fmt.Fprintf(os.Stderr, "Add one with 'chlog add <category> \"...\"', or waive it with the %s label or a '%s: true' commit trailer\n", highlight(label), label)
+	os.Exit(1)
+	return nil
+}
+
+// requireEntry returns the changed files that need a changelog entry when
+// none was added to unreleased since ref, or nil when the requirement is met
+// or waived.
+func requireEntry(ref string, labels []string) ([]string, error) {
+	cfg := loadConfig()
+	changed, err := changelog.ChangedFiles(ref)
+	if err != nil {
+		return nil, err
+	}
+	relevant, err := requiringFiles(changed, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(relevant) == 0 {
+		success("No changes since %s require a changelog entry", ref)
+		return nil, nil
+	}
+
+	label := cfg.Require.SkipLabelName()
+	if slices.ContainsFunc(labels, func(l string) bool { return strings.EqualFold(strings.TrimSpace(l), label) }) {
+		success("Changelog entry waived by the %s label", highlight(label))
+		return nil, nil
+	}
+	messages, err := changelog.CommitMessages(ref)
+	if err != nil {
+		return nil, err
+	}
+	if changelog.HasSkipTrailer(messages, label) {
+		success("Changelog entry waived by a %s commit trailer", highlight(label))
+		return nil, nil
+	}
+
+	added, err := entriesAddedSince(ref, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(added) == 0 {
+		return relevant, nil
+	}
+	success("%d changelog entr%s added since %s", len(added), pluralY(len(added)), ref)
+	return nil, nil
+}
+
+// requiringFiles drops the changelog's own files and paths the require
+// globs exempt. changed and the globs are relative to the repository root,
+// the own files to the working directory.
+func requiringFiles(changed []string, cfg *changelog.Config) ([]string, error) {
+	own, err := changelog.RepoPaths(yamlFile, configFile, defaultMDFile, cfg.PublicFilePath(), cfg.InternalFilePath(), cfg.FragmentDirPath())
+	if err != nil {
+		return nil, err
+	}
+	fragDir := own[len(own)-1] + "/"
+	own = own[:len(own)-1]
+
+	var out []string
+	for _, f := range changed {
+		f = path.Clean(f)
+		if slices.Contains(own, f) || strings.HasPrefix(f, fragDir) {
+			continue
+		}
+		if cfg.Require.RequiresEntry(f) {
+			out = append(out, f)
+		}
+	}
+	return out, nil
+}
+
+// entriesAddedSince compares the unreleased entries at the merge base of ref
+// with the working tree, fragments included.
+func entriesAddedSince(ref string, cfg *changelog.Config) ([]changelog.Entry, error) {
+	base, err := changelog.MergeBase(ref)
+	if err != nil {
+		return nil, err
+	}
+	old, err := loadChangelogAt(base, cfg)
+	if err != nil {
+		return nil, err
+	}
+	head, err := loadChangelog()
+	if err != nil {
+		return nil, err
+	}
+	return changelog.UnreleasedAdditions(old, head), nil
+}
+
+// loadChangelogAt loads CHANGELOG.yaml and, when enabled, its fragments as
+// they were at a commit. A changelog missing there counts as empty.
+func loadChangelogAt(commit string, cfg *changelog.Config) (*changelog.Changelog, error) {
+	c := &changelog.Changelog{}
+	if data, err := changelog.ShowFile(commit, yamlFile); err == nil {
+		if c, err = changelog.LoadFromReader(bytes.NewReader(data), cfg); err != nil {
+			return nil, fmt.Errorf("loading %s at %s: %w", yamlFile, commit, err)
+		}
+	}
+	if !cfg.Fragments {
+		return c, nil
+	}
+	paths, err := changelog.ListFiles(commit, cfg.FragmentDirPath())
+	if err != nil {
+		return nil, err
+	}
+	var frags []*changelog.Fragment
+	for _, p := range paths {
+		if ext := path.Ext(p); ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+		data, err := changelog.ShowFile(commit, p)
+		if err != nil {
+			return nil, err
+		}
+		f, err := changelog.ParseFragment(p, data)
+		if err != nil {
+			return nil, err
+		}
+		frags = append(frags, f)
+	}
+	c.ApplyFragments(frags)
+	return c, nil
+}
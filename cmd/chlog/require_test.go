@@ -0,0 +1,119 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ariel-frischer/chlog/pkg/changelog"
+)
+
+func TestRequireEntry(t *testing.T) {
+	tests := map[string]struct {
+		setup   func(t *testing.T)
+		labels  []string
+		pending int
+	}{
+		"code change without entry": {
+			setup:   func(t *testing.T) { writeFile(t, "main.go", "package main") },
+			pending: 1,
+		},
+		"entry added": {
+			setup: func(t *testing.T) {
+				writeFile(t, "main.go", "package main")
+				c := loadTestChangelog(t, yamlFile)
+				c.GetUnreleased().Public.Append("fixed", "Crash on start")
+				writeTestChangelog(t, yamlFile, c)
+			},
+		},
+		"excluded paths only": {
+			setup: func(t *testing.T) {
+				writeFile(t, ".chlog.yaml", "require:\n  exclude: [\"docs/**\", \"*.md\"]\n")
+				writeFile(t, "docs/guide.md", "# Guide")
+				writeFile(t, "README.md", "# Demo")
+			},
+		},
+		"skip label": {
+			setup:  func(t *testing.T) { writeFile(t, "main.go", "package main") },
+			labels: []string{"bug", "Skip-Changelog"},
+		},
+		"skip trailer": {
+			setup: func(t *testing.T) {
+				writeFile(t, "main.go", "package main")
+				gitOutput(t, "add", ".")
+				gitOutput(t, "commit", "-qm", "Tweak\n\nSkip-Changelog: true")
+			},
+		},
+		"changelog-only change": {
+			setup: func(t *testing.T) {
+				c := loadTestChangelog(t, yamlFile)
+				c.Project = "renamed"
+				writeTestChangelog(t, yamlFile, c)
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			initReleaseRepo(t)
+			base := gitOutput(t, "rev-parse", "HEAD")
+			tt.setup(t)
+
+			pending, err := requireEntry(base, tt.labels)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(pending) != tt.pending {
+				t.Errorf("pending = %v, want %d file(s)", pending, tt.pending)
+			}
+		})
+	}
+}
+
+func TestRequireEntry_Fragments(t *testing.T) {
+	initReleaseRepo(t)
+	writeFile(t, ".chlog.yaml", "fragments: true\n")
+	gitOutput(t, "add", ".")
+	gitOutput(t, "commit", "-qm", "enable fragments")
+	base := gitOutput(t, "rev-parse", "HEAD")
+
+	writeFile(t, "main.go", "package main")
+	f := &changelog.Fragment{Path: ".chlog/unreleased/feat.yaml"}
+	f.Public.Append("added", "Dark mode")
+	if err := f.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	pending, err := requireEntry(base, nil)
+	if err != nil || len(pending) != 0 {
+		t.Errorf("fragment entry not counted: pending = %v, err = %v", pending, err)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRequireEntry_FromSubdirectory(t *testing.T) {
+	initReleaseRepo(t)
+	base := gitOutput(t, "rev-parse", "HEAD")
+	c := loadTestChangelog(t, yamlFile)
+	c.Project = "renamed"
+	writeTestChangelog(t, yamlFile, c)
+	writeFile(t, "sub/main.go", "package main")
+	t.Chdir("sub")
+	yamlFile, configFile = "../CHANGELOG.yaml", "../.chlog.yaml"
+
+	pending, err := requireEntry(base, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pending) != 1 || pending[0] != "sub/main.go" {
+		t.Errorf("pending = %v, want only sub/main.go", pending)
+	}
+}
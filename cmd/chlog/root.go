@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/ariel-frischer/chlog/internal/version"
@@ -56,19 +57,51 @@ func init() {
 	rootCmd.AddCommand(syncCmd)
 	rootCmd.AddCommand(checkCmd)
 	rootCmd.AddCommand(validateCmd)
+	rootCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(fmtCmd)
 	rootCmd.AddCommand(extractCmd)
+	rootCmd.AddCommand(feedCmd)
+	rootCmd.AddCommand(announceCmd)
+	rootCmd.AddCommand(exportCmd)
 	rootCmd.AddCommand(showCmd)
 	rootCmd.AddCommand(scaffoldCmd)
+	rootCmd.AddCommand(diffCmd)
 	rootCmd.AddCommand(releaseCmd)
+	rootCmd.AddCommand(bumpCmd)
 	rootCmd.AddCommand(addCmd)
 	rootCmd.AddCommand(removeCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(sortCmd)
+	rootCmd.AddCommand(mergeDriverCmd)
+	rootCmd.AddCommand(installMergeDriverCmd)
 	rootCmd.AddCommand(versionCmd)
 }
 
+// loadTemplate returns the template named by flag or the config's template
+// setting, or nil to use the built-in default.
+func loadTemplate(flag string, cfg *changelog.Config) (*changelog.Template, error) {
+	path := flag
+	if path == "" {
+		path = cfg.Template
+	}
+	if path == "" {
+		return nil, nil
+	}
+	return changelog.LoadTemplate(path)
+}
+
+// loadChangelog loads the changelog file, validating it against the config.
+func loadChangelog() (*changelog.Changelog, error) {
+	return changelog.Load(yamlFile, loadConfig())
+}
+
+// loadConfig loads the config file, exiting with status 2 when it cannot be
+// read or has invalid settings.
 func loadConfig() *changelog.Config {
 	cfg, err := changelog.LoadConfig(configFile)
 	if err != nil {
-		return &changelog.Config{}
+		fmt.Fprintln(os.Stderr, errFmt.Sprintf("config error: %v", err))
+		os.Exit(2)
 	}
 	return cfg
 }
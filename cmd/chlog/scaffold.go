@@ -25,7 +25,7 @@ func init() {
 }
 
 func runScaffold(cmd *cobra.Command, args []string) error {
-	sinceTag, err := changelog.LatestTag()
+	sinceTag, err := changelog.LatestTag(loadConfig().TagPattern())
 	if err != nil {
 		sinceTag = ""
 	}
@@ -59,7 +59,7 @@ func runScaffold(cmd *cobra.Command, args []string) error {
 }
 
 func writeScaffold(v *changelog.Version) error {
-	c, err := changelog.Load(yamlFile)
+	c, err := loadChangelog()
 	if err != nil {
 		if os.IsNotExist(err) {
 			return fmt.Errorf("%s not found — run 'chlog init' first", yamlFile)
@@ -11,6 +11,7 @@ var (
 	showLast     int
 	showPlain    bool
 	showInternal bool
+	showFormat   string
 )
 
 var showCmd = &cobra.Command{
@@ -24,10 +25,11 @@ func init() {
 	showCmd.Flags().IntVarP(&showLast, "last", "n", 0, "show last N entries")
 	showCmd.Flags().BoolVar(&showPlain, "plain", false, "disable colors and icons")
 	showCmd.Flags().BoolVar(&showInternal, "internal", false, "include internal entries")
+	showCmd.Flags().StringVar(&showFormat, "format", "text", "output format: text or json")
 }
 
 func runShow(cmd *cobra.Command, args []string) error {
-	c, err := changelog.Load(yamlFile)
+	c, err := loadChangelog()
 	if err != nil {
 		return err
 	}
@@ -36,6 +38,14 @@ func runShow(cmd *cobra.Command, args []string) error {
 	internal := showInternal || cfg.IncludeInternal
 	opts := changelog.FormatOptions{Plain: showPlain, IncludeInternal: internal}
 
+	switch showFormat {
+	case "text":
+	case "json":
+		return showJSON(c, args, changelog.QueryOptions{IncludeInternal: internal})
+	default:
+		return fmt.Errorf("unknown format %q (valid: text, json)", showFormat)
+	}
+
 	if len(args) == 1 {
 		v, err := c.GetVersion(args[0])
 		if err != nil {
@@ -60,3 +70,22 @@ func runShow(cmd *cobra.Command, args []string) error {
 	fmt.Print(changelog.FormatTerminal(c, opts))
 	return nil
 }
+
+// showJSON prints the same selection as runShow as JSON.
+func showJSON(c *changelog.Changelog, args []string, opts changelog.QueryOptions) error {
+	if len(args) == 1 {
+		v, err := c.GetVersion(args[0])
+		if err != nil {
+			return err
+		}
+		return printJSON(changelog.NewJSONVersion(v, opts))
+	}
+	if showLast > 0 {
+		entries := c.GetLastN(showLast, opts)
+		if entries == nil {
+			entries = []changelog.Entry{}
+		}
+		return printJSON(entries)
+	}
+	return printJSON(changelog.NewJSONDocument(c, opts))
+}
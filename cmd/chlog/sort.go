@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/ariel-frischer/chlog/pkg/changelog"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sortScheme string
+	sortDryRun bool
+)
+
+var sortCmd = &cobra.Command{
+	Use:   "sort",
+	Short: "Reorder versions newest first",
+	Long: `Reorder the versions in CHANGELOG.yaml newest first according to the
+version scheme (version_scheme in .chlog.yaml, or --scheme). The unreleased
+block stays at the top; comments move with their version.`,
+	Example: `  chlog sort
+  chlog sort --scheme calver --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runSort,
+}
+
+func init() {
+	sortCmd.Flags().StringVar(&sortScheme, "scheme", "", "version scheme: semver or calver (default: version_scheme from config)")
+	sortCmd.Flags().BoolVar(&sortDryRun, "dry-run", false, "print the sorted YAML instead of writing it")
+}
+
+func runSort(cmd *cobra.Command, args []string) error {
+	cfg := *loadConfig()
+	if sortScheme != "" {
+		cfg.VersionScheme = sortScheme
+	}
+	scheme, err := cfg.Scheme()
+	if err != nil {
+		return err
+	}
+	if scheme == changelog.SchemeNone {
+		return fmt.Errorf("no version scheme set — pass --scheme or run `chlog config set version_scheme semver`")
+	}
+
+	// Load without the scheme so an out-of-order file can be fixed.
+	cfg.VersionScheme = ""
+	c, err := changelog.Load(yamlFile, &cfg)
+	if err != nil {
+		return err
+	}
+	before := c.ListVersions()
+	if err := c.Sort(scheme); err != nil {
+		return err
+	}
+
+	if sortDryRun {
+		data, err := changelog.Marshal(c)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+	if slices.Equal(before, c.ListVersions()) {
+		success("%s is already in order", fileRef(yamlFile))
+		return nil
+	}
+	if err := changelog.Save(c, yamlFile); err != nil {
+		return fmt.Errorf("saving %s: %w", yamlFile, err)
+	}
+	success("Sorted %d versions in %s", c.GetVersionCount(), fileRef(yamlFile))
+	return nil
+}
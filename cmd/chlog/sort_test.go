@@ -0,0 +1,33 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestRunSort(t *testing.T) {
+	dir := t.TempDir()
+	yamlFile = filepath.Join(dir, "CHANGELOG.yaml")
+	configFile = filepath.Join(dir, ".chlog.yaml")
+	content := "project: demo\nversions:\n  0.2.0:\n    date: \"2024-01-01\"\n    added: [a]\n" +
+		"  0.10.0:\n    date: \"2024-03-01\"\n    added: [b]\n"
+	if err := os.WriteFile(yamlFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sortScheme, sortDryRun = "", false
+
+	if err := runSort(nil, nil); err == nil {
+		t.Error("expected error without a version scheme")
+	}
+
+	sortScheme = "semver"
+	if err := runSort(nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c := loadTestChangelog(t, yamlFile)
+	if got := c.ListVersions(); !slices.Equal(got, []string{"0.10.0", "0.2.0"}) {
+		t.Errorf("order = %v", got)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/ariel-frischer/chlog/pkg/changelog"
 	"github.com/spf13/cobra"
@@ -12,49 +14,116 @@ import (
 var (
 	syncInternal bool
 	syncSplit    bool
+	syncTemplate string
+	syncFormat   string
 )
 
 var syncCmd = &cobra.Command{
 	Use:   "sync",
 	Short: "Generate CHANGELOG.md from CHANGELOG.yaml",
-	RunE:  runSync,
+	Long: `Generate CHANGELOG.md from CHANGELOG.yaml.
+
+--format html writes a self-contained, styled HTML page next to each markdown
+file instead (CHANGELOG.html, or CHANGELOG-internal.html with --split), with
+an anchor per version, category badges and compare links. With internal
+entries included, the page has a toggle to hide them.
+
+--format debian and rpm write the released versions as a packaging changelog,
+configured under packaging in .chlog.yaml (maintainer is required): debian
+writes debian/changelog, rpm replaces the %changelog section of <name>.spec.
+Packaging changelogs list public entries only, even with include_internal.`,
+	Example: `  chlog sync
+  chlog sync --split
+  chlog sync --format html --internal
+  chlog sync --format debian`,
+	RunE: runSync,
 }
 
 func init() {
 	syncCmd.Flags().BoolVar(&syncInternal, "internal", false, "include internal entries")
 	syncCmd.Flags().BoolVar(&syncSplit, "split", false, "generate both public and internal changelogs")
+	syncCmd.Flags().StringVar(&syncTemplate, "template", "", "render with a custom text/template file")
+	syncCmd.Flags().StringVar(&syncFormat, "format", "markdown", "output format: markdown, html, debian or rpm")
+}
+
+// syncRenderer renders a whole changelog in a sync output format.
+type syncRenderer func(c *changelog.Changelog, opts ...changelog.RenderOptions) (string, error)
+
+// syncOutput returns the renderer of a --format value and maps a markdown
+// output path to the path of that format. The packaging formats write the
+// file configured under packaging instead.
+func syncOutput(format string, cfg *changelog.Config, project string) (syncRenderer, func(string) string, error) {
+	switch format {
+	case "markdown", "md":
+		return changelog.RenderMarkdownString, func(p string) string { return p }, nil
+	case "html":
+		return changelog.RenderHTMLString, func(p string) string { return strings.TrimSuffix(p, filepath.Ext(p)) + ".html" }, nil
+	case "debian":
+		path := cfg.Packaging.DebianFilePath()
+		return changelog.RenderDebianString, func(string) string { return path }, nil
+	case "rpm":
+		path := cfg.Packaging.RPMFilePath(project)
+		return rpmRenderer(path), func(string) string { return path }, nil
+	}
+	return nil, nil, fmt.Errorf("unknown format %q (valid: markdown, html, debian, rpm)", format)
+}
+
+// rpmRenderer renders the %changelog entries into the spec file at path,
+// or on their own when path is not a .spec file.
+func rpmRenderer(path string) syncRenderer {
+	return func(c *changelog.Changelog, opts ...changelog.RenderOptions) (string, error) {
+		entries, err := changelog.RenderRPMString(c, opts...)
+		if err != nil || filepath.Ext(path) != ".spec" {
+			return entries, err
+		}
+		spec, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("reading spec file: %w", err)
+		}
+		return changelog.ReplaceRPMChangelog(string(spec), entries)
+	}
 }
 
 func runSync(cmd *cobra.Command, args []string) error {
-	c, err := changelog.Load(yamlFile)
+	c, err := loadChangelog()
 	if err != nil {
 		return err
 	}
-
 	cfg := loadConfig()
-
-	if syncSplit {
-		return runSyncSplit(c, cfg)
+	render, outPath, err := syncOutput(syncFormat, cfg, c.Project)
+	if err != nil {
+		return err
+	}
+	if syncTemplate != "" && syncFormat != "markdown" && syncFormat != "md" {
+		return fmt.Errorf("--template applies to markdown output only")
+	}
+	if packaging := syncFormat == "debian" || syncFormat == "rpm"; packaging && (syncSplit || syncInternal) {
+		return fmt.Errorf("--split and --internal do not apply to --format %s: packages list public entries only", syncFormat)
 	}
 
-	internal := syncInternal || cfg.IncludeInternal
-	return syncFile(c, cfg, internal, defaultMDFile)
-}
-
-func runSyncSplit(c *changelog.Changelog, cfg *changelog.Config) error {
-	if err := syncFile(c, cfg, false, cfg.PublicFilePath()); err != nil {
+	tmpl, err := loadTemplate(syncTemplate, cfg)
+	if err != nil {
 		return err
 	}
-	return syncFile(c, cfg, true, cfg.InternalFilePath())
+	opts := changelog.RenderOptions{Config: cfg, Template: tmpl}
+
+	if syncSplit {
+		opts.IncludeInternal = false
+		if err := syncFile(c, opts, outPath(cfg.PublicFilePath()), render); err != nil {
+			return err
+		}
+		opts.IncludeInternal = true
+		return syncFile(c, opts, outPath(cfg.InternalFilePath()), render)
+	}
+
+	opts.IncludeInternal = syncInternal || cfg.IncludeInternal
+	return syncFile(c, opts, outPath(defaultMDFile), render)
 }
 
-func syncFile(c *changelog.Changelog, cfg *changelog.Config, internal bool, path string) error {
-	rendered, err := changelog.RenderMarkdownString(c, changelog.RenderOptions{
-		IncludeInternal: internal,
-		Config:          cfg,
-	})
+func syncFile(c *changelog.Changelog, opts changelog.RenderOptions, path string, render syncRenderer) error {
+	rendered, err := render(c, opts)
 	if err != nil {
-		return fmt.Errorf("rendering markdown: %w", err)
+		return fmt.Errorf("rendering %s: %w", path, err)
 	}
 
 	existing, _ := os.ReadFile(path)
@@ -63,6 +132,9 @@ func syncFile(c *changelog.Changelog, cfg *changelog.Config, internal bool, path
 		return nil
 	}
 
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Dir(path), err)
+	}
 	if err := os.WriteFile(path, []byte(rendered), 0644); err != nil {
 		return fmt.Errorf("writing %s: %w", path, err)
 	}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ariel-frischer/chlog/pkg/changelog"
+)
+
+func TestSyncFile_CreatesDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "debian", "changelog")
+	render := func(*changelog.Changelog, ...changelog.RenderOptions) (string, error) { return "demo (1.0.0-1)\n", nil }
+
+	if err := syncFile(&changelog.Changelog{}, changelog.RenderOptions{}, path, render); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "demo (1.0.0-1)\n" {
+		t.Errorf("%s = %q, %v", path, data, err)
+	}
+}
+
+func TestRunSync_PackagingRejectsInternal(t *testing.T) {
+	initReleaseRepo(t)
+	writeFile(t, yamlFile, "project: demo\nversions:\n  1.0.0:\n    date: 2024-01-01\n    added:\n      - Login\n")
+	oldFormat, oldInternal := syncFormat, syncInternal
+	t.Cleanup(func() { syncFormat, syncInternal = oldFormat, oldInternal })
+	syncFormat, syncInternal = "debian", true
+
+	if err := runSync(syncCmd, nil); err == nil || !strings.Contains(err.Error(), "--internal") {
+		t.Errorf("err = %v, want --internal rejected", err)
+	}
+}
@@ -1,6 +1,11 @@
 package main
 
 import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+
 	"github.com/ariel-frischer/chlog/pkg/changelog"
 	"github.com/spf13/cobra"
 )
@@ -8,14 +13,60 @@ import (
 var validateCmd = &cobra.Command{
 	Use:   "validate",
 	Short: "Validate CHANGELOG.yaml schema",
-	RunE:  runValidate,
+	Long: `Validate CHANGELOG.yaml against the schema and .chlog.yaml settings.
+Errors are printed one per line as file:line:col: message, a format editors
+and CI problem matchers understand.
+
+--format json, sarif, junit or github write the errors as a report instead,
+each tagged with a stable rule ID such as bad-date or unknown-category.`,
+	RunE: runValidate,
+}
+
+var validateFormat string
+
+func init() {
+	validateCmd.Flags().StringVar(&validateFormat, "format", "text", "output format: text, json, sarif, junit or github")
 }
 
 func runValidate(cmd *cobra.Command, args []string) error {
-	_, err := changelog.Load(yamlFile)
+	format, err := changelog.ParseReportFormat(validateFormat)
 	if err != nil {
 		return err
 	}
-	success("%s is valid", fileRef("CHANGELOG.yaml"))
+	_, err = loadChangelog()
+	if format != changelog.ReportText {
+		finishReport(format, "validate", changelog.DiagnosticsFromError(yamlFile, err), 1)
+		return nil
+	}
+	if printValidationErrors(err) {
+		return fmt.Errorf("%s is invalid", yamlFile)
+	}
+	if err != nil {
+		return err
+	}
+	success("%s is valid", fileRef(yamlFile))
 	return nil
 }
+
+// printValidationErrors prints each error of a ValidationErrors to stderr
+// and reports whether err was one.
+func printValidationErrors(err error) bool {
+	var verrs changelog.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return false
+	}
+	for _, e := range verrs {
+		fmt.Fprintln(os.Stderr, errFmt.Sprint(validationLine(yamlFile, e)))
+	}
+	return true
+}
+
+// validationLine formats e as "file:line:col: field: message", leaving out
+// the position when it is unknown. Errors in fragments name the fragment.
+func validationLine(file string, e changelog.ValidationError) string {
+	file = cmp.Or(e.File, file)
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d:%d: %s: %s", file, e.Line, e.Column, e.Field, e.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", file, e.Field, e.Message)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ariel-frischer/chlog/pkg/changelog"
+)
+
+func TestValidationLine(t *testing.T) {
+	tests := map[string]struct {
+		err  changelog.ValidationError
+		want string
+	}{
+		"positioned": {
+			err:  changelog.ValidationError{Field: "versions[1].date", Message: "date required", Line: 9, Column: 3},
+			want: "CHANGELOG.yaml:9:3: versions[1].date: date required",
+		},
+		"fragment": {
+			err:  changelog.ValidationError{Field: "bogus", Message: "unknown category", File: ".chlog/unreleased/feat.yaml", Line: 3, Column: 1},
+			want: ".chlog/unreleased/feat.yaml:3:1: bogus: unknown category",
+		},
+		"unknown position": {
+			err:  changelog.ValidationError{Field: "version_scheme", Message: "unknown scheme"},
+			want: "CHANGELOG.yaml: version_scheme: unknown scheme",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := validationLine("CHANGELOG.yaml", tt.err); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunValidate_Invalid(t *testing.T) {
+	initReleaseRepo(t)
+	writeFile(t, yamlFile, "project: demo\nversions:\n  1.0.0:\n    added: [x]\n")
+	if err := runValidate(nil, nil); err == nil {
+		t.Fatal("expected an error for a release without a date")
+	}
+}
@@ -0,0 +1,400 @@
+package changelog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// AnnounceFormat is a chat platform of `chlog announce`.
+type AnnounceFormat string
+
+const (
+	AnnounceSlack      AnnounceFormat = "slack"
+	AnnounceDiscord    AnnounceFormat = "discord"
+	AnnounceTeams      AnnounceFormat = "teams"
+	AnnounceMattermost AnnounceFormat = "mattermost"
+)
+
+// ParseAnnounceFormat parses the --format value of `chlog announce`.
+func ParseAnnounceFormat(s string) (AnnounceFormat, error) {
+	switch f := AnnounceFormat(strings.ToLower(s)); f {
+	case AnnounceSlack, AnnounceDiscord, AnnounceTeams, AnnounceMattermost:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown announce format %q (valid: slack, discord, teams, mattermost)", s)
+}
+
+// Platform limits, in characters, that announcements are truncated to.
+const (
+	slackHeaderLimit  = 150  // header block text
+	slackSectionLimit = 3000 // section block text
+	discordTitleLimit = 256
+	discordFieldLimit = 1024
+	discordEmbedLimit = 6000 // all text of an embed
+	// teamsLimit keeps a card well below the 28 KB Teams message size.
+	teamsLimit      = 20000
+	mattermostLimit = 16383 // post message
+)
+
+// AnnounceOptions controls announcement rendering.
+type AnnounceOptions struct {
+	Format          AnnounceFormat
+	IncludeInternal bool
+	// Config links PR and issue references and the compare link.
+	Config *Config
+}
+
+// announcement is the platform-independent content of an announcement.
+type announcement struct {
+	Title      string
+	Date       string
+	CompareURL string
+	Categories []TemplateCategory
+}
+
+// subtitle returns the release date and compare link line, with link
+// rendering the compare link in the platform's syntax.
+func (a announcement) subtitle(link func(text, url string) string) string {
+	var parts []string
+	if a.Date != "" {
+		parts = append(parts, "Released "+a.Date)
+	}
+	if a.CompareURL != "" {
+		parts = append(parts, link("Compare changes", a.CompareURL))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// RenderAnnouncement renders a version of c as a JSON payload ready to POST
+// to an opts.Format incoming webhook: a header with the project and version,
+// the release date and compare link, and a section per category with its
+// icon. Sections are truncated to the platform's limits, noting how many
+// entries were left out.
+func RenderAnnouncement(c *Changelog, version string, opts AnnounceOptions) ([]byte, error) {
+	a, err := newAnnouncement(c, version, opts)
+	if err != nil {
+		return nil, err
+	}
+	var payload any
+	switch opts.Format {
+	case AnnounceSlack:
+		payload = slackPayload(a)
+	case AnnounceDiscord:
+		payload = discordPayload(a)
+	case AnnounceTeams:
+		payload = teamsPayload(a)
+	case AnnounceMattermost:
+		payload = mattermostPayload(a)
+	default:
+		return nil, fmt.Errorf("unknown announce format %q", opts.Format)
+	}
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(payload); err != nil {
+		return nil, fmt.Errorf("encoding %s payload: %w", opts.Format, err)
+	}
+	return b.Bytes(), nil
+}
+
+func newAnnouncement(c *Changelog, version string, opts AnnounceOptions) (announcement, error) {
+	v, err := c.GetVersion(version)
+	if err != nil {
+		return announcement{}, err
+	}
+	ropt := RenderOptions{IncludeInternal: opts.IncludeInternal, Config: opts.Config}
+	data := NewTemplateData(c, ropt, ResolveRepoURL(opts.Config))
+	for _, tv := range data.Versions {
+		if tv.Version != v.Version {
+			continue
+		}
+		title := strings.TrimSpace(c.Project + " " + versionLabel(v))
+		return announcement{Title: title, Date: tv.Date, CompareURL: tv.CompareURL, Categories: tv.Categories}, nil
+	}
+	return announcement{}, VersionNotFoundError{Version: version}
+}
+
+func categoryHeading(cat TemplateCategory) string {
+	return styleFor(cat.Name).Icon + " " + cat.Title
+}
+
+// fitSections renders the entries of each category as lines, each section
+// with its heading within sectionLimit and all of them within total.
+// Categories that no longer fit are dropped; omitted counts their entries.
+func fitSections(cats []TemplateCategory, heading func(TemplateCategory) string, line func(TemplateEntry) string, sectionLimit, total int) (bodies []string, omitted int) {
+	for i, cat := range cats {
+		overhead := runeLen(heading(cat)) + 1
+		limit := min(sectionLimit, total) - overhead
+		if limit < 40 {
+			for _, rest := range cats[i:] {
+				omitted += len(rest.Entries)
+			}
+			break
+		}
+		lines := make([]string, len(cat.Entries))
+		for j, e := range cat.Entries {
+			lines[j] = line(e)
+		}
+		body := fitLines(lines, limit)
+		total -= overhead + runeLen(body) + 1
+		bodies = append(bodies, body)
+	}
+	return bodies, omitted
+}
+
+// fitLines joins lines with newlines, dropping lines from the end, and
+// shortening any line longer than the limit, so the text with a note of how
+// many lines were dropped fits in limit characters.
+func fitLines(lines []string, limit int) string {
+	maxLine := limit - runeLen("…and 999 more") - 1
+	for i, l := range lines {
+		lines[i] = truncateText(l, maxLine)
+	}
+	text := strings.Join(lines, "\n")
+	for n := len(lines) - 1; runeLen(text) > limit && n >= 0; n-- {
+		text = strings.Join(append(lines[:n:n], fmt.Sprintf("…and %d more", len(lines)-n)), "\n")
+	}
+	return text
+}
+
+// truncateText shortens s to at most limit characters, ending in "…".
+func truncateText(s string, limit int) string {
+	if runeLen(s) <= limit {
+		return s
+	}
+	if limit <= 0 {
+		return ""
+	}
+	return string([]rune(s)[:limit-1]) + "…"
+}
+
+func runeLen(s string) int {
+	return utf8.RuneCountInString(s)
+}
+
+func moreChanges(n int) string {
+	return fmt.Sprintf("…and %d more change%s", n, plural(n))
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
+
+var (
+	// markdownLink allows one level of balanced parentheses in the URL, as
+	// in "https://e.com/a_(b)".
+	markdownLink = regexp.MustCompile(`\[([^\]]+)\]\(((?:[^()\s]|\([^()\s]*\))+)\)`)
+	markdownBold = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+)
+
+// slackMrkdwn converts the inline markdown of an entry to Slack mrkdwn.
+func slackMrkdwn(md string) string {
+	s := markdownLink.ReplaceAllString(slackEscaper.Replace(md), "<$2|$1>")
+	return markdownBold.ReplaceAllString(s, "*$1*")
+}
+
+type slackText struct {
+	Type  string `json:"type"`
+	Text  string `json:"text"`
+	Emoji bool   `json:"emoji,omitempty"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+func slackPayload(a announcement) slackMessage {
+	title := truncateText(a.Title, slackHeaderLimit)
+	msg := slackMessage{Text: title, Blocks: []slackBlock{
+		{Type: "header", Text: &slackText{Type: "plain_text", Text: title, Emoji: true}},
+	}}
+	if sub := a.subtitle(func(text, url string) string { return "<" + url + "|" + text + ">" }); sub != "" {
+		msg.Blocks = append(msg.Blocks, slackBlock{Type: "context", Elements: []slackText{{Type: "mrkdwn", Text: sub}}})
+	}
+	heading := func(cat TemplateCategory) string { return "*" + categoryHeading(cat) + "*" }
+	line := func(e TemplateEntry) string { return "• " + slackMrkdwn(e.Markdown) }
+	bodies, omitted := fitSections(a.Categories, heading, line, slackSectionLimit, math.MaxInt32)
+	for i, body := range bodies {
+		text := heading(a.Categories[i]) + "\n" + body
+		msg.Blocks = append(msg.Blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: text}})
+	}
+	if omitted > 0 {
+		msg.Blocks = append(msg.Blocks, slackBlock{Type: "context", Elements: []slackText{{Type: "mrkdwn", Text: moreChanges(omitted)}}})
+	}
+	return msg
+}
+
+type discordField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title"`
+	URL         string         `json:"url,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Color       int            `json:"color,omitempty"`
+	Timestamp   string         `json:"timestamp,omitempty"`
+	Fields      []discordField `json:"fields,omitempty"`
+}
+
+type discordMessage struct {
+	Embeds []discordEmbed `json:"embeds"`
+}
+
+func discordPayload(a announcement) discordMessage {
+	embed := discordEmbed{Title: truncateText(a.Title, discordTitleLimit), URL: a.CompareURL}
+	if a.Date != "" {
+		embed.Description = "Released " + a.Date
+		embed.Timestamp = a.Date + "T00:00:00Z"
+	}
+	if len(a.Categories) > 0 {
+		css := styleFor(a.Categories[0].Name).CSS
+		color, _ := strconv.ParseInt(strings.TrimPrefix(css, "#"), 16, 32)
+		embed.Color = int(color)
+	}
+	// A note of omitted changes needs room in the description.
+	budget := discordEmbedLimit - runeLen(embed.Title) - runeLen(embed.Description) - 40
+	line := func(e TemplateEntry) string { return "- " + e.Markdown }
+	bodies, omitted := fitSections(a.Categories, categoryHeading, line, discordFieldLimit, budget)
+	for i, body := range bodies {
+		embed.Fields = append(embed.Fields, discordField{Name: categoryHeading(a.Categories[i]), Value: body})
+	}
+	if omitted > 0 {
+		embed.Description = strings.TrimSpace(embed.Description + "\n" + moreChanges(omitted))
+	}
+	return discordMessage{Embeds: []discordEmbed{embed}}
+}
+
+type teamsElement struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	Size     string `json:"size,omitempty"`
+	Weight   string `json:"weight,omitempty"`
+	IsSubtle bool   `json:"isSubtle,omitempty"`
+	Spacing  string `json:"spacing,omitempty"`
+	Wrap     bool   `json:"wrap,omitempty"`
+	Title    string `json:"title,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
+type teamsCard struct {
+	Schema  string         `json:"$schema"`
+	Type    string         `json:"type"`
+	Version string         `json:"version"`
+	Body    []teamsElement `json:"body"`
+	Actions []teamsElement `json:"actions,omitempty"`
+}
+
+type teamsAttachment struct {
+	ContentType string    `json:"contentType"`
+	Content     teamsCard `json:"content"`
+}
+
+type teamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+func teamsPayload(a announcement) teamsMessage {
+	card := teamsCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body:    []teamsElement{{Type: "TextBlock", Text: a.Title, Size: "Large", Weight: "Bolder", Wrap: true}},
+	}
+	if a.Date != "" {
+		card.Body = append(card.Body, teamsElement{Type: "TextBlock", Text: "Released " + a.Date, IsSubtle: true, Spacing: "None"})
+	}
+	heading := func(cat TemplateCategory) string { return "**" + categoryHeading(cat) + "**" }
+	line := func(e TemplateEntry) string { return "- " + e.Markdown }
+	bodies, omitted := fitSections(a.Categories, heading, line, teamsLimit, teamsLimit)
+	for i, body := range bodies {
+		card.Body = append(card.Body,
+			// Bold markup keeps a "+" or "-" icon from starting a list.
+			teamsElement{Type: "TextBlock", Text: heading(a.Categories[i]), Wrap: true},
+			// Adaptive Card markdown separates list items with \r.
+			teamsElement{Type: "TextBlock", Text: strings.ReplaceAll(body, "\n", "\r"), Spacing: "Small", Wrap: true},
+		)
+	}
+	if omitted > 0 {
+		card.Body = append(card.Body, teamsElement{Type: "TextBlock", Text: moreChanges(omitted), IsSubtle: true, Wrap: true})
+	}
+	if a.CompareURL != "" {
+		card.Actions = []teamsElement{{Type: "Action.OpenUrl", Title: "Compare changes", URL: a.CompareURL}}
+	}
+	return teamsMessage{Type: "message", Attachments: []teamsAttachment{
+		{ContentType: "application/vnd.microsoft.card.adaptive", Content: card},
+	}}
+}
+
+type mattermostMessage struct {
+	Text string `json:"text"`
+}
+
+func mattermostPayload(a announcement) mattermostMessage {
+	var b strings.Builder
+	b.WriteString("#### " + a.Title + "\n")
+	if sub := a.subtitle(func(text, url string) string { return "[" + text + "](" + url + ")" }); sub != "" {
+		b.WriteString(sub + "\n")
+	}
+	heading := func(cat TemplateCategory) string { return "**" + categoryHeading(cat) + "**" }
+	line := func(e TemplateEntry) string { return "- " + e.Markdown }
+	budget := mattermostLimit - runeLen(b.String()) - 40
+	bodies, omitted := fitSections(a.Categories, heading, line, budget, budget)
+	for i, body := range bodies {
+		fmt.Fprintf(&b, "\n%s\n%s\n", heading(a.Categories[i]), body)
+	}
+	if omitted > 0 {
+		b.WriteString("\n" + moreChanges(omitted) + "\n")
+	}
+	return mattermostMessage{Text: b.String()}
+}
+
+// PostWebhook POSTs a payload from RenderAnnouncement to an incoming webhook
+// and fails unless it answers 2xx. Errors leave out the URL, which usually
+// embeds a secret token.
+func PostWebhook(ctx context.Context, client *http.Client, webhookURL string, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return errors.New("invalid webhook URL")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return fmt.Errorf("posting to webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook answered %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package changelog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func announceChangelog() *Changelog {
+	c := &Changelog{Project: "demo", Versions: []Version{
+		{Version: "1.1.0", Date: "2026-03-02"},
+		{Version: "1.0.0", Date: "2026-01-05"},
+	}}
+	c.Versions[0].Public.AppendWithMeta("added", "Export to **CSV** & <TSV>", EntryMeta{PR: 42})
+	c.Versions[0].Public.Append("removed", "Dropped `--legacy`")
+	c.Versions[0].Internal.Append("changed", "Refactored the store")
+	c.Versions[1].Public.Append("added", "First release")
+	return c
+}
+
+var announceConfig = &Config{RepoURL: "https://github.com/acme/demo"}
+
+func TestSlackMrkdwn(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want string
+	}{
+		"plain":   {"Login page", "Login page"},
+		"escapes": {"a < b & c > d", "a &lt; b &amp; c &gt; d"},
+		"link":    {"Fix ([#12](https://x.dev/pull/12))", "Fix (<https://x.dev/pull/12|#12>)"},
+		"parens":  {"See [docs](https://e.com/a_(b)).", "See <https://e.com/a_(b)|docs>."},
+		"bold":    {"**BREAKING:** **api:** Drop v1", "*BREAKING:* *api:* Drop v1"},
+		"code":    {"Use `--json`", "Use `--json`"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := slackMrkdwn(tt.in); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderAnnouncement(t *testing.T) {
+	tests := map[string]struct {
+		format   AnnounceFormat
+		internal bool
+		want     []string
+	}{
+		"slack": {
+			format: AnnounceSlack,
+			want: []string{
+				`"text": "demo 1.1.0"`,
+				`"type": "header"`,
+				`"text": "Released 2026-03-02 · <https://github.com/acme/demo/compare/v1.0.0...v1.1.0|Compare changes>"`,
+				`"text": "*+ Added*\n• Export to *CSV* &amp; &lt;TSV&gt; (<https://github.com/acme/demo/pull/42|#42>)"`,
+				`"text": "*- Removed*\n• Dropped ` + "`--legacy`" + `"`,
+			},
+		},
+		"discord": {
+			format: AnnounceDiscord,
+			want: []string{
+				`"title": "demo 1.1.0"`,
+				`"url": "https://github.com/acme/demo/compare/v1.0.0...v1.1.0"`,
+				`"timestamp": "2026-03-02T00:00:00Z"`,
+				`"color": 1736503`,
+				`"name": "+ Added"`,
+				`"value": "- Export to **CSV** & <TSV> ([#42](https://github.com/acme/demo/pull/42))"`,
+			},
+		},
+		"teams": {
+			format:   AnnounceTeams,
+			internal: true,
+			want: []string{
+				`"contentType": "application/vnd.microsoft.card.adaptive"`,
+				`"type": "AdaptiveCard"`,
+				`"text": "**~ Changed**"`,
+				`"text": "- Refactored the store"`,
+				`"type": "Action.OpenUrl"`,
+			},
+		},
+		"mattermost": {
+			format: AnnounceMattermost,
+			want: []string{
+				`"text": "#### demo 1.1.0\nReleased 2026-03-02 · [Compare changes](https://github.com/acme/demo/compare/v1.0.0...v1.1.0)\n\n**+ Added**\n- Export`,
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := RenderAnnouncement(announceChangelog(), "v1.1.0", AnnounceOptions{Format: tt.format, IncludeInternal: tt.internal, Config: announceConfig})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !json.Valid(out) {
+				t.Fatalf("invalid JSON:\n%s", out)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(string(out), w) {
+					t.Errorf("missing %s in\n%s", w, out)
+				}
+			}
+			if !tt.internal && strings.Contains(string(out), "Refactored") {
+				t.Errorf("internal entry in payload:\n%s", out)
+			}
+		})
+	}
+}
+
+func TestRenderAnnouncement_UnknownVersion(t *testing.T) {
+	_, err := RenderAnnouncement(announceChangelog(), "9.9.9", AnnounceOptions{Format: AnnounceSlack, Config: announceConfig})
+	if _, ok := err.(VersionNotFoundError); !ok {
+		t.Errorf("err = %v, want VersionNotFoundError", err)
+	}
+}
+
+func TestRenderAnnouncement_Truncates(t *testing.T) {
+	c := &Changelog{Project: "demo", Versions: []Version{{Version: "2.0.0", Date: "2026-03-02"}}}
+	for _, cat := range []string{"added", "changed", "fixed", "removed", "security", "deprecated", "docs"} {
+		for i := range 40 {
+			c.Versions[0].Public.Append(cat, fmt.Sprintf("Entry %d of %s %s", i, cat, strings.Repeat("long text ", 20)))
+		}
+	}
+	c.Versions[0].Public.Append("fixed", strings.Repeat("x", 5000))
+	opts := AnnounceOptions{Config: announceConfig}
+
+	opts.Format = AnnounceSlack
+	out, err := RenderAnnouncement(c, "2.0.0", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var slack slackMessage
+	if err := json.Unmarshal(out, &slack); err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range slack.Blocks {
+		if b.Text != nil && runeLen(b.Text.Text) > slackSectionLimit {
+			t.Errorf("slack block of %d characters", runeLen(b.Text.Text))
+		}
+	}
+	if !strings.Contains(string(out), "more\"") {
+		t.Error("slack sections do not note dropped entries")
+	}
+
+	opts.Format = AnnounceDiscord
+	if out, err = RenderAnnouncement(c, "2.0.0", opts); err != nil {
+		t.Fatal(err)
+	}
+	var discord discordMessage
+	if err := json.Unmarshal(out, &discord); err != nil {
+		t.Fatal(err)
+	}
+	embed := discord.Embeds[0]
+	total := runeLen(embed.Title) + runeLen(embed.Description)
+	for _, f := range embed.Fields {
+		if runeLen(f.Value) > discordFieldLimit {
+			t.Errorf("discord field of %d characters", runeLen(f.Value))
+		}
+		total += runeLen(f.Name) + runeLen(f.Value)
+	}
+	if total > discordEmbedLimit {
+		t.Errorf("discord embed of %d characters", total)
+	}
+	if !strings.Contains(string(out), "more") {
+		t.Error("discord fields do not note dropped entries")
+	}
+}
+
+func TestFitLines(t *testing.T) {
+	tests := map[string]struct {
+		lines []string
+		limit int
+		want  string
+	}{
+		"fits":      {[]string{"- a", "- b"}, 100, "- a\n- b"},
+		"drops":     {[]string{"- " + strings.Repeat("a", 40), "- " + strings.Repeat("b", 40), "- c"}, 70, "- " + strings.Repeat("a", 40) + "\n…and 2 more"},
+		"long line": {[]string{strings.Repeat("a", 100)}, 60, strings.Repeat("a", 45) + "…"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := fitLines(tt.lines, tt.limit); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostWebhook(t *testing.T) {
+	var gotBody, gotType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBody, gotType = string(body), r.Header.Get("Content-Type")
+		if strings.HasSuffix(r.URL.Path, "/bad") {
+			http.Error(w, "invalid_payload", http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	if err := PostWebhook(context.Background(), srv.Client(), srv.URL+"/hooks/secret", []byte(`{"text":"hi"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if gotBody != `{"text":"hi"}` || gotType != "application/json" {
+		t.Errorf("body = %q, content type = %q", gotBody, gotType)
+	}
+
+	err := PostWebhook(context.Background(), srv.Client(), srv.URL+"/secret/bad", []byte(`{}`))
+	if err == nil || !strings.Contains(err.Error(), "400 Bad Request: invalid_payload") {
+		t.Errorf("err = %v", err)
+	}
+
+	srv.Close()
+	err = PostWebhook(context.Background(), srv.Client(), srv.URL+"/secret", []byte(`{}`))
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("err = %v, want an error without the URL", err)
+	}
+}
+
+func TestFitSections_DropsCategories(t *testing.T) {
+	cats := []TemplateCategory{
+		{Name: "added", Title: "Added", Entries: []TemplateEntry{{Markdown: strings.Repeat("a", 80)}}},
+		{Name: "fixed", Title: "Fixed", Entries: []TemplateEntry{{Markdown: "b"}, {Markdown: "c"}}},
+	}
+	line := func(e TemplateEntry) string { return "- " + e.Markdown }
+	bodies, omitted := fitSections(cats, categoryHeading, line, 1000, 100)
+	if len(bodies) != 1 || omitted != 2 {
+		t.Errorf("bodies = %q, omitted = %d; want 1 body and 2 omitted", bodies, omitted)
+	}
+}
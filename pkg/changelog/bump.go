@@ -0,0 +1,213 @@
+package changelog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BumpLevel is the size of a semantic version increment.
+type BumpLevel int
+
+const (
+	BumpNone BumpLevel = iota
+	BumpPatch
+	BumpMinor
+	BumpMajor
+)
+
+var bumpLevelNames = []string{"none", "patch", "minor", "major"}
+
+func (l BumpLevel) String() string {
+	if l < BumpNone || l > BumpMajor {
+		return fmt.Sprintf("BumpLevel(%d)", int(l))
+	}
+	return bumpLevelNames[l]
+}
+
+// ParseBumpLevel converts "none", "patch", "minor" or "major" to a BumpLevel.
+func ParseBumpLevel(s string) (BumpLevel, error) {
+	for i, name := range bumpLevelNames {
+		if s == name {
+			return BumpLevel(i), nil
+		}
+	}
+	return BumpNone, fmt.Errorf("unknown bump level %q (valid: major, minor, patch, none)", s)
+}
+
+// DefaultBumpRules maps Keep a Changelog categories to the increment they
+// require. Categories not listed bump the patch version.
+var DefaultBumpRules = map[string]BumpLevel{
+	"added":      BumpMinor,
+	"changed":    BumpMinor,
+	"deprecated": BumpMinor,
+	"removed":    BumpMajor,
+	"fixed":      BumpPatch,
+	"security":   BumpPatch,
+}
+
+// BumpOptions controls NextVersion.
+type BumpOptions struct {
+	// Rules override DefaultBumpRules per category.
+	Rules map[string]BumpLevel
+	// Pre produces a pre-release such as "1.3.0-rc.1" when set to "rc",
+	// numbered after any existing pre-releases of the same version.
+	Pre string
+}
+
+// Bump describes a computed version increment.
+type Bump struct {
+	// Latest is the latest release, or empty if there is none.
+	Latest string
+	Next   string
+	Level  BumpLevel
+	// Reason names what decided the level, e.g. "breaking change" or "added".
+	Reason string
+}
+
+// breakingPrefixes mark an entry as breaking when it carries no metadata,
+// as written by scaffold for "feat!:" commits.
+var breakingPrefixes = []string{"BREAKING:", "BREAKING CHANGE:"}
+
+// BumpLevelFor returns the increment a version's public changes require:
+// major for any breaking entry, otherwise the highest category rule.
+// Internal-only changes require a patch release.
+func BumpLevelFor(v *Version, rules map[string]BumpLevel) (BumpLevel, string) {
+	level, reason := BumpNone, ""
+	for _, cat := range v.Public.Categories {
+		for i, text := range cat.Entries {
+			if isBreakingEntry(text, cat.MetaAt(i)) {
+				return BumpMajor, "breaking change"
+			}
+		}
+		if len(cat.Entries) == 0 {
+			continue
+		}
+		if l := categoryBump(cat.Name, rules); l > level {
+			level, reason = l, cat.Name
+		}
+	}
+	if v.Public.IsEmpty() && !v.Internal.IsEmpty() {
+		return BumpPatch, "internal changes"
+	}
+	return level, reason
+}
+
+func isBreakingEntry(text string, meta EntryMeta) bool {
+	if meta.Breaking {
+		return true
+	}
+	for _, p := range breakingPrefixes {
+		if strings.HasPrefix(text, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func categoryBump(category string, rules map[string]BumpLevel) BumpLevel {
+	if l, ok := rules[category]; ok {
+		return l
+	}
+	if l, ok := DefaultBumpRules[category]; ok {
+		return l
+	}
+	return BumpPatch
+}
+
+// NextVersion computes the semantic version the unreleased changes should be
+// released as, based on BumpLevelFor and the latest stable release. Before
+// 1.0.0 a major increment bumps the minor version instead. A pending
+// pre-release of a higher version (e.g. 2.0.0-rc.1) is continued rather than
+// skipped, so releasing without Pre finalizes it.
+func (c *Changelog) NextVersion(opts ...BumpOptions) (Bump, error) {
+	var opt BumpOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	unreleased := c.GetUnreleased()
+	if unreleased == nil || (unreleased.IsEmpty() && unreleased.Internal.IsEmpty()) {
+		return Bump{}, fmt.Errorf("no unreleased changes to release")
+	}
+	b := Bump{}
+	b.Level, b.Reason = BumpLevelFor(unreleased, opt.Rules)
+	if b.Level == BumpNone {
+		return Bump{}, fmt.Errorf("unreleased changes do not require a version bump")
+	}
+
+	stable, pending, err := c.semverBase()
+	if err != nil {
+		return Bump{}, err
+	}
+	if latest := c.GetLatestRelease(); latest != nil {
+		b.Latest = latest.Version
+	}
+	next := bumpSemver(stable, b.Level)
+	if pending != nil && pending.Compare(next) > 0 {
+		next = SemVer{Major: pending.Major, Minor: pending.Minor, Patch: pending.Patch}
+	}
+	if opt.Pre != "" {
+		next.Prerelease = fmt.Sprintf("%s.%d", opt.Pre, c.nextPrereleaseNumber(next, opt.Pre))
+	}
+	b.Next = next.String()
+	return b, nil
+}
+
+// semverBase returns the highest stable release (0.0.0 if none) and the
+// highest pre-release above it, if any.
+func (c *Changelog) semverBase() (SemVer, *SemVer, error) {
+	var stable SemVer
+	var pending *SemVer
+	for _, v := range c.Versions {
+		if v.IsUnreleased() {
+			continue
+		}
+		sv, err := ParseSemver(v.Version)
+		if err != nil {
+			return SemVer{}, nil, fmt.Errorf("released versions must be semver to bump: %w", err)
+		}
+		switch {
+		case !sv.IsPrerelease() && sv.Compare(stable) > 0:
+			stable = sv
+		case sv.IsPrerelease() && (pending == nil || sv.Compare(*pending) > 0):
+			pending = &sv
+		}
+	}
+	if pending != nil && pending.Compare(stable) < 0 {
+		pending = nil
+	}
+	return stable, pending, nil
+}
+
+func bumpSemver(v SemVer, level BumpLevel) SemVer {
+	if level == BumpMajor && v.Major == 0 {
+		level = BumpMinor
+	}
+	switch level {
+	case BumpMajor:
+		return SemVer{Major: v.Major + 1}
+	case BumpMinor:
+		return SemVer{Major: v.Major, Minor: v.Minor + 1}
+	}
+	return SemVer{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+}
+
+// nextPrereleaseNumber returns one more than the highest N among existing
+// "<core>-<pre>.N" versions, or 1.
+func (c *Changelog) nextPrereleaseNumber(core SemVer, pre string) int {
+	n := 0
+	for _, v := range c.Versions {
+		sv, err := ParseSemver(v.Version)
+		if err != nil || sv.Major != core.Major || sv.Minor != core.Minor || sv.Patch != core.Patch {
+			continue
+		}
+		num, ok := strings.CutPrefix(sv.Prerelease, pre+".")
+		if !ok {
+			continue
+		}
+		if i, err := strconv.Atoi(num); err == nil && i > n {
+			n = i
+		}
+	}
+	return n + 1
+}
@@ -0,0 +1,116 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+)
+
+// bumpChangelog builds a changelog with the given released versions, newest
+// first, and unreleased entries in the given category.
+func bumpChangelog(category string, released ...string) *Changelog {
+	c := &Changelog{Project: "test", Versions: []Version{{Version: "unreleased"}}}
+	if category != "" {
+		c.Versions[0].Public.Append(category, "Change")
+	}
+	for _, name := range released {
+		v := Version{Version: name, Date: "2024-01-01"}
+		v.Public.Append("added", "Entry")
+		c.Versions = append(c.Versions, v)
+	}
+	return c
+}
+
+func TestNextVersion(t *testing.T) {
+	tests := map[string]struct {
+		changelog *Changelog
+		opts      BumpOptions
+		want      string
+		level     BumpLevel
+	}{
+		"fixed":          {changelog: bumpChangelog("fixed", "1.2.3"), want: "1.2.4", level: BumpPatch},
+		"added":          {changelog: bumpChangelog("added", "1.2.3"), want: "1.3.0", level: BumpMinor},
+		"removed":        {changelog: bumpChangelog("removed", "1.2.3"), want: "2.0.0", level: BumpMajor},
+		"pre-1.0 major":  {changelog: bumpChangelog("removed", "0.4.1"), want: "0.5.0", level: BumpMajor},
+		"first release":  {changelog: bumpChangelog("added"), want: "0.1.0", level: BumpMinor},
+		"custom rule":    {changelog: bumpChangelog("removed", "1.2.3"), opts: BumpOptions{Rules: map[string]BumpLevel{"removed": BumpMinor}}, want: "1.3.0", level: BumpMinor},
+		"unknown cat":    {changelog: bumpChangelog("perf", "1.2.3"), want: "1.2.4", level: BumpPatch},
+		"v prefix":       {changelog: bumpChangelog("fixed", "v1.0.0"), want: "1.0.1", level: BumpPatch},
+		"pre first":      {changelog: bumpChangelog("added", "1.2.3"), opts: BumpOptions{Pre: "rc"}, want: "1.3.0-rc.1", level: BumpMinor},
+		"pre increments": {changelog: bumpChangelog("fixed", "1.3.0-rc.2", "1.3.0-rc.1", "1.2.3"), opts: BumpOptions{Pre: "rc"}, want: "1.3.0-rc.3", level: BumpPatch},
+		"pre other id":   {changelog: bumpChangelog("fixed", "1.3.0-beta.4", "1.2.3"), opts: BumpOptions{Pre: "rc"}, want: "1.3.0-rc.1", level: BumpPatch},
+		"finalize pre":   {changelog: bumpChangelog("fixed", "1.3.0-rc.2", "1.2.3"), want: "1.3.0", level: BumpPatch},
+		"outgrow pre":    {changelog: bumpChangelog("removed", "1.3.0-rc.1", "1.2.3"), want: "2.0.0", level: BumpMajor},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := tt.changelog.NextVersion(tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Next != tt.want || got.Level != tt.level {
+				t.Errorf("NextVersion = %s (%s), want %s (%s)", got.Next, got.Level, tt.want, tt.level)
+			}
+		})
+	}
+}
+
+func TestNextVersion_Errors(t *testing.T) {
+	tests := map[string]struct {
+		changelog *Changelog
+		opts      BumpOptions
+		wantErr   string
+	}{
+		"empty unreleased": {changelog: bumpChangelog("", "1.0.0"), wantErr: "no unreleased changes"},
+		"non-semver":       {changelog: bumpChangelog("added", "2024.06"), wantErr: "must be semver"},
+		"rule none":        {changelog: bumpChangelog("changed", "1.0.0"), opts: BumpOptions{Rules: map[string]BumpLevel{"changed": BumpNone}}, wantErr: "do not require"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := tt.changelog.NextVersion(tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBumpLevelFor(t *testing.T) {
+	tests := map[string]struct {
+		build  func(v *Version)
+		want   BumpLevel
+		reason string
+	}{
+		"breaking meta": {func(v *Version) {
+			v.Public.Append("fixed", "Fix")
+			v.Public.AppendWithMeta("fixed", "Drop flag", EntryMeta{Breaking: true})
+		}, BumpMajor, "breaking change"},
+		"breaking prefix": {func(v *Version) { v.Public.Append("changed", "BREAKING: new API") }, BumpMajor, "breaking change"},
+		"highest wins": {func(v *Version) {
+			v.Public.Append("fixed", "Fix")
+			v.Public.Append("added", "Feature")
+		}, BumpMinor, "added"},
+		"internal only": {func(v *Version) { v.Internal.Append("changed", "Refactor") }, BumpPatch, "internal changes"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			v := &Version{Version: "unreleased"}
+			tt.build(v)
+			got, reason := BumpLevelFor(v, nil)
+			if got != tt.want || reason != tt.reason {
+				t.Errorf("BumpLevelFor = %s (%s), want %s (%s)", got, reason, tt.want, tt.reason)
+			}
+		})
+	}
+}
+
+func TestParseBumpLevel(t *testing.T) {
+	for _, l := range []BumpLevel{BumpNone, BumpPatch, BumpMinor, BumpMajor} {
+		got, err := ParseBumpLevel(l.String())
+		if err != nil || got != l {
+			t.Errorf("ParseBumpLevel(%q) = %v, %v", l.String(), got, err)
+		}
+	}
+	if _, err := ParseBumpLevel("huge"); err == nil {
+		t.Error("expected error for unknown level")
+	}
+}
@@ -0,0 +1,135 @@
+package changelog
+
+import (
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FormatYAML normalizes c in place and returns its canonical YAML, as
+// written by `chlog fmt`: categories follow cfg's Categories (or
+// DefaultCategories) with unknown ones after them, entries are trimmed and
+// quoted only where needed, version keys are quoted only when YAML would
+// misread them, and each version lists date first and internal last.
+// Comments and single blank lines are kept. Load c without fragments, as
+// their entries would otherwise be written into the file.
+func FormatYAML(c *Changelog, cfg *Config) ([]byte, error) {
+	order := DefaultCategories
+	if cfg != nil && len(cfg.Categories) > 0 {
+		order = cfg.Categories
+	}
+	rank := func(key string) int {
+		switch key {
+		case "date":
+			return -1
+		case "internal":
+			return len(order) + 1
+		}
+		if i := slices.Index(order, key); i >= 0 {
+			return i
+		}
+		return len(order)
+	}
+
+	for i := range c.Versions {
+		v := &c.Versions[i]
+		formatChanges(&v.Public, rank)
+		formatChanges(&v.Internal, rank)
+		if v.key != nil {
+			v.key.Tag, v.key.Style = versionKeyNode(v.key.Value).Tag, 0
+		}
+		if v.node != nil && v.node.Kind == yaml.MappingNode {
+			formatVersionNode(v.node, rank)
+		}
+	}
+	if c.source != nil {
+		c.source.raw = tidyLines(c.source.raw)
+	}
+	return Marshal(c)
+}
+
+// formatChanges orders categories by rank and trims their entries.
+func formatChanges(changes *Changes, rank func(string) int) {
+	slices.SortStableFunc(changes.Categories, func(a, b CategoryEntry) int {
+		return rank(a.Name) - rank(b.Name)
+	})
+	for i := range changes.Categories {
+		entries := changes.Categories[i].Entries
+		for j := range entries {
+			entries[j] = strings.TrimSpace(entries[j])
+		}
+	}
+}
+
+// formatVersionNode applies formatChanges to the nodes a version was decoded
+// from, so Save keeps them and their comments.
+func formatVersionNode(node *yaml.Node, rank func(string) int) {
+	sortPairs(node, rank)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, val := node.Content[i], node.Content[i+1]
+		switch key.Value {
+		case "date":
+		case "internal":
+			if val.Kind == yaml.MappingNode {
+				sortPairs(val, rank)
+				for j := 1; j < len(val.Content); j += 2 {
+					formatEntryNodes(val.Content[j])
+				}
+			}
+		default:
+			formatEntryNodes(val)
+		}
+	}
+}
+
+// sortPairs stably orders a mapping's key/value pairs by the rank of the key.
+func sortPairs(node *yaml.Node, rank func(string) int) {
+	pairs := make([][2]*yaml.Node, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		pairs = append(pairs, [2]*yaml.Node{node.Content[i], node.Content[i+1]})
+	}
+	slices.SortStableFunc(pairs, func(a, b [2]*yaml.Node) int {
+		return rank(a[0].Value) - rank(b[0].Value)
+	})
+	node.Content = node.Content[:0]
+	for _, p := range pairs {
+		node.Content = append(node.Content, p[0], p[1])
+	}
+}
+
+// formatEntryNodes trims the entry texts of a category sequence and lets the
+// encoder choose their quoting; block scalars stay block scalars.
+func formatEntryNodes(seq *yaml.Node) {
+	if seq.Kind != yaml.SequenceNode {
+		return
+	}
+	for _, item := range seq.Content {
+		if item.Kind == yaml.MappingNode {
+			item = mappingValue(item, "text")
+		}
+		if item == nil || item.Kind != yaml.ScalarNode {
+			continue
+		}
+		item.Value = strings.TrimSpace(item.Value)
+		if item.Style&(yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+			item.Style = 0
+		}
+	}
+}
+
+// tidyLines drops trailing whitespace and repeated blank lines from the
+// source, which Save would otherwise carry over.
+func tidyLines(raw []byte) []byte {
+	var b strings.Builder
+	blank := true
+	for _, line := range splitLines(raw) {
+		line = strings.TrimRight(line, " \t")
+		if line == "" && blank {
+			continue
+		}
+		blank = line == ""
+		b.WriteString(line + "\n")
+	}
+	return []byte(b.String())
+}
@@ -0,0 +1,89 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatYAML(t *testing.T) {
+	input := `project: demo
+versions:
+  "unreleased":
+    fixed:
+      - "  Crash on save  "   
+    internal:
+      added: ["Tests"]
+    # about added
+    added:
+      - "Login page"
+      - text: "  Dark mode "
+        pr: 4
+
+
+  "1.0":
+    custom:
+      - Other
+    added:
+      - Search
+    date: 2024-01-01
+`
+	want := `project: demo
+versions:
+  unreleased:
+    # about added
+    added:
+      - Login page
+      - text: Dark mode
+        pr: 4
+    fixed:
+      - Crash on save
+    internal:
+      added: [Tests]
+
+  "1.0":
+    date: 2024-01-01
+    added:
+      - Search
+    custom:
+      - Other
+`
+	strict := false
+	cfg := &Config{StrictCategories: &strict}
+	c, err := LoadFromReader(strings.NewReader(input), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := FormatYAML(c, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if cat := c.Versions[0].Public.Categories[1]; cat.Name != "fixed" || cat.Entries[0] != "Crash on save" {
+		t.Errorf("changelog not normalized: %+v", c.Versions[0].Public.Categories)
+	}
+
+	// Formatting is idempotent.
+	c, err = LoadFromReader(strings.NewReader(want), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := FormatYAML(c, nil); string(again) != want {
+		t.Errorf("second pass changed the output:\n%s", again)
+	}
+}
+
+func TestFormatYAML_ConfiguredOrder(t *testing.T) {
+	c := &Changelog{Project: "demo", Versions: []Version{{Version: "1.0.0", Date: "2024-01-01"}}}
+	c.Versions[0].Public.Append("added", "Search")
+	c.Versions[0].Public.Append("fixed", " Crash ")
+	got, err := FormatYAML(c, &Config{Categories: []string{"fixed", "added"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "project: demo\nversions:\n    1.0.0:\n        date: 2024-01-01\n        fixed:\n            - Crash\n        added:\n            - Search\n"
+	if string(got) != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
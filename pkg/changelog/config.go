@@ -3,6 +3,8 @@ package changelog
 import (
 	"fmt"
 	"os"
+	"strings"
+	"text/template"
 
 	"gopkg.in/yaml.v3"
 )
@@ -11,17 +13,129 @@ const (
 	DefaultConfigFile   = ".chlog.yaml"
 	DefaultPublicFile   = "CHANGELOG.md"
 	DefaultInternalFile = "CHANGELOG-internal.md"
+	DefaultTagPrefix    = "v"
+	// DefaultCommitMessage is the text/template for release commits.
+	DefaultCommitMessage = "release: {{.Tag}}"
 )
 
 // Config holds project-specific chlog settings.
 type Config struct {
-	RepoURL          string   `yaml:"repo_url,omitempty"`
-	ChangelogFile    string   `yaml:"changelog_file,omitempty"`
-	PublicFile       string   `yaml:"public_file,omitempty"`
-	InternalFile     string   `yaml:"internal_file,omitempty"`
-	IncludeInternal  bool     `yaml:"include_internal,omitempty"`
-	Categories       []string `yaml:"categories,omitempty"`
-	StrictCategories *bool    `yaml:"strict_categories,omitempty"`
+	RepoURL          string            `yaml:"repo_url,omitempty"`
+	ChangelogFile    string            `yaml:"changelog_file,omitempty"`
+	PublicFile       string            `yaml:"public_file,omitempty"`
+	InternalFile     string            `yaml:"internal_file,omitempty"`
+	IncludeInternal  bool              `yaml:"include_internal,omitempty"`
+	Categories       []string          `yaml:"categories,omitempty"`
+	StrictCategories *bool             `yaml:"strict_categories,omitempty"`
+	Template         string            `yaml:"template,omitempty"`
+	VersionScheme    string            `yaml:"version_scheme,omitempty"`
+	BumpRules        map[string]string `yaml:"bump_rules,omitempty"`
+	TagPrefix        *string           `yaml:"tag_prefix,omitempty"`
+	CommitMessage    string            `yaml:"commit_message,omitempty"`
+	// Forge selects the URL shape of compare and reference links; empty
+	// means detect from RepoURL.
+	Forge string `yaml:"forge,omitempty"`
+	// CompareURLTemplate is a text/template for compare links, receiving
+	// CompareURLData. It is required by forge "custom" and overrides others.
+	CompareURLTemplate string `yaml:"compare_url_template,omitempty"`
+	// Fragments overlays the files in FragmentDir onto the unreleased version
+	// on Load and makes `chlog add` write fragments by default.
+	Fragments   bool   `yaml:"fragments,omitempty"`
+	FragmentDir string `yaml:"fragment_dir,omitempty"`
+	// Require configures `chlog check --changed-since`.
+	Require RequireConfig `yaml:"require,omitempty"`
+	// Lint configures the rules of `chlog lint`.
+	Lint LintConfig `yaml:"lint,omitempty"`
+	// Packaging configures the Debian and RPM changelogs.
+	Packaging PackagingConfig `yaml:"packaging,omitempty"`
+	// Store configures the app store release notes.
+	Store StoreConfig `yaml:"store,omitempty"`
+}
+
+// TagName returns the git tag for a version: TagPrefix (DefaultTagPrefix if
+// unset, so an explicit "" gives bare tags) followed by the version without
+// its own "v" prefix.
+func (c *Config) TagName(version string) string {
+	prefix := DefaultTagPrefix
+	if c.TagPrefix != nil {
+		prefix = *c.TagPrefix
+	}
+	return prefix + strings.TrimPrefix(version, "v")
+}
+
+// TagPattern returns a git glob matching the project's release tags, e.g.
+// "api/v*", or "" when tag_prefix is not set explicitly (any tag matches).
+func (c *Config) TagPattern() string {
+	if c.TagPrefix == nil || *c.TagPrefix == "" {
+		return ""
+	}
+	return *c.TagPrefix + "*"
+}
+
+// ReleaseCommitMessage renders CommitMessage (or DefaultCommitMessage) for a
+// release. The template receives .Version and .Tag.
+func (c *Config) ReleaseCommitMessage(version string) (string, error) {
+	text := c.CommitMessage
+	if text == "" {
+		text = DefaultCommitMessage
+	}
+	tmpl, err := template.New("commit_message").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parsing commit_message: %w", err)
+	}
+	var b strings.Builder
+	data := struct{ Version, Tag string }{version, c.TagName(version)}
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("rendering commit_message: %w", err)
+	}
+	return b.String(), nil
+}
+
+// Scheme returns the configured version scheme, SchemeNone if unset.
+func (c *Config) Scheme() (VersionScheme, error) {
+	return ParseVersionScheme(c.VersionScheme)
+}
+
+// BumpLevels parses BumpRules, which override DefaultBumpRules per category.
+func (c *Config) BumpLevels() (map[string]BumpLevel, error) {
+	levels := make(map[string]BumpLevel, len(c.BumpRules))
+	for cat, name := range c.BumpRules {
+		l, err := ParseBumpLevel(name)
+		if err != nil {
+			return nil, fmt.Errorf("bump_rules.%s: %w", cat, err)
+		}
+		levels[cat] = l
+	}
+	return levels, nil
+}
+
+// Validate checks the fields that LoadConfig cannot type-check: the version
+// scheme, bump rules, forge, compare URL template, lint, packaging and
+// store settings.
+func (c *Config) Validate() error {
+	if _, err := c.Scheme(); err != nil {
+		return err
+	}
+	if _, err := c.BumpLevels(); err != nil {
+		return err
+	}
+	forge, err := ParseForge(c.Forge)
+	if err != nil {
+		return err
+	}
+	if forge == ForgeCustom && c.CompareURLTemplate == "" {
+		return fmt.Errorf("forge %q requires compare_url_template", ForgeCustom)
+	}
+	if err := c.checkCompareTemplate(); err != nil {
+		return err
+	}
+	if err := c.Lint.Validate(); err != nil {
+		return err
+	}
+	if err := c.Packaging.Validate(); err != nil {
+		return err
+	}
+	return c.Store.Validate()
 }
 
 // AllowedCategories returns the category allowlist for validation.
@@ -53,6 +167,14 @@ func (c *Config) InternalFilePath() string {
 	return DefaultInternalFile
 }
 
+// FragmentDirPath returns FragmentDir if set, otherwise the default.
+func (c *Config) FragmentDirPath() string {
+	if c.FragmentDir != "" {
+		return c.FragmentDir
+	}
+	return DefaultFragmentDir
+}
+
 // LoadConfig reads a config file from the given path.
 // Returns an empty config (not an error) if the file doesn't exist.
 func LoadConfig(path string) (*Config, error) {
@@ -68,6 +190,9 @@ func LoadConfig(path string) (*Config, error) {
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("parsing config %s: %w", path, err)
 	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("parsing config %s: %w", path, err)
+	}
 	return &cfg, nil
 }
 
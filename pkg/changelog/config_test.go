@@ -3,6 +3,7 @@ package changelog
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -240,3 +241,95 @@ func TestSaveConfig_BadPath(t *testing.T) {
 		t.Fatal("expected error for bad path, got nil")
 	}
 }
+
+func TestLoadConfig_VersionScheme(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".chlog.yaml")
+	if err := os.WriteFile(path, []byte("version_scheme: calver\n"), 0644); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if scheme, _ := cfg.Scheme(); scheme != SchemeCalver {
+		t.Errorf("Scheme() = %q, want calver", scheme)
+	}
+
+	if err := os.WriteFile(path, []byte("version_scheme: romver\n"), 0644); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+	if _, err := LoadConfig(path); err == nil {
+		t.Error("expected error for unknown version scheme")
+	}
+}
+
+func TestLoadConfig_Invalid(t *testing.T) {
+	tests := map[string]struct {
+		yaml string
+		want string
+	}{
+		"version scheme":   {yaml: "version_scheme: sem\ncategories: [added, custom]\n", want: "sem"},
+		"packaging":        {yaml: "packaging:\n  maintainer: Jane <jane@example.com>\n  urgency: hi\n", want: "packaging.urgency"},
+		"store locale":     {yaml: "store:\n  locales: [English]\n", want: "store.locales"},
+		"custom forge URL": {yaml: "forge: custom\n", want: "compare_url_template"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".chlog.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0644); err != nil {
+				t.Fatalf("writing config: %v", err)
+			}
+			_, err := LoadConfig(path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadConfig() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_TagName(t *testing.T) {
+	empty, mono := "", "api/v"
+	tests := map[string]struct {
+		prefix  *string
+		version string
+		want    string
+	}{
+		"default":         {nil, "1.2.0", "v1.2.0"},
+		"version with v":  {nil, "v1.2.0", "v1.2.0"},
+		"bare":            {&empty, "1.2.0", "1.2.0"},
+		"monorepo prefix": {&mono, "1.2.0", "api/v1.2.0"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &Config{TagPrefix: tt.prefix}
+			if got := cfg.TagName(tt.version); got != tt.want {
+				t.Errorf("TagName(%q) = %q, want %q", tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_ReleaseCommitMessage(t *testing.T) {
+	tests := map[string]struct {
+		template string
+		want     string
+		wantErr  bool
+	}{
+		"default":   {"", "release: v1.2.0", false},
+		"custom":    {"chore: release {{.Version}}", "chore: release 1.2.0", false},
+		"bad field": {"{{.Nope}}", "", true},
+		"bad parse": {"{{", "", true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := (&Config{CommitMessage: tt.template}).ReleaseCommitMessage("1.2.0")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ReleaseCommitMessage = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
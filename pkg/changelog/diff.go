@@ -0,0 +1,191 @@
+package changelog
+
+import (
+	"fmt"
+	"slices"
+)
+
+// DiffStatus says how a version differs between two changelogs.
+type DiffStatus string
+
+const (
+	DiffAdded    DiffStatus = "added"
+	DiffRemoved  DiffStatus = "removed"
+	DiffModified DiffStatus = "modified"
+)
+
+// ChangelogDiff is the structured difference between two changelogs.
+type ChangelogDiff struct {
+	Versions []VersionDiff `json:"versions"`
+}
+
+// IsEmpty returns true if the changelogs have the same versions and entries.
+func (d *ChangelogDiff) IsEmpty() bool {
+	return len(d.Versions) == 0
+}
+
+// Public returns a copy without internal categories, dropping modified
+// versions left with no changes.
+func (d *ChangelogDiff) Public() *ChangelogDiff {
+	out := &ChangelogDiff{}
+	for _, vd := range d.Versions {
+		vd.Categories = slices.DeleteFunc(slices.Clone(vd.Categories), func(cd CategoryDiff) bool { return cd.Internal })
+		if vd.Status != DiffModified || vd.OldDate != vd.NewDate || len(vd.Categories) > 0 {
+			out.Versions = append(out.Versions, vd)
+		}
+	}
+	return out
+}
+
+// VersionDiff lists the changes to one version. OldDate and NewDate are set
+// when the date differs.
+type VersionDiff struct {
+	Version    string         `json:"version"`
+	Status     DiffStatus     `json:"status"`
+	OldDate    string         `json:"old_date,omitempty"`
+	NewDate    string         `json:"new_date,omitempty"`
+	Categories []CategoryDiff `json:"categories,omitempty"`
+}
+
+// CategoryDiff lists the entries added, removed and modified in a category.
+type CategoryDiff struct {
+	Category string        `json:"category"`
+	Internal bool          `json:"internal,omitempty"`
+	Added    []Entry       `json:"added,omitempty"`
+	Removed  []Entry       `json:"removed,omitempty"`
+	Modified []EntryChange `json:"modified,omitempty"`
+}
+
+func (cd CategoryDiff) label() string {
+	if cd.Internal {
+		return "internal." + cd.Category
+	}
+	return cd.Category
+}
+
+// EntryChange is an entry whose text was reworded or whose metadata changed.
+type EntryChange struct {
+	Old Entry `json:"old"`
+	New Entry `json:"new"`
+}
+
+// Diff compares two changelogs version by version. Versions are listed in
+// updated's order, followed by versions only in old. Within a category, a
+// removed and an added entry that are similar count as a modification, as
+// does an entry kept with different metadata.
+func Diff(old, updated *Changelog) *ChangelogDiff {
+	d := &ChangelogDiff{}
+	for i := range updated.Versions {
+		nv := &updated.Versions[i]
+		ov := findVersion(old, nv.Version)
+		if ov == nil {
+			vd := DiffVersions(&Version{}, nv)
+			vd.Status, vd.OldDate, vd.NewDate = DiffAdded, "", ""
+			d.Versions = append(d.Versions, vd)
+			continue
+		}
+		if vd := DiffVersions(ov, nv); vd.OldDate != vd.NewDate || len(vd.Categories) > 0 {
+			d.Versions = append(d.Versions, vd)
+		}
+	}
+	for i := range old.Versions {
+		ov := &old.Versions[i]
+		if findVersion(updated, ov.Version) == nil {
+			vd := DiffVersions(ov, &Version{Version: ov.Version})
+			vd.Status, vd.OldDate, vd.NewDate = DiffRemoved, "", ""
+			d.Versions = append(d.Versions, vd)
+		}
+	}
+	return d
+}
+
+// DiffVersions compares the entries and dates of two versions, such as
+// 1.3.0 and 1.4.0, and labels the result with updated's version.
+func DiffVersions(old, updated *Version) VersionDiff {
+	vd := VersionDiff{Version: updated.Version, Status: DiffModified}
+	if old.Date != updated.Date {
+		vd.OldDate, vd.NewDate = old.Date, updated.Date
+	}
+	vd.Categories = append(vd.Categories, diffChanges(updated.Version, old.Public, updated.Public, false)...)
+	vd.Categories = append(vd.Categories, diffChanges(updated.Version, old.Internal, updated.Internal, true)...)
+	return vd
+}
+
+func diffChanges(version string, old, updated Changes, internal bool) []CategoryDiff {
+	names := old.CategoryNames()
+	for _, name := range updated.CategoryNames() {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	var diffs []CategoryDiff
+	for _, name := range names {
+		oc, _ := old.Category(name)
+		nc, _ := updated.Category(name)
+		cd := diffCategory(version, oc, nc)
+		cd.Category, cd.Internal = name, internal
+		if len(cd.Added)+len(cd.Removed)+len(cd.Modified) > 0 {
+			diffs = append(diffs, cd)
+		}
+	}
+	return diffs
+}
+
+// rewordSimilarity is the similarity from which a removed and an added entry
+// are paired as a rewording rather than reported separately.
+const rewordSimilarity = 0.5
+
+// diffCategory matches entries by text, reporting metadata changes of kept
+// entries, then pairs each leftover addition with the most similar leftover
+// removal as a rewording. Unrelated entries stay added and removed.
+func diffCategory(version string, old, updated CategoryEntry) CategoryDiff {
+	entry := func(cat CategoryEntry, i int) Entry {
+		return Entry{Text: cat.Entries[i], Category: cat.Name, Version: version, EntryMeta: cat.MetaAt(i)}
+	}
+	var cd CategoryDiff
+	var added []int
+	remaining := make([]int, len(old.Entries))
+	for i := range remaining {
+		remaining[i] = i
+	}
+	for j, text := range updated.Entries {
+		k := slices.IndexFunc(remaining, func(i int) bool { return old.Entries[i] == text })
+		if k < 0 {
+			added = append(added, j)
+			continue
+		}
+		if i := remaining[k]; !old.MetaAt(i).Equal(updated.MetaAt(j)) {
+			cd.Modified = append(cd.Modified, EntryChange{Old: entry(old, i), New: entry(updated, j)})
+		}
+		remaining = slices.Delete(remaining, k, k+1)
+	}
+	for _, j := range added {
+		best, bestSim := -1, rewordSimilarity
+		for k, i := range remaining {
+			if sim := similarity(normalizeEntry(old.Entries[i]), normalizeEntry(updated.Entries[j])); sim >= bestSim {
+				best, bestSim = k, sim
+			}
+		}
+		if best < 0 {
+			cd.Added = append(cd.Added, entry(updated, j))
+			continue
+		}
+		cd.Modified = append(cd.Modified, EntryChange{Old: entry(old, remaining[best]), New: entry(updated, j)})
+		remaining = slices.Delete(remaining, best, best+1)
+	}
+	for _, i := range remaining {
+		cd.Removed = append(cd.Removed, entry(old, i))
+	}
+	return cd
+}
+
+// describeChange renders "old → new", with "(none)" for an empty side.
+func describeChange(old, updated string) string {
+	if old == "" {
+		old = "(none)"
+	}
+	if updated == "" {
+		updated = "(none)"
+	}
+	return fmt.Sprintf("%s → %s", old, updated)
+}
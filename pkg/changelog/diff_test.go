@@ -0,0 +1,127 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+)
+
+func loadDiffSides(t *testing.T, edit func(*Changelog)) (*Changelog, *Changelog) {
+	t.Helper()
+	old, err := LoadFromReader(strings.NewReader(releasedBase))
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated, _ := LoadFromReader(strings.NewReader(releasedBase))
+	edit(updated)
+	return old, updated
+}
+
+func TestDiff(t *testing.T) {
+	old, updated := loadDiffSides(t, func(c *Changelog) {
+		c.GetUnreleased().Public.Append("added", "Dark mode")
+		_ = c.Release("0.3.0", "2024-03-01")
+		c.Versions[2].Public.Categories[0].Entries[0] = "Fix crash on start"
+		c.Versions[2].Public.Categories[0].Meta = []EntryMeta{{}, {PR: 7}}
+		c.Versions = c.Versions[:3]
+	})
+
+	d := Diff(old, updated)
+	var got []string
+	for _, vd := range d.Versions {
+		got = append(got, vd.Version+":"+string(vd.Status))
+	}
+	if strings.Join(got, ",") != "unreleased:modified,0.3.0:added,0.2.0:modified,0.1.0:removed" {
+		t.Fatalf("versions = %v", got)
+	}
+	if removed := d.Versions[0].Categories[0].Removed; len(removed) != 1 || removed[0].Text != "Pending" {
+		t.Errorf("unreleased removed = %+v", removed)
+	}
+	fixed := d.Versions[2].Categories[0]
+	if len(fixed.Modified) != 2 || fixed.Modified[0].New.PR != 7 || fixed.Modified[1].Old.Text != "Fix crash" {
+		t.Errorf("fixed diff = %+v", fixed)
+	}
+	if added := d.Versions[1].Categories; len(added) != 1 || added[0].Added[0].Text != "Pending" {
+		t.Errorf("0.3.0 categories = %+v", added)
+	}
+}
+
+func TestDiffCategory_Pairing(t *testing.T) {
+	tests := map[string]struct {
+		old, updated             []string
+		added, removed, modified int
+	}{
+		"reworded":          {old: []string{"Fix crash on start"}, updated: []string{"Fix crash on startup"}, modified: 1},
+		"unrelated":         {old: []string{"Fix login timeout"}, updated: []string{"Add dark mode"}, added: 1, removed: 1},
+		"most similar wins": {old: []string{"Support SSO", "Add CSV export"}, updated: []string{"Add CSV exports"}, removed: 1, modified: 1},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cd := diffCategory("1.0.0", CategoryEntry{Name: "added", Entries: tt.old}, CategoryEntry{Name: "added", Entries: tt.updated})
+			if len(cd.Added) != tt.added || len(cd.Removed) != tt.removed || len(cd.Modified) != tt.modified {
+				t.Errorf("diff = %+v", cd)
+			}
+		})
+	}
+}
+
+func TestDiff_Identical(t *testing.T) {
+	old, updated := loadDiffSides(t, func(*Changelog) {})
+	if d := Diff(old, updated); !d.IsEmpty() {
+		t.Errorf("expected empty diff, got %+v", d)
+	}
+}
+
+func TestDiffVersions(t *testing.T) {
+	c, _ := loadDiffSides(t, func(*Changelog) {})
+	older, _ := c.GetVersion("0.1.0")
+	newer, _ := c.GetVersion("0.2.0")
+
+	vd := DiffVersions(older, newer)
+	if vd.Version != "0.2.0" || vd.OldDate != "2024-01-01" || vd.NewDate != "2024-02-01" {
+		t.Errorf("header = %+v", vd)
+	}
+	if len(vd.Categories) != 3 {
+		t.Errorf("categories = %+v", vd.Categories)
+	}
+}
+
+func TestChangelogDiff_Public(t *testing.T) {
+	old, updated := loadDiffSides(t, func(c *Changelog) {
+		_, _ = c.Versions[1].Internal.Remove("changed", "Refactor", false)
+	})
+	if d := Diff(old, updated).Public(); !d.IsEmpty() {
+		t.Errorf("internal-only change should drop out: %+v", d)
+	}
+}
+
+func TestFormatDiff(t *testing.T) {
+	old, updated := loadDiffSides(t, func(c *Changelog) {
+		c.GetUnreleased().Public.Append("fixed", "Typo")
+		c.Versions[1].Public.Categories[0].Entries[1] = "Fix memory leak"
+		c.Versions[2].Date = "2024-01-02"
+	})
+	d := Diff(old, updated)
+
+	wantText := `unreleased (modified)
+  fixed
+    + Typo
+0.2.0 (modified)
+  fixed
+    ~ Fix leak → Fix memory leak
+0.1.0 (modified)
+  ~ date: 2024-01-01 → 2024-01-02
+`
+	if got := FormatDiff(d, FormatOptions{Plain: true}); got != wantText {
+		t.Errorf("FormatDiff =\n%s\nwant\n%s", got, wantText)
+	}
+
+	wantMD := "### unreleased (modified)\n\n- **fixed** added: Typo\n\n" +
+		"### 0.2.0 (modified)\n\n- **fixed** changed: ~~Fix leak~~ → Fix memory leak\n\n" +
+		"### 0.1.0 (modified)\n\n- **date** changed: 2024-01-01 → 2024-01-02\n"
+	if got := RenderDiffMarkdown(d); got != wantMD {
+		t.Errorf("RenderDiffMarkdown =\n%s\nwant\n%s", got, wantMD)
+	}
+	if got := FormatDiff(&ChangelogDiff{}, FormatOptions{Plain: true}); got != "No changelog changes\n" {
+		t.Errorf("empty = %q", got)
+	}
+}
@@ -31,6 +31,9 @@
 //	}
 //	changelog.Save(c, "CHANGELOG.yaml")
 //
+// Save writes back only what changed when the changelog came from Load or
+// LoadFromReader, keeping comments, blank lines and quoting intact.
+//
 // # Parsing from any reader
 //
 //	f, _ := os.Open("CHANGELOG.yaml")
@@ -0,0 +1,303 @@
+package changelog
+
+import (
+	"cmp"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// FeedFormat is a release feed format.
+type FeedFormat string
+
+const (
+	FeedAtom FeedFormat = "atom"
+	FeedRSS  FeedFormat = "rss"
+	FeedJSON FeedFormat = "jsonfeed"
+)
+
+// ParseFeedFormat parses the --format value of `chlog feed`.
+func ParseFeedFormat(s string) (FeedFormat, error) {
+	switch f := FeedFormat(strings.ToLower(s)); f {
+	case FeedAtom, FeedRSS, FeedJSON:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown feed format %q (valid: atom, rss, jsonfeed)", s)
+}
+
+// FeedOptions controls feed rendering.
+type FeedOptions struct {
+	Format FeedFormat
+	// BaseURL is the absolute URL of the published changelog, e.g. the page
+	// written by `chlog sync --format html`. Items link to its version
+	// anchors, and those links are the items' stable IDs.
+	BaseURL string
+	// Title defaults to "<project> releases".
+	Title string
+	// Author names the publisher of the feed; defaults to the project, or
+	// Title when there is none.
+	Author string
+	// MaxItems limits the feed to the newest releases; 0 means no limit.
+	MaxItems int
+	// Config links PR and issue references in the content.
+	Config *Config
+}
+
+// FeedItem is a released version as a feed entry.
+type FeedItem struct {
+	ID      string
+	Title   string
+	URL     string
+	Date    time.Time
+	Content string // HTML of the public entries
+}
+
+// Feed is the format-independent model of a release feed.
+type Feed struct {
+	Title   string
+	URL     string
+	Author  string
+	Updated time.Time
+	Items   []FeedItem
+}
+
+// NewFeed builds the feed of c: one item per released version in file
+// order, skipping unreleased, with the public entries as HTML content.
+// Updated is the newest release date, or the current time without releases.
+func NewFeed(c *Changelog, opts FeedOptions) (Feed, error) {
+	base, err := url.Parse(opts.BaseURL)
+	if err != nil || !base.IsAbs() || base.Host == "" {
+		return Feed{}, fmt.Errorf("base URL must be an absolute URL, got %q", opts.BaseURL)
+	}
+	base.Fragment = ""
+	feed := Feed{Title: opts.Title, URL: base.String()}
+	if feed.Title == "" {
+		feed.Title = strings.TrimSpace(c.Project + " releases")
+	}
+	feed.Author = cmp.Or(opts.Author, c.Project, feed.Title)
+
+	links := newRepoLinks(opts.Config, ResolveRepoURL(opts.Config))
+	for i := range c.Versions {
+		v := &c.Versions[i]
+		if v.IsUnreleased() {
+			continue
+		}
+		if opts.MaxItems > 0 && len(feed.Items) == opts.MaxItems {
+			break
+		}
+		item, err := newFeedItem(newTemplateVersion(v, false, links), feed.URL)
+		if err != nil {
+			return Feed{}, err
+		}
+		if item.Date.After(feed.Updated) {
+			feed.Updated = item.Date
+		}
+		feed.Items = append(feed.Items, item)
+	}
+	if feed.Updated.IsZero() {
+		feed.Updated = time.Now().UTC().Truncate(time.Second)
+	}
+	return feed, nil
+}
+
+func newFeedItem(v TemplateVersion, baseURL string) (FeedItem, error) {
+	date, err := time.Parse(time.DateOnly, v.Date)
+	if err != nil {
+		return FeedItem{}, fmt.Errorf("version %s: date %q is not YYYY-MM-DD", v.Version, v.Date)
+	}
+	link := baseURL + "#" + versionAnchor(v)
+	return FeedItem{ID: link, Title: v.Version, URL: link, Date: date, Content: feedContentHTML(v)}, nil
+}
+
+// feedContentHTML renders the categories of a version as HTML.
+func feedContentHTML(v TemplateVersion) string {
+	var b strings.Builder
+	for _, cat := range v.Categories {
+		fmt.Fprintf(&b, "<h3>%s</h3>\n<ul>\n", html.EscapeString(cat.Title))
+		for _, e := range cat.Entries {
+			fmt.Fprintf(&b, "<li>%s</li>\n", inlineMarkdownHTML(e.Markdown))
+		}
+		b.WriteString("</ul>\n")
+	}
+	return b.String()
+}
+
+// RenderFeed writes the release feed of c in opts.Format.
+func RenderFeed(c *Changelog, w io.Writer, opts FeedOptions) error {
+	feed, err := NewFeed(c, opts)
+	if err != nil {
+		return err
+	}
+	switch opts.Format {
+	case FeedAtom:
+		err = writeXML(w, atomFeed(feed))
+	case FeedRSS:
+		err = writeXML(w, rssFeed(feed))
+	case FeedJSON:
+		err = writeJSONFeed(w, feed)
+	default:
+		return fmt.Errorf("unknown feed format %q", opts.Format)
+	}
+	if err != nil {
+		return fmt.Errorf("writing %s feed: %w", opts.Format, err)
+	}
+	return nil
+}
+
+// RenderFeedString renders the release feed of c to a string.
+func RenderFeedString(c *Changelog, opts FeedOptions) (string, error) {
+	var b strings.Builder
+	if err := RenderFeed(c, &b, opts); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr,omitempty"`
+	Body string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	Title   string   `xml:"title"`
+	ID      string   `xml:"id"`
+	Link    atomLink `xml:"link"`
+	Updated string   `xml:"updated"`
+	Content atomText `xml:"content"`
+}
+
+type atomDoc struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Links   []atomLink  `xml:"link"`
+	Author  string      `xml:"author>name"`
+	Updated string      `xml:"updated"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+func atomFeed(f Feed) atomDoc {
+	doc := atomDoc{
+		Title:   f.Title,
+		ID:      f.URL,
+		Links:   []atomLink{{Href: f.URL, Rel: "alternate"}},
+		Author:  f.Author,
+		Updated: f.Updated.Format(time.RFC3339),
+	}
+	for _, item := range f.Items {
+		doc.Entries = append(doc.Entries, atomEntry{
+			Title:   item.Title,
+			ID:      item.ID,
+			Link:    atomLink{Href: item.URL},
+			Updated: item.Date.Format(time.RFC3339),
+			Content: atomText{Type: "html", Body: item.Content},
+		})
+	}
+	return doc
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"`
+}
+
+type rssDoc struct {
+	XMLName       xml.Name  `xml:"rss"`
+	Version       string    `xml:"version,attr"`
+	Title         string    `xml:"channel>title"`
+	Link          string    `xml:"channel>link"`
+	Description   string    `xml:"channel>description"`
+	LastBuildDate string    `xml:"channel>lastBuildDate"`
+	Items         []rssItem `xml:"channel>item"`
+}
+
+func rssFeed(f Feed) rssDoc {
+	doc := rssDoc{Version: "2.0", Title: f.Title, Link: f.URL, Description: f.Title, LastBuildDate: f.Updated.Format(time.RFC1123Z)}
+	for _, item := range f.Items {
+		doc.Items = append(doc.Items, rssItem{
+			Title:       item.Title,
+			Link:        item.URL,
+			GUID:        rssGUID{IsPermaLink: true, Value: item.ID},
+			PubDate:     item.Date.Format(time.RFC1123Z),
+			Description: item.Content,
+		})
+	}
+	return doc
+}
+
+// jsonFeedVersion identifies JSON Feed 1.1.
+const jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+
+type jsonFeedItem struct {
+	ID            string `json:"id"`
+	URL           string `json:"url"`
+	Title         string `json:"title"`
+	ContentHTML   string `json:"content_html"`
+	DatePublished string `json:"date_published"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedDoc struct {
+	Version     string           `json:"version"`
+	Title       string           `json:"title"`
+	HomePageURL string           `json:"home_page_url"`
+	Authors     []jsonFeedAuthor `json:"authors"`
+	Items       []jsonFeedItem   `json:"items"`
+}
+
+func writeJSONFeed(w io.Writer, f Feed) error {
+	doc := jsonFeedDoc{
+		Version:     jsonFeedVersion,
+		Title:       f.Title,
+		HomePageURL: f.URL,
+		Authors:     []jsonFeedAuthor{{Name: f.Author}},
+		Items:       []jsonFeedItem{},
+	}
+	for _, item := range f.Items {
+		doc.Items = append(doc.Items, jsonFeedItem{
+			ID:            item.ID,
+			URL:           item.URL,
+			Title:         item.Title,
+			ContentHTML:   item.Content,
+			DatePublished: item.Date.Format(time.RFC3339),
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(doc)
+}
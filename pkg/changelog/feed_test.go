@@ -0,0 +1,185 @@
+package changelog
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func feedChangelog() *Changelog {
+	c := &Changelog{Project: "demo", Versions: []Version{
+		{Version: "unreleased"},
+		{Version: "1.1.0", Date: "2024-02-01"},
+		{Version: "1.0.0", Date: "2024-01-01"},
+	}}
+	c.Versions[0].Public.Append("added", "Not yet")
+	c.Versions[1].Public.AppendWithMeta("fixed", "Crash on `<empty>` input", EntryMeta{PR: 9})
+	c.Versions[1].Internal.Append("changed", "Secret refactor")
+	c.Versions[2].Public.Append("added", "First release")
+	return c
+}
+
+func TestNewFeed(t *testing.T) {
+	cfg := &Config{RepoURL: "https://github.com/acme/demo"}
+	tests := map[string]struct {
+		opts    FeedOptions
+		titles  []string
+		title   string
+		wantErr string
+	}{
+		"all releases": {
+			opts:   FeedOptions{BaseURL: "https://acme.dev/changelog.html#top", Config: cfg},
+			titles: []string{"1.1.0", "1.0.0"},
+			title:  "demo releases",
+		},
+		"max items": {
+			opts:   FeedOptions{BaseURL: "https://acme.dev/changelog", MaxItems: 1, Title: "Demo", Config: cfg},
+			titles: []string{"1.1.0"},
+			title:  "Demo",
+		},
+		"relative base": {opts: FeedOptions{BaseURL: "/changelog"}, wantErr: "absolute URL"},
+		"empty base":    {opts: FeedOptions{}, wantErr: "absolute URL"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			feed, err := NewFeed(feedChangelog(), tt.opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if feed.Title != tt.title {
+				t.Errorf("title = %q, want %q", feed.Title, tt.title)
+			}
+			var titles []string
+			for _, item := range feed.Items {
+				titles = append(titles, item.Title)
+			}
+			if strings.Join(titles, ",") != strings.Join(tt.titles, ",") {
+				t.Errorf("items = %v, want %v", titles, tt.titles)
+			}
+			if got := feed.Updated.Format("2006-01-02"); got != "2024-02-01" {
+				t.Errorf("updated = %s", got)
+			}
+		})
+	}
+}
+
+func TestNewFeed_Item(t *testing.T) {
+	feed, err := NewFeed(feedChangelog(), FeedOptions{BaseURL: "https://acme.dev/changelog.html", Config: &Config{RepoURL: "https://github.com/acme/demo"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := feed.Items[0]
+	if item.ID != "https://acme.dev/changelog.html#v1.1.0" || item.URL != item.ID {
+		t.Errorf("id = %q, url = %q", item.ID, item.URL)
+	}
+	want := "<h3>Fixed</h3>\n<ul>\n<li>Crash on <code>&lt;empty&gt;</code> input (<a href=\"https://github.com/acme/demo/pull/9\">#9</a>)</li>\n</ul>\n"
+	if item.Content != want {
+		t.Errorf("content = %q, want %q", item.Content, want)
+	}
+}
+
+func TestNewFeed_BadDate(t *testing.T) {
+	c := &Changelog{Versions: []Version{{Version: "1.0.0", Date: "soon"}}}
+	if _, err := NewFeed(c, FeedOptions{BaseURL: "https://acme.dev/"}); err == nil || !strings.Contains(err.Error(), "1.0.0") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestNewFeed_NoReleases(t *testing.T) {
+	c := &Changelog{Versions: []Version{{Version: "unreleased"}}}
+	before := time.Now().Add(-time.Second)
+	feed, err := NewFeed(c, FeedOptions{BaseURL: "https://acme.dev/", Author: "Acme"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if feed.Updated.Before(before) {
+		t.Errorf("updated = %s, want the current time", feed.Updated)
+	}
+	if feed.Author != "Acme" {
+		t.Errorf("author = %q", feed.Author)
+	}
+}
+
+func TestRenderFeed(t *testing.T) {
+	tests := map[string]struct {
+		format FeedFormat
+		check  func(t *testing.T, out string)
+	}{
+		"atom": {FeedAtom, func(t *testing.T, out string) {
+			var doc struct {
+				XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+				Author  string   `xml:"author>name"`
+				Updated string   `xml:"updated"`
+				Entries []struct {
+					ID      string `xml:"id"`
+					Updated string `xml:"updated"`
+					Content string `xml:"content"`
+				} `xml:"entry"`
+			}
+			if err := xml.Unmarshal([]byte(out), &doc); err != nil {
+				t.Fatal(err)
+			}
+			if doc.Author != "demo" || doc.Updated != "2024-02-01T00:00:00Z" || len(doc.Entries) != 2 {
+				t.Fatalf("doc = %+v", doc)
+			}
+			if doc.Entries[1].ID != "https://acme.dev/changelog#v1.0.0" || !strings.Contains(doc.Entries[1].Content, "<li>First release</li>") {
+				t.Errorf("entry = %+v", doc.Entries[1])
+			}
+		}},
+		"rss": {FeedRSS, func(t *testing.T, out string) {
+			var doc struct {
+				Items []struct {
+					GUID    string `xml:"guid"`
+					PubDate string `xml:"pubDate"`
+				} `xml:"channel>item"`
+			}
+			if err := xml.Unmarshal([]byte(out), &doc); err != nil {
+				t.Fatal(err)
+			}
+			if len(doc.Items) != 2 || doc.Items[0].GUID != "https://acme.dev/changelog#v1.1.0" || doc.Items[0].PubDate != "Thu, 01 Feb 2024 00:00:00 +0000" {
+				t.Errorf("items = %+v", doc.Items)
+			}
+		}},
+		"jsonfeed": {FeedJSON, func(t *testing.T, out string) {
+			var doc jsonFeedDoc
+			if err := json.Unmarshal([]byte(out), &doc); err != nil {
+				t.Fatal(err)
+			}
+			if doc.Version != jsonFeedVersion || len(doc.Authors) != 1 || doc.Authors[0].Name != "demo" ||
+				len(doc.Items) != 2 || doc.Items[0].DatePublished != "2024-02-01T00:00:00Z" {
+				t.Errorf("doc = %+v", doc)
+			}
+		}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := RenderFeedString(feedChangelog(), FeedOptions{Format: tt.format, BaseURL: "https://acme.dev/changelog", Config: &Config{RepoURL: "https://github.com/acme/demo"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(out, "Not yet") || strings.Contains(out, "Secret") {
+				t.Errorf("unreleased or internal entries in feed:\n%s", out)
+			}
+			tt.check(t, out)
+		})
+	}
+}
+
+func TestParseFeedFormat(t *testing.T) {
+	for _, s := range []string{"atom", "RSS", "jsonfeed"} {
+		if _, err := ParseFeedFormat(s); err != nil {
+			t.Errorf("ParseFeedFormat(%q): %v", s, err)
+		}
+	}
+	if _, err := ParseFeedFormat("xml"); err == nil {
+		t.Error("expected error for xml")
+	}
+}
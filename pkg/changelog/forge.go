@@ -0,0 +1,158 @@
+package changelog
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// Forge identifies the code hosting service a repository lives on, which
+// decides the shape of compare, pull request and issue URLs.
+type Forge string
+
+const (
+	ForgeGitHub    Forge = "github"
+	ForgeGitLab    Forge = "gitlab"
+	ForgeBitbucket Forge = "bitbucket"
+	ForgeGitea     Forge = "gitea"
+	ForgeAzure     Forge = "azure"
+	// ForgeCustom builds compare links from Config.CompareURLTemplate.
+	ForgeCustom Forge = "custom"
+)
+
+// ParseForge converts a config value to a Forge. An empty string means the
+// forge is detected from the repository URL.
+func ParseForge(s string) (Forge, error) {
+	switch f := Forge(s); f {
+	case "", ForgeGitHub, ForgeGitLab, ForgeBitbucket, ForgeGitea, ForgeAzure, ForgeCustom:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown forge %q (valid: github, gitlab, bitbucket, gitea, azure, custom)", s)
+}
+
+// DetectForge guesses the forge from well-known host names, defaulting to GitHub.
+func DetectForge(repoURL string) Forge {
+	u := strings.ToLower(repoURL)
+	switch {
+	case strings.Contains(u, "gitlab"):
+		return ForgeGitLab
+	case strings.Contains(u, "bitbucket"):
+		return ForgeBitbucket
+	case strings.Contains(u, "dev.azure.com"), strings.Contains(u, "visualstudio.com"):
+		return ForgeAzure
+	case strings.Contains(u, "gitea"), strings.Contains(u, "forgejo"), strings.Contains(u, "codeberg.org"):
+		return ForgeGitea
+	}
+	return ForgeGitHub
+}
+
+// CompareURLData is passed to Config.CompareURLTemplate.
+type CompareURLData struct {
+	RepoURL string
+	// From and To are tag names; To is "HEAD" for unreleased changes.
+	From, To string
+}
+
+// repoLinks builds forge-specific URLs for a repository.
+type repoLinks struct {
+	repoURL string
+	forge   Forge
+	cfg     *Config
+}
+
+// newRepoLinks resolves the forge from cfg, falling back to detection.
+// A nil cfg uses "v" tags and detection.
+func newRepoLinks(cfg *Config, repoURL string) repoLinks {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	forge, err := ParseForge(cfg.Forge)
+	if err != nil || forge == "" {
+		forge = DetectForge(repoURL)
+	}
+	if cfg.CompareURLTemplate != "" {
+		forge = ForgeCustom
+	}
+	return repoLinks{repoURL: repoURL, forge: forge, cfg: cfg}
+}
+
+// compare returns the URL comparing two versions; an empty to compares from
+// against HEAD. It returns "" when the forge cannot express the comparison.
+func (l repoLinks) compare(from, to string) string {
+	data := CompareURLData{RepoURL: l.repoURL, From: l.cfg.TagName(from), To: "HEAD"}
+	if to != "" {
+		data.To = l.cfg.TagName(to)
+	}
+	switch l.forge {
+	case ForgeCustom:
+		return executeCompareTemplate(l.cfg.CompareURLTemplate, data)
+	case ForgeGitLab:
+		return fmt.Sprintf("%s/-/compare/%s...%s", l.repoURL, data.From, data.To)
+	case ForgeBitbucket:
+		return fmt.Sprintf("%s/branches/compare/%s%%0D%s", l.repoURL, data.To, data.From)
+	case ForgeAzure:
+		if to == "" {
+			return "" // Azure DevOps compare URLs cannot target HEAD
+		}
+		return fmt.Sprintf("%s/branchCompare?baseVersion=GT%s&targetVersion=GT%s", l.repoURL, data.From, data.To)
+	}
+	return fmt.Sprintf("%s/compare/%s...%s", l.repoURL, data.From, data.To)
+}
+
+func executeCompareTemplate(text string, data CompareURLData) string {
+	tmpl, err := parseCompareTemplate(text)
+	if err != nil {
+		return ""
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return ""
+	}
+	return b.String()
+}
+
+func parseCompareTemplate(text string) (*template.Template, error) {
+	return template.New("compare_url_template").Option("missingkey=error").Parse(text)
+}
+
+// checkCompareTemplate parses the compare URL template and renders it with
+// sample data, so a typo such as {{.Repo}} fails when the config is loaded
+// instead of silently dropping compare links.
+func (c *Config) checkCompareTemplate() error {
+	tmpl, err := parseCompareTemplate(c.CompareURLTemplate)
+	if err != nil {
+		return fmt.Errorf("parsing compare_url_template: %w", err)
+	}
+	data := CompareURLData{RepoURL: "https://example.com/repo", From: c.TagName("1.0.0"), To: "HEAD"}
+	if err := tmpl.Execute(io.Discard, data); err != nil {
+		return fmt.Errorf("rendering compare_url_template: %w", err)
+	}
+	return nil
+}
+
+// reference returns the URL of a pull request ("pull") or issue ("issues").
+func (l repoLinks) reference(kind, num string) string {
+	switch l.forge {
+	case ForgeGitLab:
+		if kind == "pull" {
+			return l.repoURL + "/-/merge_requests/" + num
+		}
+		return l.repoURL + "/-/issues/" + num
+	case ForgeBitbucket:
+		if kind == "pull" {
+			return l.repoURL + "/pull-requests/" + num
+		}
+	case ForgeGitea:
+		if kind == "pull" {
+			return l.repoURL + "/pulls/" + num
+		}
+	case ForgeAzure:
+		if kind == "pull" {
+			return l.repoURL + "/pullrequest/" + num
+		}
+		project, _, _ := strings.Cut(l.repoURL, "/_git/")
+		return project + "/_workitems/edit/" + num
+	}
+	return l.repoURL + "/" + kind + "/" + num
+}
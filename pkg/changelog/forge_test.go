@@ -0,0 +1,154 @@
+package changelog
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectForge(t *testing.T) {
+	tests := map[string]struct {
+		url  string
+		want Forge
+	}{
+		"github":         {"https://github.com/o/r", ForgeGitHub},
+		"gitlab custom":  {"https://gitlab.example.com/o/r", ForgeGitLab},
+		"bitbucket":      {"https://bitbucket.org/o/r", ForgeBitbucket},
+		"azure":          {"https://dev.azure.com/org/proj/_git/r", ForgeAzure},
+		"azure legacy":   {"https://org.visualstudio.com/proj/_git/r", ForgeAzure},
+		"codeberg":       {"https://codeberg.org/o/r", ForgeGitea},
+		"unknown domain": {"https://git.example.com/o/r", ForgeGitHub},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := DetectForge(tt.url); got != tt.want {
+				t.Errorf("DetectForge(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepoLinks_Compare(t *testing.T) {
+	bare, mono := "", "api/v"
+	tests := map[string]struct {
+		cfg      Config
+		repo     string
+		from, to string
+		want     string
+	}{
+		"github default": {Config{}, "https://github.com/o/r", "1.0.0", "1.1.0",
+			"https://github.com/o/r/compare/v1.0.0...v1.1.0"},
+		"github unreleased": {Config{}, "https://github.com/o/r", "1.1.0", "",
+			"https://github.com/o/r/compare/v1.1.0...HEAD"},
+		"monorepo prefix": {Config{TagPrefix: &mono}, "https://github.com/o/r", "1.0.0", "1.1.0",
+			"https://github.com/o/r/compare/api/v1.0.0...api/v1.1.0"},
+		"bare tags": {Config{TagPrefix: &bare}, "https://github.com/o/r", "1.0.0", "1.1.0",
+			"https://github.com/o/r/compare/1.0.0...1.1.0"},
+		"self-hosted gitlab": {Config{Forge: "gitlab"}, "https://code.example.com/o/r", "1.0.0", "1.1.0",
+			"https://code.example.com/o/r/-/compare/v1.0.0...v1.1.0"},
+		"bitbucket": {Config{}, "https://bitbucket.org/o/r", "1.0.0", "1.1.0",
+			"https://bitbucket.org/o/r/branches/compare/v1.1.0%0Dv1.0.0"},
+		"gitea": {Config{Forge: "gitea"}, "https://git.example.com/o/r", "1.0.0", "1.1.0",
+			"https://git.example.com/o/r/compare/v1.0.0...v1.1.0"},
+		"azure": {Config{}, "https://dev.azure.com/org/proj/_git/r", "1.0.0", "1.1.0",
+			"https://dev.azure.com/org/proj/_git/r/branchCompare?baseVersion=GTv1.0.0&targetVersion=GTv1.1.0"},
+		"azure unreleased": {Config{}, "https://dev.azure.com/org/proj/_git/r", "1.1.0", "", ""},
+		"custom": {Config{Forge: "custom", CompareURLTemplate: "{{.RepoURL}}/diff?a={{.From}}&b={{.To}}"},
+			"https://git.example.com/r", "1.0.0", "", "https://git.example.com/r/diff?a=v1.0.0&b=HEAD"},
+		"template overrides forge": {Config{Forge: "github", CompareURLTemplate: "{{.From}}..{{.To}}"},
+			"https://github.com/o/r", "1.0.0", "1.1.0", "v1.0.0..v1.1.0"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := newRepoLinks(&tt.cfg, tt.repo).compare(tt.from, tt.to); got != tt.want {
+				t.Errorf("compare = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepoLinks_Reference(t *testing.T) {
+	tests := map[string]struct {
+		repo      string
+		pr, issue string
+	}{
+		"github":    {"https://github.com/o/r", "https://github.com/o/r/pull/5", "https://github.com/o/r/issues/7"},
+		"gitlab":    {"https://gitlab.com/o/r", "https://gitlab.com/o/r/-/merge_requests/5", "https://gitlab.com/o/r/-/issues/7"},
+		"bitbucket": {"https://bitbucket.org/o/r", "https://bitbucket.org/o/r/pull-requests/5", "https://bitbucket.org/o/r/issues/7"},
+		"gitea":     {"https://codeberg.org/o/r", "https://codeberg.org/o/r/pulls/5", "https://codeberg.org/o/r/issues/7"},
+		"azure": {"https://dev.azure.com/org/proj/_git/r",
+			"https://dev.azure.com/org/proj/_git/r/pullrequest/5", "https://dev.azure.com/org/proj/_workitems/edit/7"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			links := newRepoLinks(nil, tt.repo)
+			if got := links.reference("pull", "5"); got != tt.pr {
+				t.Errorf("pull = %q, want %q", got, tt.pr)
+			}
+			if got := links.reference("issues", "7"); got != tt.issue {
+				t.Errorf("issue = %q, want %q", got, tt.issue)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Forge(t *testing.T) {
+	tests := map[string]struct {
+		yaml    string
+		wantErr bool
+	}{
+		"known forge":         {"forge: bitbucket\n", false},
+		"unknown forge":       {"forge: sourcehut\n", true},
+		"custom with url":     {"forge: custom\ncompare_url_template: \"{{.From}}\"\n", false},
+		"custom without url":  {"forge: custom\n", true},
+		"bad template syntax": {"compare_url_template: \"{{.From\"\n", true},
+		"unknown field":       {"compare_url_template: \"{{.Repo}}/compare/{{.From}}...{{.To}}\"\n", true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".chlog.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadConfig(path); (err != nil) != tt.wantErr {
+				t.Errorf("LoadConfig error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_TagPattern(t *testing.T) {
+	bare, mono := "", "api/v"
+	if got := (&Config{}).TagPattern(); got != "" {
+		t.Errorf("unset TagPattern = %q, want empty", got)
+	}
+	if got := (&Config{TagPrefix: &bare}).TagPattern(); got != "" {
+		t.Errorf("bare TagPattern = %q, want empty", got)
+	}
+	if got := (&Config{TagPrefix: &mono}).TagPattern(); got != "api/v*" {
+		t.Errorf("TagPattern = %q, want api/v*", got)
+	}
+}
+
+func TestRenderMarkdown_ForgeLinks(t *testing.T) {
+	c := bumpChangelog("fixed", "1.1.0", "1.0.0")
+	c.Versions[0].Public.Categories[0].Meta = []EntryMeta{{PR: 9}}
+	mono := "api/v"
+	cfg := &Config{RepoURL: "https://bitbucket.org/o/r", TagPrefix: &mono}
+
+	var b bytes.Buffer
+	if err := RenderMarkdown(c, &b, RenderOptions{Config: cfg}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"[Unreleased]: https://bitbucket.org/o/r/branches/compare/HEAD%0Dapi/v1.1.0",
+		"[1.1.0]: https://bitbucket.org/o/r/branches/compare/api/v1.1.0%0Dapi/v1.0.0",
+		"(https://bitbucket.org/o/r/pull-requests/9)",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, b.String())
+		}
+	}
+}
@@ -10,6 +10,8 @@ import (
 type categoryStyle struct {
 	Icon  string
 	Color *color.Color
+	// CSS is the terminal color as a CSS color, for HTML output.
+	CSS string
 }
 
 // FormatOptions controls terminal output formatting.
@@ -20,15 +22,15 @@ type FormatOptions struct {
 }
 
 var categoryStyles = map[string]categoryStyle{
-	"added":      {Icon: "+", Color: color.New(color.FgGreen)},
-	"changed":    {Icon: "~", Color: color.New(color.FgYellow)},
-	"deprecated": {Icon: "!", Color: color.New(color.FgYellow)},
-	"removed":    {Icon: "-", Color: color.New(color.FgRed)},
-	"fixed":      {Icon: "x", Color: color.New(color.FgCyan)},
-	"security":   {Icon: "🔒", Color: color.New(color.FgMagenta)},
+	"added":      {Icon: "+", Color: color.New(color.FgGreen), CSS: "#1a7f37"},
+	"changed":    {Icon: "~", Color: color.New(color.FgYellow), CSS: "#9a6700"},
+	"deprecated": {Icon: "!", Color: color.New(color.FgYellow), CSS: "#9a6700"},
+	"removed":    {Icon: "-", Color: color.New(color.FgRed), CSS: "#cf222e"},
+	"fixed":      {Icon: "x", Color: color.New(color.FgCyan), CSS: "#1b7c83"},
+	"security":   {Icon: "🔒", Color: color.New(color.FgMagenta), CSS: "#8250df"},
 }
 
-var defaultStyle = categoryStyle{Icon: "*", Color: color.New(color.FgWhite)}
+var defaultStyle = categoryStyle{Icon: "*", Color: color.New(color.FgWhite), CSS: "#57606a"}
 
 func styleFor(category string) categoryStyle {
 	if s, ok := categoryStyles[category]; ok {
@@ -92,8 +94,9 @@ func FormatVersion(v *Version, opts FormatOptions) string {
 			fmt.Fprintf(&b, "  %s\n", style.Color.Sprintf("%s %s", style.Icon, catHeader))
 		}
 
-		for _, entry := range cat.Entries {
-			wrapped := wrapText(entry, opts.MaxWidth-6, "      ")
+		for i, entry := range cat.Entries {
+			text := formatEntryTerminal(entry, cat.MetaAt(i), opts.Plain)
+			wrapped := wrapText(text, opts.MaxWidth-6, "      ")
 			fmt.Fprintf(&b, "    - %s\n", wrapped)
 		}
 	}
@@ -101,6 +104,29 @@ func FormatVersion(v *Version, opts FormatOptions) string {
 	return b.String()
 }
 
+// formatEntryTerminal renders entry text with its metadata for the terminal.
+func formatEntryTerminal(text string, meta EntryMeta, plain bool) string {
+	var b strings.Builder
+	if meta.Breaking {
+		if plain {
+			b.WriteString("BREAKING: ")
+		} else {
+			b.WriteString(color.New(color.FgRed, color.Bold).Sprint("BREAKING:") + " ")
+		}
+	}
+	if meta.Scope != "" {
+		fmt.Fprintf(&b, "%s: ", meta.Scope)
+	}
+	b.WriteString(text)
+	if refs := entryReferences(meta); len(refs) > 0 {
+		fmt.Fprintf(&b, " (%s)", strings.Join(refs, ", "))
+	}
+	if len(meta.Authors) > 0 {
+		fmt.Fprintf(&b, " by %s", strings.Join(meta.Authors, ", "))
+	}
+	return b.String()
+}
+
 func formatVersionHeader(v *Version) string {
 	if v.IsUnreleased() {
 		return "[Unreleased]"
@@ -150,3 +176,41 @@ func wrapText(text string, maxWidth int, indent string) string {
 
 	return strings.Join(lines, "\n"+indent)
 }
+
+// FormatDiff formats a changelog diff for terminal output: "+" for added,
+// "-" for removed and "~" for modified entries, grouped by version and
+// category.
+func FormatDiff(d *ChangelogDiff, opts FormatOptions) string {
+	if d.IsEmpty() {
+		return "No changelog changes\n"
+	}
+	paint := func(c *color.Color, s string) string {
+		if opts.Plain {
+			return s
+		}
+		return c.Sprint(s)
+	}
+	green, red, yellow := color.New(color.FgGreen), color.New(color.FgRed), color.New(color.FgYellow)
+
+	var b strings.Builder
+	for _, vd := range d.Versions {
+		fmt.Fprintf(&b, "%s (%s)\n", paint(color.New(color.FgMagenta, color.Bold), vd.Version), vd.Status)
+		if vd.OldDate != vd.NewDate {
+			fmt.Fprintf(&b, "  %s\n", paint(yellow, "~ date: "+describeChange(vd.OldDate, vd.NewDate)))
+		}
+		for _, cd := range vd.Categories {
+			fmt.Fprintf(&b, "  %s\n", paint(styleFor(cd.Category).Color, cd.label()))
+			for _, e := range cd.Added {
+				fmt.Fprintf(&b, "    %s\n", paint(green, "+ "+formatEntryTerminal(e.Text, e.EntryMeta, true)))
+			}
+			for _, e := range cd.Removed {
+				fmt.Fprintf(&b, "    %s\n", paint(red, "- "+formatEntryTerminal(e.Text, e.EntryMeta, true)))
+			}
+			for _, c := range cd.Modified {
+				old := formatEntryTerminal(c.Old.Text, c.Old.EntryMeta, true)
+				fmt.Fprintf(&b, "    %s\n", paint(yellow, "~ "+describeChange(old, formatEntryTerminal(c.New.Text, c.New.EntryMeta, true))))
+			}
+		}
+	}
+	return b.String()
+}
@@ -167,3 +167,12 @@ func TestFormatVersion_CustomCategory(t *testing.T) {
 		t.Error("expected entry text")
 	}
 }
+
+func TestFormatVersion_EntryMeta(t *testing.T) {
+	v := &Version{Version: "1.0.0", Date: "2024-01-01"}
+	v.Public.AppendWithMeta("changed", "Drop v1 API", EntryMeta{PR: 4, Scope: "api", Breaking: true})
+	out := FormatVersion(v, FormatOptions{Plain: true})
+	if !strings.Contains(out, "- BREAKING: api: Drop v1 API (#4)") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
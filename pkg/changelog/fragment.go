@@ -0,0 +1,316 @@
+package changelog
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFragmentDir is where unreleased entry fragments are kept.
+const DefaultFragmentDir = ".chlog/unreleased"
+
+// Fragment is a small YAML file of unreleased entries kept outside
+// CHANGELOG.yaml, so parallel branches do not edit the same block. It uses
+// the layout of a version block without a date: public categories at the top
+// level and an optional "internal" mapping.
+type Fragment struct {
+	Path     string
+	Public   Changes
+	Internal Changes
+
+	// node is the mapping the fragment was decoded from, if any, and source
+	// its document, kept so Save preserves comments and layout.
+	node   *yaml.Node
+	source *sourceDoc
+}
+
+// fragmentOverlay records what fragments contributed to the unreleased
+// version, so Save can leave them out of CHANGELOG.yaml.
+type fragmentOverlay struct {
+	frags []*Fragment
+	added Version
+	// created is set when the unreleased block exists only because of fragments.
+	created bool
+	// sources locates each overlaid entry in its fragment.
+	sources map[sourceKey]entrySource
+}
+
+// sourceKey identifies an entry of the unreleased version; overlaid entries
+// are unique by text within a category.
+type sourceKey struct {
+	internal bool
+	category string
+	text     string
+}
+
+// entrySource is the fragment file, index in the fragment's category and
+// YAML node an entry came from.
+type entrySource struct {
+	path  string
+	index int
+	node  *yaml.Node
+}
+
+// LoadFragment reads a fragment file.
+func LoadFragment(path string) (*Fragment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading fragment: %w", err)
+	}
+	return ParseFragment(path, data)
+}
+
+// ParseFragment decodes fragment data; path is recorded and used in errors.
+func ParseFragment(path string, data []byte) (*Fragment, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("decoding fragment %s: %w", path, err)
+	}
+	f := &Fragment{Path: path}
+	if len(doc.Content) == 0 {
+		return f, nil
+	}
+	var v Version
+	if err := doc.Decode(&v); err != nil {
+		var de *decodeError
+		if errors.As(err, &de) {
+			e := de.validationError()
+			e.File = path
+			return nil, ValidationErrors{e}
+		}
+		return nil, fmt.Errorf("decoding fragment %s: %w", path, err)
+	}
+	if v.Date != "" {
+		return nil, fmt.Errorf("fragment %s: date is not allowed in a fragment", path)
+	}
+	f.Public, f.Internal, f.node = v.Public, v.Internal, doc.Content[0]
+	f.source = newSourceDoc(&doc, data)
+	return f, nil
+}
+
+// LoadFragments reads every *.yaml and *.yml fragment in dir, sorted by file
+// name. A missing directory yields no fragments.
+func LoadFragments(dir string) ([]*Fragment, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading fragments: %w", err)
+	}
+	var frags []*Fragment
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if e.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		f, err := LoadFragment(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		frags = append(frags, f)
+	}
+	return frags, nil
+}
+
+// Save writes the fragment to its Path, creating the directory if needed.
+// A fragment read from a file keeps its comments and layout.
+func (f *Fragment) Save() error {
+	data, err := f.marshal()
+	if err != nil {
+		return fmt.Errorf("marshaling fragment: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0755); err != nil {
+		return fmt.Errorf("creating fragment directory: %w", err)
+	}
+	if err := os.WriteFile(f.Path, data, 0644); err != nil {
+		return fmt.Errorf("writing fragment %s: %w", f.Path, err)
+	}
+	return nil
+}
+
+func (f *Fragment) marshal() ([]byte, error) {
+	v := Version{Public: f.Public, Internal: f.Internal}
+	if f.source != nil {
+		return f.source.renderVersion(&v)
+	}
+	return yaml.Marshal(v)
+}
+
+var unsafeFragmentChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// FragmentName returns a file name for a new fragment: the branch name made
+// file-safe (e.g. "feat-login.yaml"), or a random id when branch is empty,
+// detached ("HEAD") or a default branch.
+func FragmentName(branch string) string {
+	switch branch {
+	case "", "HEAD", "main", "master":
+		b := make([]byte, 4)
+		_, _ = rand.Read(b)
+		return hex.EncodeToString(b) + ".yaml"
+	}
+	return strings.Trim(unsafeFragmentChars.ReplaceAllString(branch, "-"), "-.") + ".yaml"
+}
+
+// ApplyFragments overlays fragment entries onto the unreleased version,
+// creating it if needed and skipping entries already present. The entries
+// stay out of CHANGELOG.yaml on Save until Release folds them into a version.
+func (c *Changelog) ApplyFragments(frags []*Fragment) {
+	if len(frags) == 0 {
+		return
+	}
+	if c.overlay == nil {
+		c.overlay = &fragmentOverlay{sources: map[sourceKey]entrySource{}}
+	}
+	u := c.GetUnreleased()
+	if u == nil {
+		c.Versions = append([]Version{{Version: "unreleased"}}, c.Versions...)
+		u = &c.Versions[0]
+		c.overlay.created = true
+	}
+	for _, f := range frags {
+		c.overlay.changes(&u.Public, f, false)
+		c.overlay.changes(&u.Internal, f, true)
+		c.overlay.frags = append(c.overlay.frags, f)
+	}
+}
+
+// changes overlays the public or internal entries of f onto dst.
+func (o *fragmentOverlay) changes(dst *Changes, f *Fragment, internal bool) {
+	src, added, node := f.Public, &o.added.Public, f.node
+	if internal {
+		src, added, node = f.Internal, &o.added.Internal, mappingValue(f.node, "internal")
+	}
+	for _, cat := range src.Categories {
+		items := mappingValue(node, cat.Name)
+		for i, entry := range cat.Entries {
+			if !slices.Contains(dst.Get(cat.Name), entry) {
+				dst.AppendWithMeta(cat.Name, entry, cat.MetaAt(i))
+				added.AppendWithMeta(cat.Name, entry, cat.MetaAt(i))
+				o.sources[sourceKey{internal, cat.Name, entry}] = entrySource{f.Path, i, sequenceItem(items, i)}
+			}
+		}
+	}
+}
+
+// fragmentSource returns the fragment an entry of the unreleased version was
+// overlaid from, if any.
+func (c *Changelog) fragmentSource(internal bool, category, text string) (entrySource, bool) {
+	if c.overlay == nil {
+		return entrySource{}, false
+	}
+	src, ok := c.overlay.sources[sourceKey{internal, category, text}]
+	return src, ok
+}
+
+// Fragments returns the paths of the fragments applied to this changelog.
+// After Release they have been folded into the new version and can be
+// deleted with RemoveFragments.
+func (c *Changelog) Fragments() []string {
+	if c.overlay == nil {
+		return nil
+	}
+	paths := make([]string, len(c.overlay.frags))
+	for i, f := range c.overlay.frags {
+		paths[i] = f.Path
+	}
+	return paths
+}
+
+// RemoveFragmentEntry removes an entry of a public or internal category from
+// every applied fragment holding it, saving those fragments or deleting the
+// ones left empty, and returns their paths. Removing an overlaid entry from
+// the unreleased version alone does not last: the fragment adds it again on
+// the next Load.
+func (c *Changelog) RemoveFragmentEntry(internal bool, category, entry string) ([]string, error) {
+	if c.overlay == nil {
+		return nil, nil
+	}
+	added := &c.overlay.added.Public
+	if internal {
+		added = &c.overlay.added.Internal
+	}
+	_, _ = added.Remove(category, entry, false)
+	delete(c.overlay.sources, sourceKey{internal, category, entry})
+
+	var paths []string
+	for _, f := range c.overlay.frags {
+		changes := &f.Public
+		if internal {
+			changes = &f.Internal
+		}
+		if _, err := changes.Remove(category, entry, false); err != nil {
+			continue
+		}
+		paths = append(paths, f.Path)
+		if f.Public.IsEmpty() && f.Internal.IsEmpty() {
+			if err := RemoveFragments([]string{f.Path}); err != nil {
+				return paths, err
+			}
+		} else if err := f.Save(); err != nil {
+			return paths, err
+		}
+	}
+	return paths, nil
+}
+
+// RemoveFragments deletes fragment files, ignoring ones already gone.
+func RemoveFragments(paths []string) error {
+	for _, p := range paths {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing fragment: %w", err)
+		}
+	}
+	return nil
+}
+
+// consumeFragments marks the overlaid entries as part of the changelog, as
+// Release does when it promotes the unreleased version.
+func (c *Changelog) consumeFragments() {
+	if c.overlay != nil {
+		c.overlay.added = Version{}
+		c.overlay.created = false
+		clear(c.overlay.sources)
+	}
+}
+
+// withoutFragments returns a shallow copy of c whose unreleased version no
+// longer holds the entries contributed by fragments.
+func (c *Changelog) withoutFragments() *Changelog {
+	if c.overlay == nil {
+		return c
+	}
+	out := *c
+	out.Versions = slices.Clone(c.Versions)
+	for i := range out.Versions {
+		v := &out.Versions[i]
+		if !v.IsUnreleased() {
+			continue
+		}
+		v.Public, v.Internal = v.Public.Clone(), v.Internal.Clone()
+		v.Public.subtract(c.overlay.added.Public)
+		v.Internal.subtract(c.overlay.added.Internal)
+		if c.overlay.created && v.IsEmpty() && v.Internal.IsEmpty() {
+			out.Versions = slices.Delete(out.Versions, i, i+1)
+		}
+		break
+	}
+	return &out
+}
+
+// subtract removes one exact match of each entry in other, if present.
+func (c *Changes) subtract(other Changes) {
+	for _, cat := range other.Categories {
+		for _, entry := range cat.Entries {
+			_, _ = c.Remove(cat.Name, entry, false)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package changelog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFragment(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadFragments(t *testing.T) {
+	dir := t.TempDir()
+	writeFragment(t, dir, "b.yaml", "fixed:\n  - Fix B\n")
+	writeFragment(t, dir, "a.yml", "added:\n  - Add A\ninternal:\n  changed:\n    - Refactor A\n")
+	writeFragment(t, dir, "notes.txt", "ignored")
+
+	frags, err := LoadFragments(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(frags) != 2 || filepath.Base(frags[0].Path) != "a.yml" {
+		t.Fatalf("LoadFragments = %v", frags)
+	}
+	if got := frags[0].Internal.Get("changed"); len(got) != 1 || got[0] != "Refactor A" {
+		t.Errorf("internal = %v", got)
+	}
+
+	if frags, err := LoadFragments(filepath.Join(dir, "missing")); err != nil || frags != nil {
+		t.Errorf("missing dir = %v, %v; want nil, nil", frags, err)
+	}
+	writeFragment(t, dir, "c.yaml", "date: 2024-01-01\n")
+	if _, err := LoadFragments(dir); err == nil {
+		t.Error("expected error for a fragment with a date")
+	}
+}
+
+func TestFragment_SaveRoundTrip(t *testing.T) {
+	f := &Fragment{Path: filepath.Join(t.TempDir(), "nested", "feat.yaml")}
+	f.Public.AppendWithMeta("added", "Login", EntryMeta{PR: 3})
+	if err := f.Save(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadFragment(f.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cat, _ := got.Public.Category("added"); cat.MetaAt(0).PR != 3 {
+		t.Errorf("round trip lost metadata: %+v", got.Public)
+	}
+}
+
+func TestFragment_SaveKeepsComments(t *testing.T) {
+	dir := t.TempDir()
+	writeFragment(t, dir, "feat.yaml", "# keep me\nadded:\n  - Login # note\n  - Logout\ninternal:\n  changed:\n    - refactor auth\n")
+	f, err := LoadFragment(filepath.Join(dir, "feat.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Public.Remove("added", "Logout", false); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := FixLintFragment(f, LintConfig{}); err != nil || n != 1 {
+		t.Fatalf("FixLintFragment = %d, %v", n, err)
+	}
+	if err := f.Save(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# keep me\nadded:\n  - Login # note\ninternal:\n  changed:\n    - Refactor auth\n"
+	if string(data) != want {
+		t.Errorf("saved fragment:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestFragmentName(t *testing.T) {
+	if got := FragmentName("feat/Login page"); got != "feat-Login-page.yaml" {
+		t.Errorf("FragmentName = %q", got)
+	}
+	for _, branch := range []string{"", "HEAD", "main", "master"} {
+		name := FragmentName(branch)
+		if len(name) != len("01234567.yaml") || name == FragmentName(branch) {
+			t.Errorf("FragmentName(%q) = %q, want a random id", branch, name)
+		}
+	}
+}
+
+func TestApplyFragments_SaveExcludesOverlay(t *testing.T) {
+	c, err := LoadFromReader(strings.NewReader("project: demo\nversions:\n  unreleased:\n    added:\n      - Existing\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &Fragment{Path: "feat.yaml"}
+	f.Public.Append("added", "Existing")
+	f.Public.Append("fixed", "From fragment")
+	c.ApplyFragments([]*Fragment{f})
+
+	if got := c.GetUnreleased().Public.Get("fixed"); len(got) != 1 {
+		t.Fatalf("overlay missing: %v", c.GetUnreleased().Public)
+	}
+	if got := c.GetUnreleased().Public.Get("added"); len(got) != 1 {
+		t.Errorf("duplicate entry overlaid: %v", got)
+	}
+	data, err := Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "From fragment") {
+		t.Errorf("Marshal wrote fragment entries:\n%s", data)
+	}
+	if got := c.Fragments(); len(got) != 1 || got[0] != "feat.yaml" {
+		t.Errorf("Fragments = %v", got)
+	}
+}
+
+func TestApplyFragments_CreatesUnreleased(t *testing.T) {
+	c := &Changelog{Project: "demo", Versions: []Version{{Version: "1.0.0", Date: "2024-01-01"}}}
+	c.Versions[0].Public.Append("added", "Old")
+	f := &Fragment{Path: "x.yaml"}
+	f.Public.Append("added", "New")
+	c.ApplyFragments([]*Fragment{f})
+
+	if u := c.GetUnreleased(); u == nil || u.Count() != 1 {
+		t.Fatalf("unreleased = %+v", u)
+	}
+	data, err := Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "unreleased") {
+		t.Errorf("Marshal kept a fragment-only unreleased block:\n%s", data)
+	}
+}
+
+func TestRelease_ConsumesFragments(t *testing.T) {
+	c := &Changelog{Project: "demo", Versions: []Version{{Version: "unreleased"}}}
+	f := &Fragment{Path: "x.yaml"}
+	f.Public.Append("added", "New")
+	f.Internal.Append("changed", "Refactor")
+	c.ApplyFragments([]*Fragment{f})
+	if err := c.Release("1.0.0", "2024-06-01"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"New", "Refactor"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("released YAML missing %q:\n%s", want, data)
+		}
+	}
+	if len(c.Fragments()) != 1 {
+		t.Errorf("Fragments = %v, want the consumed path", c.Fragments())
+	}
+}
+
+func TestLoad_FragmentsConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "CHANGELOG.yaml")
+	if err := os.WriteFile(path, []byte("project: demo\nversions:\n  unreleased: {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fragDir := filepath.Join(dir, "frags")
+	writeFragment(t, fragDir, "a.yaml", "added:\n  - From fragment\n")
+
+	c, err := Load(path, &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.GetUnreleased().IsEmpty() {
+		t.Error("fragments applied without fragments: true")
+	}
+
+	c, err = Load(path, &Config{Fragments: true, FragmentDir: fragDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetUnreleased().Public.Get("added"); len(got) != 1 {
+		t.Errorf("unreleased added = %v", got)
+	}
+
+	writeFragment(t, fragDir, "b.yaml", "bogus:\n  - Bad category\n")
+	if _, err := Load(path, &Config{Fragments: true, FragmentDir: fragDir}); err == nil {
+		t.Error("expected validation error for a fragment category")
+	}
+}
+
+func TestRemoveFragmentEntry(t *testing.T) {
+	dir := t.TempDir()
+	writeFragment(t, dir, "a.yaml", "added:\n  - New\n  - Other\n")
+	writeFragment(t, dir, "b.yaml", "internal:\n  changed:\n    - Refactor\n")
+	frags, err := LoadFragments(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Changelog{Project: "demo", Versions: []Version{{Version: "unreleased"}}}
+	c.ApplyFragments(frags)
+
+	for _, tt := range []struct {
+		internal        bool
+		category, entry string
+	}{{false, "added", "New"}, {true, "changed", "Refactor"}} {
+		changes := &c.GetUnreleased().Public
+		if tt.internal {
+			changes = &c.GetUnreleased().Internal
+		}
+		if _, err := changes.Remove(tt.category, tt.entry, false); err != nil {
+			t.Fatal(err)
+		}
+		if paths, err := c.RemoveFragmentEntry(tt.internal, tt.category, tt.entry); err != nil || len(paths) != 1 {
+			t.Errorf("RemoveFragmentEntry(%q) = %v, %v", tt.entry, paths, err)
+		}
+	}
+
+	frags, err = LoadFragments(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(frags) != 1 || strings.Join(frags[0].Public.Get("added"), ",") != "Other" {
+		t.Errorf("fragments after removal = %+v", frags)
+	}
+}
+
+func TestLoad_FragmentErrorPositions(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "CHANGELOG.yaml")
+	if err := os.WriteFile(path, []byte("project: demo\nversions:\n  unreleased:\n    added:\n      - Existing\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fragDir := filepath.Join(dir, "frags")
+	writeFragment(t, fragDir, "feat.yaml", "added:\n  - Fine\nbogus:\n  - Bad category\ninternal:\n  changed:\n    - \"\"\n")
+
+	_, err := Load(path, &Config{Fragments: true, FragmentDir: fragDir})
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("err = %v, want ValidationErrors", err)
+	}
+	want := []string{
+		filepath.Join(fragDir, "feat.yaml") + ":3:1: bogus: unknown category \"bogus\"",
+		filepath.Join(fragDir, "feat.yaml") + ":7:7: internal.changed[0]: entry must not be empty",
+	}
+	if len(verrs) != len(want) {
+		t.Fatalf("errors = %v", verrs)
+	}
+	for i, e := range verrs {
+		if e.Error() != want[i] {
+			t.Errorf("error %d = %q, want %q", i, e.Error(), want[i])
+		}
+	}
+}
@@ -1,8 +1,12 @@
 package changelog
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"path"
+	"path/filepath"
 	"strings"
 )
 
@@ -37,15 +41,207 @@ func GitLog(sinceTag string) ([]GitCommit, error) {
 	return parseGitLog(strings.TrimSpace(string(out))), nil
 }
 
-// LatestTag returns the most recent tag reachable from HEAD.
-func LatestTag() (string, error) {
-	out, err := exec.Command("git", "describe", "--tags", "--abbrev=0").Output()
+// LatestTag returns the most recent tag reachable from HEAD. An optional glob
+// pattern (see Config.TagPattern) restricts which tags are considered.
+func LatestTag(pattern ...string) (string, error) {
+	args := []string{"describe", "--tags", "--abbrev=0"}
+	if len(pattern) > 0 && pattern[0] != "" {
+		args = append(args, "--match", pattern[0])
+	}
+	out, err := exec.Command("git", args...).Output()
 	if err != nil {
 		return "", fmt.Errorf("getting latest tag: %w", err)
 	}
 	return strings.TrimSpace(string(out)), nil
 }
 
+// CurrentBranch returns the checked-out branch name, or "HEAD" when detached.
+func CurrentBranch() (string, error) {
+	out, err := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("getting current branch: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// DirtyFiles returns the paths git reports as modified, staged or untracked.
+func DirtyFiles() ([]string, error) {
+	out, err := exec.Command("git", "status", "--porcelain").Output()
+	if err != nil {
+		return nil, fmt.Errorf("getting git status: %w", err)
+	}
+	var files []string
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		if len(line) > 3 {
+			files = append(files, line[3:])
+		}
+	}
+	return files, nil
+}
+
+// TagExists reports whether a tag with the given name exists.
+func TagExists(name string) bool {
+	return exec.Command("git", "rev-parse", "-q", "--verify", "refs/tags/"+name).Run() == nil
+}
+
+// RevisionExists reports whether rev names a commit, such as a branch, tag
+// or hash.
+func RevisionExists(rev string) bool {
+	return exec.Command("git", "rev-parse", "-q", "--verify", rev+"^{commit}").Run() == nil
+}
+
+// CommitFiles stages the given paths and commits them with message,
+// GPG-signing the commit if sign is set.
+func CommitFiles(message string, paths []string, sign bool) error {
+	if err := runGit(append([]string{"add", "--"}, paths...)...); err != nil {
+		return fmt.Errorf("staging files: %w", err)
+	}
+	args := []string{"commit", "-m", message}
+	if sign {
+		args = append(args, "-S")
+	}
+	if err := runGit(args...); err != nil {
+		return fmt.Errorf("committing: %w", err)
+	}
+	return nil
+}
+
+// CreateTag creates an annotated tag at HEAD, or a signed tag if sign is set.
+// The message is kept verbatim apart from whitespace, so markdown headings
+// are not stripped as comments.
+func CreateTag(name, message string, sign bool) error {
+	mode := "-a"
+	if sign {
+		mode = "-s"
+	}
+	if err := runGit("tag", mode, "--cleanup=whitespace", "-m", message, name); err != nil {
+		return fmt.Errorf("creating tag %s: %w", name, err)
+	}
+	return nil
+}
+
+// ShowFile returns the contents of path (relative to the working directory)
+// at a git ref.
+func ShowFile(ref, path string) ([]byte, error) {
+	out, err := exec.Command("git", "show", ref+":./"+filepath.ToSlash(path)).Output()
+	if err != nil {
+		return nil, fmt.Errorf("reading %s at %s: %w", path, ref, err)
+	}
+	return out, nil
+}
+
+// ListFiles returns the files under dir at a git ref.
+func ListFiles(ref, dir string) ([]string, error) {
+	out, err := exec.Command("git", "ls-tree", "-r", "--name-only", ref, "--", dir).Output()
+	if err != nil {
+		return nil, fmt.Errorf("listing %s at %s: %w", dir, ref, err)
+	}
+	return splitNonEmptyLines(string(out)), nil
+}
+
+// MergeBase returns the commit where HEAD forked from ref.
+func MergeBase(ref string) (string, error) {
+	out, err := exec.Command("git", "merge-base", ref, "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("finding merge base with %s: %w", ref, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ChangedFiles returns the paths changed between the merge base of ref and
+// HEAD and the working tree, including untracked files, relative to the
+// repository root.
+func ChangedFiles(ref string) ([]string, error) {
+	base, err := MergeBase(ref)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := exec.Command("git", "diff", "--name-only", base).Output()
+	if err != nil {
+		return nil, fmt.Errorf("listing changes since %s: %w", ref, err)
+	}
+	untracked, err := exec.Command("git", "ls-files", "--others", "--exclude-standard", "--full-name").Output()
+	if err != nil {
+		return nil, fmt.Errorf("listing untracked files: %w", err)
+	}
+	return append(splitNonEmptyLines(string(diff)), splitNonEmptyLines(string(untracked))...), nil
+}
+
+// RepoPaths converts paths relative to the working directory, or absolute,
+// to slash-separated paths relative to the repository root, the form
+// ChangedFiles reports.
+func RepoPaths(paths ...string) ([]string, error) {
+	out, err := exec.Command("git", "rev-parse", "--show-prefix").Output()
+	if err != nil {
+		return nil, fmt.Errorf("locating repository root: %w", err)
+	}
+	prefix := strings.TrimSpace(string(out))
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("locating working directory: %w", err)
+	}
+	rel := make([]string, len(paths))
+	for i, p := range paths {
+		if filepath.IsAbs(p) {
+			if r, err := filepath.Rel(wd, p); err == nil {
+				p = r
+			}
+		}
+		rel[i] = path.Join(prefix, filepath.ToSlash(p))
+	}
+	return rel, nil
+}
+
+// CommitMessages returns the full messages of the commits in ref..HEAD.
+func CommitMessages(ref string) (string, error) {
+	out, err := exec.Command("git", "log", "--format=%B", ref+"..HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("reading commits since %s: %w", ref, err)
+	}
+	return string(out), nil
+}
+
+func splitNonEmptyLines(s string) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// SetGitConfig sets a key in the repository's git config.
+func SetGitConfig(key, value string) error {
+	if err := runGit("config", key, value); err != nil {
+		return fmt.Errorf("setting %s: %w", key, err)
+	}
+	return nil
+}
+
+// MergeFile runs a textual three-way merge with git merge-file, writing the
+// result with conflict markers into current. It reports whether conflicts remain.
+func MergeFile(current, base, other string) (bool, error) {
+	err := exec.Command("git", "merge-file", current, base, other).Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 && exitErr.ExitCode() < 128 {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("git merge-file: %w", err)
+	}
+	return false, nil
+}
+
+// runGit runs a git command, including its stderr in the returned error.
+func runGit(args ...string) error {
+	out, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
 func parseGitLog(output string) []GitCommit {
 	if output == "" {
 		return nil
@@ -0,0 +1,184 @@
+package changelog
+
+import (
+	_ "embed"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+//go:embed templates/changelog.html.tmpl
+var htmlTemplateText string
+
+var htmlTemplate = template.Must(template.New("changelog.html").Funcs(template.FuncMap{
+	"anchor": versionAnchor,
+	"label":  htmlVersionLabel,
+	"slug":   slug,
+	"icon":   func(category string) string { return styleFor(category).Icon },
+	"inline": inlineMarkdownHTML,
+}).Parse(htmlTemplateText))
+
+// htmlData is the model of the HTML template.
+type htmlData struct {
+	TemplateData
+	// BadgeCSS colors the category badges like the terminal output.
+	BadgeCSS template.CSS
+}
+
+// RenderHTML writes the changelog as a self-contained, styled HTML page with
+// a section and anchor per version, category badges, compare links and
+// inline markdown (code spans, links, bold) rendered in entries. Internal
+// entries, when included, are marked and can be hidden with a toggle.
+// opts.Template is ignored: it is a markdown template.
+func RenderHTML(c *Changelog, w io.Writer, opts ...RenderOptions) error {
+	var opt RenderOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	data := htmlData{TemplateData: NewTemplateData(c, opt, ResolveRepoURL(opt.Config)), BadgeCSS: badgeCSS()}
+	if err := htmlTemplate.Execute(w, data); err != nil {
+		return fmt.Errorf("rendering HTML: %w", err)
+	}
+	return nil
+}
+
+// RenderHTMLString renders the changelog HTML page to a string.
+func RenderHTMLString(c *Changelog, opts ...RenderOptions) (string, error) {
+	var b strings.Builder
+	if err := RenderHTML(c, &b, opts...); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// htmlVersionLabel is versionLabel for template data.
+func htmlVersionLabel(v TemplateVersion) string {
+	if v.Unreleased {
+		return "Unreleased"
+	}
+	return v.Version
+}
+
+// versionAnchor returns the element ID of a version, e.g. "v1.2.0".
+func versionAnchor(v TemplateVersion) string {
+	if v.Unreleased {
+		return "unreleased"
+	}
+	return "v" + slug(strings.TrimPrefix(v.Version, "v"))
+}
+
+var slugUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// slug makes s safe for an ID or class name.
+func slug(s string) string {
+	return slugUnsafe.ReplaceAllString(s, "-")
+}
+
+func badgeCSS() template.CSS {
+	var b strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(categoryStyles)) {
+		fmt.Fprintf(&b, ".badge-%s { --badge: %s; }\n", name, categoryStyles[name].CSS)
+	}
+	return template.CSS(b.String())
+}
+
+// inlineMarkdownHTML renders the inline markdown of an entry as HTML: code
+// spans, [text](url) links with http, https, mailto or relative URLs, and
+// **bold**. Everything else is escaped.
+func inlineMarkdownHTML(s string) template.HTML {
+	var b strings.Builder
+	writeInlineHTML(&b, s, true)
+	return template.HTML(b.String())
+}
+
+func writeInlineHTML(b *strings.Builder, s string, links bool) {
+	for len(s) > 0 {
+		i := strings.IndexAny(s, "`[*")
+		if i < 0 {
+			b.WriteString(html.EscapeString(s))
+			return
+		}
+		b.WriteString(html.EscapeString(s[:i]))
+		s = s[i:]
+		if n := writeInlineToken(b, s, links); n > 0 {
+			s = s[n:]
+			continue
+		}
+		b.WriteString(html.EscapeString(s[:1]))
+		s = s[1:]
+	}
+}
+
+// writeInlineToken renders the code span, link or bold text at the start of
+// s and returns its length, or 0 if s does not start with one.
+func writeInlineToken(b *strings.Builder, s string, links bool) int {
+	switch {
+	case s[0] == '`':
+		end := strings.IndexByte(s[1:], '`')
+		if end < 0 {
+			return 0
+		}
+		b.WriteString("<code>" + html.EscapeString(s[1:end+1]) + "</code>")
+		return end + 2
+	case strings.HasPrefix(s, "**"):
+		end := strings.Index(s[2:], "**")
+		if end <= 0 {
+			return 0
+		}
+		b.WriteString("<strong>")
+		writeInlineHTML(b, s[2:end+2], links)
+		b.WriteString("</strong>")
+		return end + 4
+	case s[0] == '[' && links:
+		text, rest, ok := strings.Cut(s[1:], "](")
+		if !ok || strings.Contains(text, "]") {
+			return 0
+		}
+		end := linkDestinationEnd(rest)
+		if end < 0 || !safeURL(rest[:end]) {
+			return 0
+		}
+		href := rest[:end]
+		b.WriteString(`<a href="` + html.EscapeString(href) + `">`)
+		writeInlineHTML(b, text, false)
+		b.WriteString("</a>")
+		return 1 + len(text) + 2 + len(href) + 1
+	}
+	return 0
+}
+
+// linkDestinationEnd returns the index of the ")" closing the link
+// destination at the start of s, or -1. As in CommonMark, parentheses inside
+// the destination must be balanced, e.g. "https://e.com/a_(b))".
+func linkDestinationEnd(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		case ' ', '\t', '\n':
+			return -1
+		}
+	}
+	return -1
+}
+
+// safeURL accepts http, https and mailto URLs and relative references.
+func safeURL(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil || strings.ContainsAny(s, " \t\n") {
+		return false
+	}
+	return slices.Contains([]string{"", "http", "https", "mailto"}, strings.ToLower(u.Scheme))
+}
@@ -0,0 +1,86 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInlineMarkdownHTML(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want string
+	}{
+		"plain":          {"Login page", "Login page"},
+		"escaped":        {`<b>"x" & y</b>`, "&lt;b&gt;&#34;x&#34; &amp; y&lt;/b&gt;"},
+		"code span":      {"Use `--json <file>`", "Use <code>--json &lt;file&gt;</code>"},
+		"link":           {"Fixed ([#12](https://x.dev/pull/12))", `Fixed (<a href="https://x.dev/pull/12">#12</a>)`},
+		"code in link":   {"[`x`](docs/x.md)", `<a href="docs/x.md"><code>x</code></a>`},
+		"unsafe link":    {"[x](javascript:alert(1))", "[x](javascript:alert(1))"},
+		"parens in link": {"[ok](https://e.com/a_(b)) done", `<a href="https://e.com/a_(b)">ok</a> done`},
+		"unbalanced":     {"[ok](https://e.com/a_(b) done", "[ok](https://e.com/a_(b) done"},
+		"bold":           {"**BREAKING:** Drop v1", "<strong>BREAKING:</strong> Drop v1"},
+		"unclosed marks": {"a ` b [c] **d", "a ` b [c] **d"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := string(inlineMarkdownHTML(tt.in)); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	c := &Changelog{Project: "demo", Versions: []Version{
+		{Version: "unreleased"},
+		{Version: "1.0.0", Date: "2024-01-01"},
+		{Version: "0.9.0", Date: "2023-12-01"},
+	}}
+	c.Versions[0].Public.AppendWithMeta("added", "Search <beta>", EntryMeta{PR: 7})
+	c.Versions[0].Internal.Append("changed", "Refactored `store`")
+	c.Versions[1].Public.Append("fixed", "Crash")
+	c.Versions[2].Public.Append("added", "First release")
+	cfg := &Config{RepoURL: "https://github.com/acme/demo"}
+
+	tests := map[string]struct {
+		internal bool
+		want     []string
+		absent   []string
+	}{
+		"public": {
+			want: []string{
+				"<!DOCTYPE html>",
+				"<title>demo Changelog</title>",
+				`<section id="v1.0.0">`,
+				`<a class="anchor" href="#unreleased">Unreleased</a> <a class="compare" href="https://github.com/acme/demo/compare/v1.0.0...HEAD">compare</a>`,
+				`<time datetime="2024-01-01">2024-01-01</time>`,
+				`<span class="badge badge-fixed"><span class="icon">x</span>Fixed</span>`,
+				`<li>Search &lt;beta&gt; (<a href="https://github.com/acme/demo/pull/7">#7</a>)</li>`,
+				".badge-added { --badge: #1a7f37; }",
+			},
+			absent: []string{"show-internal\"", "Refactored"},
+		},
+		"internal": {
+			internal: true,
+			want:     []string{`<input type="checkbox" id="show-internal" checked hidden>`, `<li class="internal">Refactored <code>store</code></li>`},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := RenderHTMLString(c, RenderOptions{Config: cfg, IncludeInternal: tt.internal})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("missing %q in\n%s", w, got)
+				}
+			}
+			for _, a := range tt.absent {
+				if strings.Contains(got, a) {
+					t.Errorf("unexpected %q in output", a)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package changelog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EditKind classifies a change made to an already released version.
+type EditKind string
+
+const (
+	EditAdded    EditKind = "added"
+	EditRemoved  EditKind = "removed"
+	EditReworded EditKind = "reworded"
+	EditDate     EditKind = "date"
+	// EditDeleted means the whole version block was removed.
+	EditDeleted EditKind = "deleted"
+)
+
+// ReleaseEdit is one change to a released version. Old and New hold the
+// entry texts, or the dates for EditDate.
+type ReleaseEdit struct {
+	Version  string   `json:"version"`
+	Kind     EditKind `json:"kind"`
+	Category string   `json:"category,omitempty"`
+	Internal bool     `json:"internal,omitempty"`
+	Old      string   `json:"old,omitempty"`
+	New      string   `json:"new,omitempty"`
+}
+
+func (e ReleaseEdit) String() string {
+	cat := e.Category
+	if e.Internal {
+		cat = "internal." + cat
+	}
+	switch e.Kind {
+	case EditDate:
+		return fmt.Sprintf("%s: date changed from %s to %s", e.Version, e.Old, e.New)
+	case EditDeleted:
+		return fmt.Sprintf("%s: version removed", e.Version)
+	case EditAdded:
+		return fmt.Sprintf("%s %s: added %q", e.Version, cat, e.New)
+	case EditRemoved:
+		return fmt.Sprintf("%s %s: removed %q", e.Version, cat, e.Old)
+	default:
+		return fmt.Sprintf("%s %s: reworded %q to %q", e.Version, cat, e.Old, e.New)
+	}
+}
+
+// DiffChangelogs reports how updated changes the versions already released
+// in old: entries added, removed or reworded, dates changed and versions
+// deleted. The unreleased version, versions new in updated and metadata-only
+// changes are ignored. It is a view of Diff for guarding release history.
+func DiffChangelogs(old, updated *Changelog) []ReleaseEdit {
+	var edits []ReleaseEdit
+	for _, vd := range Diff(old, updated).Versions {
+		switch {
+		case vd.Status == DiffAdded || strings.EqualFold(vd.Version, "unreleased"):
+			continue
+		case vd.Status == DiffRemoved:
+			edits = append(edits, ReleaseEdit{Version: vd.Version, Kind: EditDeleted})
+			continue
+		case vd.OldDate != vd.NewDate:
+			edits = append(edits, ReleaseEdit{Version: vd.Version, Kind: EditDate, Old: vd.OldDate, New: vd.NewDate})
+		}
+		for _, cd := range vd.Categories {
+			edits = append(edits, categoryEdits(vd.Version, cd)...)
+		}
+	}
+	return edits
+}
+
+func categoryEdits(version string, cd CategoryDiff) []ReleaseEdit {
+	edit := ReleaseEdit{Version: version, Category: cd.Category, Internal: cd.Internal}
+	var edits []ReleaseEdit
+	for _, c := range cd.Modified {
+		if c.Old.Text != c.New.Text {
+			e := edit
+			e.Kind, e.Old, e.New = EditReworded, c.Old.Text, c.New.Text
+			edits = append(edits, e)
+		}
+	}
+	for _, a := range cd.Added {
+		e := edit
+		e.Kind, e.New = EditAdded, a.Text
+		edits = append(edits, e)
+	}
+	for _, r := range cd.Removed {
+		e := edit
+		e.Kind, e.Old = EditRemoved, r.Text
+		edits = append(edits, e)
+	}
+	return edits
+}
@@ -0,0 +1,82 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+)
+
+const releasedBase = `project: demo
+versions:
+  unreleased:
+    added:
+      - Pending
+  0.2.0:
+    date: 2024-02-01
+    fixed:
+      - Fix crash
+      - Fix leak
+    internal:
+      changed:
+        - Refactor
+  0.1.0:
+    date: 2024-01-01
+    added:
+      - First
+`
+
+func TestDiffChangelogs(t *testing.T) {
+	tests := map[string]struct {
+		edit func(*Changelog)
+		want []string
+	}{
+		"unchanged": {
+			edit: func(c *Changelog) {},
+		},
+		"unreleased and new versions ignored": {
+			edit: func(c *Changelog) {
+				c.GetUnreleased().Public.Append("added", "More")
+				_ = c.Release("0.3.0", "2024-03-01")
+			},
+		},
+		"reworded": {
+			edit: func(c *Changelog) { c.Versions[1].Public.Categories[0].Entries[1] = "Fix memory leak" },
+			want: []string{`0.2.0 fixed: reworded "Fix leak" to "Fix memory leak"`},
+		},
+		"added and removed": {
+			edit: func(c *Changelog) {
+				c.Versions[2].Public.Append("security", "Patch CVE")
+				_, _ = c.Versions[1].Internal.Remove("changed", "Refactor", false)
+			},
+			want: []string{
+				`0.2.0 internal.changed: removed "Refactor"`,
+				`0.1.0 security: added "Patch CVE"`,
+			},
+		},
+		"date changed": {
+			edit: func(c *Changelog) { c.Versions[2].Date = "2024-01-02" },
+			want: []string{"0.1.0: date changed from 2024-01-01 to 2024-01-02"},
+		},
+		"version deleted": {
+			edit: func(c *Changelog) { c.Versions = c.Versions[:2] },
+			want: []string{"0.1.0: version removed"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			old, err := LoadFromReader(strings.NewReader(releasedBase))
+			if err != nil {
+				t.Fatal(err)
+			}
+			updated, _ := LoadFromReader(strings.NewReader(releasedBase))
+			tt.edit(updated)
+
+			var got []string
+			for _, e := range DiffChangelogs(old, updated) {
+				got = append(got, e.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("edits =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
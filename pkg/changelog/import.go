@@ -0,0 +1,321 @@
+package changelog
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ImportOptions controls how an existing changelog is imported.
+type ImportOptions struct {
+	// Project overrides the project name found in the document.
+	Project string
+	// Config selects the allowed categories; entries in other categories are skipped.
+	Config *Config
+}
+
+// ImportWarning describes content that could not be mapped during import.
+type ImportWarning struct {
+	Line    int
+	Message string
+}
+
+func (w ImportWarning) String() string {
+	return fmt.Sprintf("line %d: %s", w.Line, w.Message)
+}
+
+// MarkdownImport is the result of ImportMarkdown.
+type MarkdownImport struct {
+	Changelog *Changelog
+	// RepoURL is derived from comparison link definitions, if present.
+	RepoURL  string
+	Warnings []ImportWarning
+}
+
+var (
+	mdVersionHeading = regexp.MustCompile(`^##\s+\[?([^\]\s]+)\]?\s*(?:[-–—]\s*)?(.*)$`)
+	mdCategory       = regexp.MustCompile(`^###\s+(.+?)\s*$`)
+	mdBullet         = regexp.MustCompile(`^[-*+]\s+(.*)$`)
+	mdNestedBullet   = regexp.MustCompile(`^\s+[-*+]\s+`)
+	mdLinkDef        = regexp.MustCompile(`^\[([^\]]+)\]:\s*(\S+)`)
+	mdCompareURL     = regexp.MustCompile(`^(.+?)(?:/-)?/compare/`)
+	mdProject        = regexp.MustCompile(`^All notable changes to (.+?) will be documented`)
+	mdBreaking       = regexp.MustCompile(`^\*\*BREAKING:\*\*\s+`)
+	mdScope          = regexp.MustCompile(`^\*\*([^*]+):\*\*\s+`)
+	mdRefs           = regexp.MustCompile(`\s+\(((?:\[#\d+\]\([^)]+\)(?:,\s*)?)+)\)$`)
+	mdRefLink        = regexp.MustCompile(`\[#(\d+)\]\(([^)]+)\)`)
+)
+
+// categoryAliases maps common heading variants to Keep a Changelog categories.
+var categoryAliases = map[string]string{
+	"features":     "added",
+	"new":          "added",
+	"fixes":        "fixed",
+	"bug fixes":    "fixed",
+	"bugfixes":     "fixed",
+	"deprecations": "deprecated",
+	"removals":     "removed",
+	"changes":      "changed",
+}
+
+var importDateLayouts = []string{"2006-01-02", "2006/01/02", "January 2, 2006", "Jan 2, 2006", "2 January 2006"}
+
+// ImportMarkdown parses a Keep a Changelog markdown document — the inverse of
+// RenderMarkdown. It understands "## [x.y.z] - date" headings, "### Category"
+// sections, bullet lists with indented continuation lines, the [Unreleased]
+// block and trailing comparison link definitions.
+//
+// Content that cannot be mapped is skipped and reported in Warnings. Released
+// versions without a usable date or without entries are skipped too, so the
+// resulting Changelog passes Validate — except that Project is empty when
+// neither the document nor opts name one.
+func ImportMarkdown(r io.Reader, opts ...ImportOptions) (*MarkdownImport, error) {
+	var opt ImportOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	imp := newMDImporter(opt)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		imp.line++
+		imp.parseLine(strings.TrimRight(scanner.Text(), " \t\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading markdown: %w", err)
+	}
+	imp.flushBullet()
+	imp.finishVersion()
+
+	c := imp.result.Changelog
+	if opt.Project != "" {
+		c.Project = opt.Project
+	}
+	for _, e := range Validate(c, opt.Config) {
+		// A missing project name is left for the caller to fill in.
+		if e.Field != "project" {
+			return nil, fmt.Errorf("imported changelog is invalid: %w", e)
+		}
+	}
+	return &imp.result, nil
+}
+
+// mdImporter is the line-oriented state machine behind ImportMarkdown.
+type mdImporter struct {
+	line       int
+	allowed    map[string]bool // nil accepts any category
+	result     MarkdownImport
+	version    *Version
+	headerLine int
+	category   string
+	skipCat    bool
+	bullet     []string
+	bulletLine int
+}
+
+func newMDImporter(opt ImportOptions) *mdImporter {
+	imp := &mdImporter{result: MarkdownImport{Changelog: &Changelog{}}}
+	allowed := DefaultCategories
+	if opt.Config != nil {
+		allowed = opt.Config.AllowedCategories()
+	}
+	if allowed != nil {
+		imp.allowed = make(map[string]bool, len(allowed))
+		for _, cat := range allowed {
+			imp.allowed[cat] = true
+		}
+	}
+	return imp
+}
+
+func (imp *mdImporter) warn(line int, format string, a ...any) {
+	imp.result.Warnings = append(imp.result.Warnings, ImportWarning{Line: line, Message: fmt.Sprintf(format, a...)})
+}
+
+func (imp *mdImporter) parseLine(line string) {
+	switch {
+	case strings.TrimSpace(line) == "":
+		return
+	case mdLinkDef.MatchString(line):
+		imp.flushBullet()
+		imp.parseLinkDef(line)
+	case strings.HasPrefix(line, "### "):
+		imp.flushBullet()
+		imp.startCategory(line)
+	case strings.HasPrefix(line, "## "):
+		imp.flushBullet()
+		imp.startVersion(line)
+	case strings.HasPrefix(line, "# "):
+		imp.flushBullet()
+	case mdBullet.MatchString(line):
+		imp.flushBullet()
+		imp.bullet = []string{mdBullet.FindStringSubmatch(line)[1]}
+		imp.bulletLine = imp.line
+	case imp.bullet != nil && (line[0] == ' ' || line[0] == '\t'):
+		text := mdNestedBullet.ReplaceAllString(line, "")
+		imp.bullet = append(imp.bullet, strings.TrimSpace(text))
+	default:
+		imp.flushBullet()
+		imp.parseText(line)
+	}
+}
+
+// parseText handles free text: the preamble may name the project, anything
+// inside a version is reported as unmapped.
+func (imp *mdImporter) parseText(line string) {
+	if imp.version == nil {
+		if m := mdProject.FindStringSubmatch(line); m != nil {
+			imp.result.Changelog.Project = m[1]
+		}
+		return
+	}
+	imp.warn(imp.line, "unrecognized content skipped: %q", line)
+}
+
+func (imp *mdImporter) parseLinkDef(line string) {
+	m := mdLinkDef.FindStringSubmatch(line)
+	if imp.result.RepoURL != "" {
+		return
+	}
+	if u := mdCompareURL.FindStringSubmatch(m[2]); u != nil {
+		imp.result.RepoURL = u[1]
+	}
+}
+
+func (imp *mdImporter) startVersion(line string) {
+	imp.finishVersion()
+	imp.category, imp.skipCat = "", false
+
+	m := mdVersionHeading.FindStringSubmatch(line)
+	if m == nil {
+		imp.warn(imp.line, "unrecognized heading skipped: %q", line)
+		return
+	}
+	name := m[1]
+	if strings.EqualFold(name, "unreleased") {
+		imp.version, imp.headerLine = &Version{Version: "unreleased"}, imp.line
+		return
+	}
+	if len(name) > 1 && (name[0] == 'v' || name[0] == 'V') && name[1] >= '0' && name[1] <= '9' {
+		name = name[1:]
+	}
+	imp.version, imp.headerLine = &Version{Version: name}, imp.line
+	imp.version.Date = imp.parseDate(m[2])
+}
+
+// parseDate extracts a YYYY-MM-DD date from the remainder of a version heading.
+func (imp *mdImporter) parseDate(rest string) string {
+	rest = strings.TrimSpace(rest)
+	if i := strings.Index(rest, "["); i > 0 {
+		imp.warn(imp.line, "heading suffix %q dropped", rest[i:])
+		rest = strings.TrimSpace(rest[:i])
+	}
+	rest = strings.Trim(rest, "()")
+	for _, layout := range importDateLayouts {
+		if t, err := time.Parse(layout, rest); err == nil {
+			return t.Format("2006-01-02")
+		}
+	}
+	return ""
+}
+
+func (imp *mdImporter) startCategory(line string) {
+	imp.category, imp.skipCat = "", false
+	if imp.version == nil {
+		imp.warn(imp.line, "category outside a version skipped: %q", line)
+		return
+	}
+	name := strings.ToLower(mdCategory.FindStringSubmatch(line)[1])
+	if alias, ok := categoryAliases[name]; ok {
+		name = alias
+	}
+	imp.category = name
+	if imp.allowed != nil && !imp.allowed[name] {
+		imp.skipCat = true
+		imp.warn(imp.line, "unknown category %q skipped", name)
+	}
+}
+
+func (imp *mdImporter) flushBullet() {
+	if imp.bullet == nil {
+		return
+	}
+	text := strings.Join(imp.bullet, " ")
+	line := imp.bulletLine
+	imp.bullet = nil
+
+	switch {
+	case imp.skipCat:
+		return
+	case imp.version == nil || imp.category == "":
+		imp.warn(line, "entry outside a category skipped: %q", text)
+		return
+	}
+	text, meta := parseEntryMarkdown(text)
+	imp.version.Public.AppendWithMeta(imp.category, text, meta)
+}
+
+// finishVersion adds the current version to the changelog if it is valid.
+func (imp *mdImporter) finishVersion() {
+	v := imp.version
+	imp.version = nil
+	if v == nil {
+		return
+	}
+	c := imp.result.Changelog
+	switch {
+	case !v.IsUnreleased() && v.Date == "":
+		imp.warn(imp.headerLine, "version %s has no date, skipped", v.Version)
+	case !v.IsUnreleased() && v.IsEmpty():
+		imp.warn(imp.headerLine, "version %s has no entries, skipped", v.Version)
+	default:
+		if _, err := c.GetVersion(v.Version); err == nil {
+			imp.warn(imp.headerLine, "duplicate version %s skipped", v.Version)
+			return
+		}
+		c.Versions = append(c.Versions, *v)
+	}
+}
+
+// pullRequestPaths are the pull request URL segments of the supported forges.
+var pullRequestPaths = []string{"/pull/", "/pulls/", "/merge_requests/", "/pull-requests/", "/pullrequest/"}
+
+func isPullRequestURL(url string) bool {
+	for _, p := range pullRequestPaths {
+		if strings.Contains(url, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseEntryMarkdown reverses formatEntryMarkdown: it strips the BREAKING and
+// scope prefixes and a trailing group of linked PR/issue references.
+func parseEntryMarkdown(text string) (string, EntryMeta) {
+	var meta EntryMeta
+	if loc := mdBreaking.FindStringIndex(text); loc != nil {
+		meta.Breaking = true
+		text = text[loc[1]:]
+	}
+	if m := mdScope.FindStringSubmatch(text); m != nil {
+		meta.Scope = m[1]
+		text = text[len(m[0]):]
+	}
+	if m := mdRefs.FindStringSubmatchIndex(text); m != nil {
+		for _, ref := range mdRefLink.FindAllStringSubmatch(text[m[2]:m[3]], -1) {
+			num, _ := strconv.Atoi(ref[1])
+			if isPullRequestURL(ref[2]) {
+				meta.PR = num
+			} else {
+				meta.Issues = append(meta.Issues, ref[1])
+			}
+		}
+		text = text[:m[0]]
+	}
+	return text, meta
+}
@@ -0,0 +1,120 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestImportMarkdown_RoundTrip(t *testing.T) {
+	original, err := Load("testdata/valid.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	original.Versions[1].Public.AppendWithMeta("changed", "Drop v1", EntryMeta{PR: 9, Scope: "api", Breaking: true})
+	md, err := RenderMarkdownString(original, RenderOptions{Config: &Config{RepoURL: "https://github.com/o/r"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := ImportMarkdown(strings.NewReader(md))
+	if err != nil {
+		t.Fatalf("ImportMarkdown: %v", err)
+	}
+	if len(res.Warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", res.Warnings)
+	}
+	if res.RepoURL != "https://github.com/o/r" {
+		t.Errorf("RepoURL = %q", res.RepoURL)
+	}
+
+	got, err := RenderMarkdownString(res.Changelog, RenderOptions{Config: &Config{RepoURL: res.RepoURL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != md {
+		t.Errorf("round trip mismatch:\n--- got ---\n%s\n--- want ---\n%s", got, md)
+	}
+}
+
+func TestImportMarkdown_Warnings(t *testing.T) {
+	input := `# Changelog
+
+## [Unreleased]
+
+### Features
+
+- Multi-line
+  entry text
+
+## [1.0.0] - 2024-01-01 [YANKED]
+
+### Notes
+
+- Dropped
+
+Free prose.
+
+### Fixed
+
+* Bug
+
+## [0.9.0]
+
+### Added
+
+- No date
+`
+	res, err := ImportMarkdown(strings.NewReader(input), ImportOptions{Project: "demo"})
+	if err != nil {
+		t.Fatalf("ImportMarkdown: %v", err)
+	}
+	c := res.Changelog
+	if c.Project != "demo" || len(c.Versions) != 2 {
+		t.Fatalf("project = %q, versions = %v", c.Project, c.ListVersions())
+	}
+	if got := c.Versions[0].Public.Get("added"); len(got) != 1 || got[0] != "Multi-line entry text" {
+		t.Errorf("unreleased added = %v", got)
+	}
+	if got := c.Versions[1].Public.Get("fixed"); len(got) != 1 || got[0] != "Bug" {
+		t.Errorf("1.0.0 fixed = %v", got)
+	}
+
+	wantLines := []int{10, 12, 16, 22}
+	if len(res.Warnings) != len(wantLines) {
+		t.Fatalf("warnings = %v", res.Warnings)
+	}
+	for i, w := range res.Warnings {
+		if w.Line != wantLines[i] {
+			t.Errorf("warning %d line = %d, want %d (%s)", i, w.Line, wantLines[i], w.Message)
+		}
+	}
+}
+
+func TestImportMarkdown_HeadingVariants(t *testing.T) {
+	tests := map[string]struct {
+		heading string
+		version string
+		date    string
+	}{
+		"keep a changelog": {"## [1.2.0] - 2024-05-01", "1.2.0", "2024-05-01"},
+		"v prefix":         {"## v1.2.0 - 2024-05-01", "1.2.0", "2024-05-01"},
+		"parenthesized":    {"## 1.2.0 (2024-05-01)", "1.2.0", "2024-05-01"},
+		"long date":        {"## [1.2.0] - May 1, 2024", "1.2.0", "2024-05-01"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			input := tt.heading + "\n\n### Added\n\n- Thing\n"
+			res, err := ImportMarkdown(strings.NewReader(input), ImportOptions{Project: "p"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(res.Changelog.Versions) != 1 {
+				t.Fatalf("versions = %v, warnings = %v", res.Changelog.ListVersions(), res.Warnings)
+			}
+			v := res.Changelog.Versions[0]
+			if v.Version != tt.version || v.Date != tt.date {
+				t.Errorf("got %s / %s, want %s / %s", v.Version, v.Date, tt.version, tt.date)
+			}
+		})
+	}
+}
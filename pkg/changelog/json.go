@@ -0,0 +1,149 @@
+package changelog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// JSONSchemaVersion is the version of the JSON document format. It is bumped
+// only for incompatible changes; new optional fields keep the same version.
+const JSONSchemaVersion = 1
+
+// JSONDocument is the stable JSON representation of a changelog.
+// Versions and categories keep their file order.
+type JSONDocument struct {
+	SchemaVersion int           `json:"schema_version"`
+	Project       string        `json:"project"`
+	Versions      []JSONVersion `json:"versions"`
+}
+
+// JSONVersion is a single version in a JSONDocument.
+type JSONVersion struct {
+	Version    string         `json:"version"`
+	Date       string         `json:"date,omitempty"`
+	Unreleased bool           `json:"unreleased,omitempty"`
+	Public     []JSONCategory `json:"public"`
+	Internal   []JSONCategory `json:"internal,omitempty"`
+}
+
+// JSONCategory is a named list of entries.
+type JSONCategory struct {
+	Name    string      `json:"name"`
+	Entries []JSONEntry `json:"entries"`
+}
+
+// JSONEntry is an entry with its optional metadata.
+type JSONEntry struct {
+	Text string `json:"text"`
+	EntryMeta
+}
+
+// NewJSONDocument converts a changelog to its JSON representation.
+// Internal entries are included only with IncludeInternal.
+func NewJSONDocument(c *Changelog, opts ...QueryOptions) JSONDocument {
+	doc := JSONDocument{SchemaVersion: JSONSchemaVersion, Project: c.Project, Versions: []JSONVersion{}}
+	for i := range c.Versions {
+		doc.Versions = append(doc.Versions, NewJSONVersion(&c.Versions[i], opts...))
+	}
+	return doc
+}
+
+// NewJSONVersion converts a single version to its JSON representation.
+func NewJSONVersion(v *Version, opts ...QueryOptions) JSONVersion {
+	var opt QueryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	jv := JSONVersion{
+		Version:    v.Version,
+		Date:       v.Date,
+		Unreleased: v.IsUnreleased(),
+		Public:     jsonCategories(v.Public),
+	}
+	if opt.IncludeInternal && !v.Internal.IsEmpty() {
+		jv.Internal = jsonCategories(v.Internal)
+	}
+	return jv
+}
+
+func jsonCategories(changes Changes) []JSONCategory {
+	cats := []JSONCategory{}
+	for _, cat := range changes.Categories {
+		if len(cat.Entries) == 0 {
+			continue
+		}
+		jc := JSONCategory{Name: cat.Name}
+		for i, text := range cat.Entries {
+			jc.Entries = append(jc.Entries, JSONEntry{Text: text, EntryMeta: cat.MetaAt(i)})
+		}
+		cats = append(cats, jc)
+	}
+	return cats
+}
+
+// MarshalJSON encodes a changelog as an indented JSON document.
+// Pass IncludeInternal to produce a document ImportJSON can restore fully.
+func MarshalJSON(c *Changelog, opts ...QueryOptions) ([]byte, error) {
+	data, err := json.MarshalIndent(NewJSONDocument(c, opts...), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// Changelog converts the document back into a Changelog.
+func (d JSONDocument) Changelog() (*Changelog, error) {
+	if d.SchemaVersion < 1 || d.SchemaVersion > JSONSchemaVersion {
+		return nil, fmt.Errorf("unsupported schema_version %d (supported: 1-%d)", d.SchemaVersion, JSONSchemaVersion)
+	}
+	c := &Changelog{Project: d.Project}
+	for _, jv := range d.Versions {
+		if _, err := c.GetVersion(jv.Version); err == nil {
+			return nil, fmt.Errorf("duplicate version %q", jv.Version)
+		}
+		v := Version{Version: jv.Version, Date: jv.Date}
+		fillChanges(&v.Public, jv.Public)
+		fillChanges(&v.Internal, jv.Internal)
+		c.Versions = append(c.Versions, v)
+	}
+	return c, nil
+}
+
+func fillChanges(changes *Changes, cats []JSONCategory) {
+	for _, cat := range cats {
+		for _, e := range cat.Entries {
+			changes.AppendWithMeta(cat.Name, e.Text, e.EntryMeta)
+		}
+	}
+}
+
+// ImportJSON reads a document produced by MarshalJSON. Unknown fields are
+// rejected, and the result must pass Validate — except that Project may be
+// empty when neither the document nor opts name one.
+func ImportJSON(r io.Reader, opts ...ImportOptions) (*Changelog, error) {
+	var opt ImportOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var doc JSONDocument
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decoding JSON: %w", err)
+	}
+
+	c, err := doc.Changelog()
+	if err != nil {
+		return nil, err
+	}
+	if opt.Project != "" {
+		c.Project = opt.Project
+	}
+	for _, e := range Validate(c, opt.Config) {
+		if e.Field != "project" {
+			return nil, fmt.Errorf("imported changelog is invalid: %w", e)
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,90 @@
+package changelog
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMarshalJSON_RoundTrip(t *testing.T) {
+	original, err := Load("testdata/valid.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	original.Versions[1].Public.AppendWithMeta("changed", "Drop v1", EntryMeta{PR: 9, Scope: "api", Breaking: true})
+	original.Versions[2].Internal.Append("changed", "Refactor")
+
+	data, err := MarshalJSON(original, QueryOptions{IncludeInternal: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ImportJSON(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ImportJSON: %v", err)
+	}
+
+	want, _ := Marshal(&Changelog{Project: original.Project, Versions: original.Versions})
+	have, _ := Marshal(got)
+	if string(have) != string(want) {
+		t.Errorf("round trip mismatch:\n--- got ---\n%s\n--- want ---\n%s", have, want)
+	}
+}
+
+func TestNewJSONDocument(t *testing.T) {
+	c := &Changelog{Project: "demo", Versions: []Version{{Version: "unreleased"}, {Version: "1.0.0", Date: "2024-01-01"}}}
+	c.Versions[1].Public.AppendWithMeta("added", "Login", EntryMeta{PR: 3})
+	c.Versions[1].Internal.Append("changed", "Refactor")
+
+	doc := NewJSONDocument(c)
+	if doc.SchemaVersion != JSONSchemaVersion || doc.Project != "demo" || len(doc.Versions) != 2 {
+		t.Fatalf("unexpected document: %+v", doc)
+	}
+	if !doc.Versions[0].Unreleased || doc.Versions[0].Public == nil {
+		t.Errorf("unreleased = %+v, want flagged with empty public list", doc.Versions[0])
+	}
+	want := []JSONCategory{{Name: "added", Entries: []JSONEntry{{Text: "Login", EntryMeta: EntryMeta{PR: 3}}}}}
+	if !reflect.DeepEqual(doc.Versions[1].Public, want) {
+		t.Errorf("public = %+v, want %+v", doc.Versions[1].Public, want)
+	}
+	if doc.Versions[1].Internal != nil {
+		t.Errorf("internal included without IncludeInternal: %+v", doc.Versions[1].Internal)
+	}
+
+	data, _ := MarshalJSON(c)
+	if !strings.Contains(string(data), `"text": "Login",`+"\n"+`              "pr": 3`) {
+		t.Errorf("unexpected encoding:\n%s", data)
+	}
+}
+
+func TestImportJSON_Errors(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		want  string
+	}{
+		"malformed":       {`{`, "decoding JSON"},
+		"unknown field":   {`{"schema_version": 1, "extra": true}`, "unknown field"},
+		"missing schema":  {`{"project": "x", "versions": []}`, "unsupported schema_version 0"},
+		"future schema":   {`{"schema_version": 99}`, "unsupported schema_version 99"},
+		"duplicate":       {`{"schema_version": 1, "versions": [{"version": "unreleased"}, {"version": "unreleased"}]}`, "duplicate version"},
+		"invalid version": {`{"schema_version": 1, "versions": [{"version": "1.0.0", "public": []}]}`, "imported changelog is invalid"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ImportJSON(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportJSON_ProjectOverride(t *testing.T) {
+	c, err := ImportJSON(strings.NewReader(`{"schema_version": 1, "versions": []}`), ImportOptions{Project: "named"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Project != "named" {
+		t.Errorf("project = %q, want named", c.Project)
+	}
+}
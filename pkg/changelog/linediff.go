@@ -0,0 +1,141 @@
+package changelog
+
+// lineOp is a single step of a line-level edit script.
+type lineOp struct {
+	Kind byte // '=' keep, '-' delete from a, '+' insert from b
+	A    int  // index into a, or -1 for inserts
+	B    int  // index into b, or -1 for deletes
+}
+
+// maxDiffEdits bounds the Myers search. Inputs that need more edits than this
+// are treated as fully replaced, which keeps memory predictable on unrelated files.
+const maxDiffEdits = 2000
+
+// diffLines returns a minimal edit script turning a into b.
+func diffLines(a, b []string) []lineOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]lineOp, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, lineOp{Kind: '=', A: i, B: i})
+	}
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	mid, ok := myers(midA, midB)
+	if !ok {
+		mid = replaceAll(len(midA), len(midB))
+	}
+	for _, op := range mid {
+		if op.A >= 0 {
+			op.A += prefix
+		}
+		if op.B >= 0 {
+			op.B += prefix
+		}
+		ops = append(ops, op)
+	}
+
+	for i := 0; i < suffix; i++ {
+		ops = append(ops, lineOp{Kind: '=', A: len(a) - suffix + i, B: len(b) - suffix + i})
+	}
+	return ops
+}
+
+// myers runs the Myers O(ND) shortest edit search, returning false if the
+// edit distance exceeds maxDiffEdits.
+func myers(a, b []string) ([]lineOp, bool) {
+	n, m := len(a), len(b)
+	total := n + m
+	off := total + 1
+	v := make([]int, 2*total+3)
+	var trace [][]int
+
+	for d := 0; d <= total; d++ {
+		if d > maxDiffEdits {
+			return nil, false
+		}
+		snap := make([]int, 2*d+1)
+		copy(snap, v[off-d:off+d+1])
+		trace = append(trace, snap)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				x = v[off+k+1]
+			} else {
+				x = v[off+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				return backtrackMyers(trace, n, m), true
+			}
+		}
+	}
+	return backtrackMyers(trace, n, m), true
+}
+
+// backtrackMyers walks the saved search frontiers backwards to recover the edit script.
+func backtrackMyers(trace [][]int, n, m int) []lineOp {
+	var rev []lineOp
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		snap := trace[d]
+		get := func(k int) int { return snap[k+d] }
+		k := x - y
+
+		prevK := k - 1
+		if k == -d || (k != d && get(k-1) < get(k+1)) {
+			prevK = k + 1
+		}
+		prevX := get(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			rev = append(rev, lineOp{Kind: '=', A: x - 1, B: y - 1})
+			x--
+			y--
+		}
+		if x == prevX {
+			rev = append(rev, lineOp{Kind: '+', A: -1, B: y - 1})
+		} else {
+			rev = append(rev, lineOp{Kind: '-', A: x - 1, B: -1})
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		rev = append(rev, lineOp{Kind: '=', A: x - 1, B: y - 1})
+		x--
+		y--
+	}
+
+	ops := make([]lineOp, len(rev))
+	for i, op := range rev {
+		ops[len(rev)-1-i] = op
+	}
+	return ops
+}
+
+// replaceAll is the fallback edit script: delete every line of a, insert every line of b.
+func replaceAll(n, m int) []lineOp {
+	ops := make([]lineOp, 0, n+m)
+	for i := 0; i < n; i++ {
+		ops = append(ops, lineOp{Kind: '-', A: i, B: -1})
+	}
+	for j := 0; j < m; j++ {
+		ops = append(ops, lineOp{Kind: '+', A: -1, B: j})
+	}
+	return ops
+}
@@ -0,0 +1,67 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+)
+
+// applyOps rebuilds b from a using an edit script, failing on inconsistencies.
+func applyOps(t *testing.T, a, b []string, ops []lineOp) []string {
+	t.Helper()
+	var out []string
+	for _, op := range ops {
+		switch op.Kind {
+		case '=':
+			if a[op.A] != b[op.B] {
+				t.Fatalf("keep op mismatch: %q vs %q", a[op.A], b[op.B])
+			}
+			out = append(out, a[op.A])
+		case '+':
+			out = append(out, b[op.B])
+		}
+	}
+	return out
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := map[string]struct {
+		a, b  string
+		edits int
+	}{
+		"identical": {"a b c", "a b c", 0},
+		"insert":    {"a c", "a b c", 1},
+		"delete":    {"a b c", "a c", 1},
+		"replace":   {"a b c", "a x c", 2},
+		"empty a":   {"", "a b", 2},
+		"empty b":   {"a b", "", 2},
+		"reorder":   {"a b c d", "b a c d", 2},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			a, b := strings.Fields(tt.a), strings.Fields(tt.b)
+			ops := diffLines(a, b)
+			got := applyOps(t, a, b, ops)
+			if strings.Join(got, " ") != strings.Join(b, " ") {
+				t.Errorf("applied = %v, want %v", got, b)
+			}
+			edits := 0
+			for _, op := range ops {
+				if op.Kind != '=' {
+					edits++
+				}
+			}
+			if edits != tt.edits {
+				t.Errorf("edits = %d, want %d", edits, tt.edits)
+			}
+		})
+	}
+}
+
+func TestRestoreLayout_KeepsBlankLinesAndCommentSpacing(t *testing.T) {
+	raw := "a: 1   # note\n\nb:\n  - x\n"
+	out := "a: 1 # note\nb:\n  - x\n  - y\n"
+	want := "a: 1   # note\n\nb:\n  - x\n  - y\n"
+	if got := string(restoreLayout([]byte(raw), []byte(out))); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,566 @@
+package changelog
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Lint defaults, used when the LintConfig field is zero.
+const (
+	DefaultLintMaxLength  = 200
+	DefaultLintMinLength  = 10
+	DefaultLintSimilarity = 0.9
+)
+
+// Trailing punctuation policies for LintConfig.Punctuation.
+const (
+	PunctuationNone   = "none"
+	PunctuationPeriod = "period"
+	PunctuationAny    = "any"
+)
+
+// Tense policies for LintConfig.Tense.
+const (
+	TensePast       = "past"
+	TenseImperative = "imperative"
+	TenseAny        = "any"
+)
+
+// LintConfig configures `chlog lint`. Zero values select the defaults.
+type LintConfig struct {
+	// Disable lists rule IDs to skip, e.g. "tense" or "min-length".
+	Disable   []string `yaml:"disable,omitempty"`
+	MaxLength int      `yaml:"max_length,omitempty"`
+	MinLength int      `yaml:"min_length,omitempty"`
+	// Punctuation is "none" (default: no trailing period), "period" (end
+	// with ".", "!" or "?") or "any".
+	Punctuation string `yaml:"punctuation,omitempty"`
+	// Tense is "past" ("Added X"), "imperative" ("Add X") or "any" (default).
+	Tense string `yaml:"tense,omitempty"`
+	// Banned lists regular expressions entries must not match.
+	Banned []string `yaml:"banned,omitempty"`
+	// Similarity is the ratio, between 0 and 1, above which two entries of
+	// a version count as near-duplicates (default 0.9).
+	Similarity float64 `yaml:"similarity,omitempty"`
+}
+
+// Validate checks rule IDs, policies and banned patterns.
+func (l LintConfig) Validate() error {
+	for _, id := range l.Disable {
+		if !slices.Contains(LintRules(), id) {
+			return fmt.Errorf("lint.disable: unknown rule %q (valid: %s)", id, strings.Join(LintRules(), ", "))
+		}
+	}
+	if !slices.Contains([]string{"", PunctuationNone, PunctuationPeriod, PunctuationAny}, l.Punctuation) {
+		return fmt.Errorf("lint.punctuation: unknown policy %q (valid: none, period, any)", l.Punctuation)
+	}
+	if !slices.Contains([]string{"", TensePast, TenseImperative, TenseAny}, l.Tense) {
+		return fmt.Errorf("lint.tense: unknown policy %q (valid: past, imperative, any)", l.Tense)
+	}
+	if l.MaxLength < 0 || l.MinLength < 0 || l.Similarity < 0 || l.Similarity > 1 {
+		return fmt.Errorf("lint: lengths must not be negative and similarity must be between 0 and 1")
+	}
+	_, err := compileBanned(l.Banned)
+	return err
+}
+
+func compileBanned(patterns []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, len(patterns))
+	for i, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("lint.banned: %w", err)
+		}
+		res[i] = re
+	}
+	return res, nil
+}
+
+// LintIssue is one finding of Lint, located at an entry.
+type LintIssue struct {
+	Rule     string `json:"rule"`
+	Version  string `json:"version"`
+	Category string `json:"category"`
+	Internal bool   `json:"internal,omitempty"`
+	// Index is the position in the category, within File when that is set.
+	Index   int    `json:"index"`
+	Text    string `json:"text"`
+	Message string `json:"message"`
+	// Fix is the corrected entry text when the rule can fix it.
+	Fix string `json:"fix,omitempty"`
+	// File is set when the entry is in a fragment rather than the changelog.
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+}
+
+// Field names the entry, e.g. "unreleased.internal.added[2]". An entry in a
+// fragment is named as in the fragment, e.g. "internal.added[0]".
+func (i LintIssue) Field() string {
+	if i.File != "" {
+		return entryField("", i.Category, i.Internal, i.Index)
+	}
+	return entryField(i.Version, i.Category, i.Internal, i.Index)
+}
+
+func entryField(version, category string, internal bool, index int) string {
+	if internal {
+		category = "internal." + category
+	}
+	if version == "" {
+		return fmt.Sprintf("%s[%d]", category, index)
+	}
+	return fmt.Sprintf("%s.%s[%d]", version, category, index)
+}
+
+// Diagnostic converts the issue for WriteReport, located in file unless the
+// entry is in a fragment.
+func (i LintIssue) Diagnostic(file string) Diagnostic {
+	return Diagnostic{Rule: i.Rule, File: cmp.Or(i.File, file), Line: i.Line, Column: i.Column, Field: i.Field(), Message: i.Message}
+}
+
+// lintEntry is an entry under lint, with the YAML node it came from and,
+// for a fragment entry, the fragment's path and the entry's index there.
+type lintEntry struct {
+	version  string
+	cat      *CategoryEntry
+	internal bool
+	index    int
+	node     *yaml.Node
+	file     string
+	srcIndex int
+}
+
+func (e lintEntry) text() string { return e.cat.Entries[e.index] }
+
+// fileIndex is the index of the entry in the file it is located in.
+func (e lintEntry) fileIndex() int {
+	if e.file != "" {
+		return e.srcIndex
+	}
+	return e.index
+}
+
+func (e lintEntry) field() string {
+	if e.file != "" {
+		return entryField("", e.cat.Name, e.internal, e.srcIndex)
+	}
+	return entryField(e.version, e.cat.Name, e.internal, e.index)
+}
+
+// ref names e for a message about other, adding e's fragment when they are
+// in different files.
+func (e lintEntry) ref(other lintEntry) string {
+	if e.file != "" && e.file != other.file {
+		return e.field() + " in " + e.file
+	}
+	return e.field()
+}
+
+func (e lintEntry) issue(rule, msg, fix string) LintIssue {
+	i := LintIssue{Rule: rule, Version: e.version, Category: e.cat.Name, Internal: e.internal, Index: e.fileIndex(), Text: e.text(), Message: msg, Fix: fix, File: e.file}
+	if e.node != nil {
+		i.Line, i.Column = e.node.Line, e.node.Column
+	}
+	return i
+}
+
+// lintEntries lists the entries of a version of c, public first. Entries
+// overlaid from fragments are located in their fragment.
+func (c *Changelog) lintEntries(v *Version) []lintEntry {
+	var out []lintEntry
+	for _, side := range []struct {
+		changes  *Changes
+		node     *yaml.Node
+		internal bool
+	}{{&v.Public, v.node, false}, {&v.Internal, mappingValue(v.node, "internal"), true}} {
+		for k := range side.changes.Categories {
+			cat := &side.changes.Categories[k]
+			items := mappingValue(side.node, cat.Name)
+			for i, text := range cat.Entries {
+				e := lintEntry{version: v.Version, cat: cat, internal: side.internal, index: i, node: sequenceItem(items, i)}
+				if src, ok := c.fragmentSource(side.internal, cat.Name, text); ok && v.IsUnreleased() && e.node == nil {
+					e.node, e.file, e.srcIndex = src.node, src.path, src.index
+				}
+				out = append(out, e)
+			}
+		}
+	}
+	return out
+}
+
+// entryRule checks one entry's text, returning a message when it fails and
+// the fixed text when the rule can fix it.
+type entryRule struct {
+	id    string
+	check func(text string) (msg, fix string)
+}
+
+// linter holds the rules enabled by a LintConfig.
+type linter struct {
+	cfg    LintConfig
+	rules  []entryRule
+	banned []*regexp.Regexp
+}
+
+func newLinter(cfg LintConfig) (*linter, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	l := &linter{cfg: cfg}
+	l.banned, _ = compileBanned(cfg.Banned)
+	// Fixable rules come first, in the order FixLint applies them.
+	for _, r := range []entryRule{
+		{RuleWhitespace, checkWhitespace},
+		{RuleCommitPrefix, checkCommitPrefix},
+		{RuleRepeatedWord, checkRepeatedWord},
+		{RuleTense, l.checkTense},
+		{RuleCapitalization, checkCapitalization},
+		{RuleTrailingPunctuation, l.checkPunctuation},
+		{RuleMaxLength, l.checkMaxLength},
+		{RuleMinLength, l.checkMinLength},
+		{RuleBannedPattern, l.checkBanned},
+		{RuleCommitHash, checkCommitHash},
+	} {
+		if l.enabled(r.id) {
+			l.rules = append(l.rules, r)
+		}
+	}
+	return l, nil
+}
+
+func (l *linter) enabled(id string) bool {
+	return !slices.Contains(l.cfg.Disable, id)
+}
+
+// LintRules returns the IDs of all lint rules.
+func LintRules() []string {
+	return []string{
+		RuleWhitespace, RuleCommitPrefix, RuleRepeatedWord, RuleTense, RuleCapitalization, RuleTrailingPunctuation,
+		RuleMaxLength, RuleMinLength, RuleBannedPattern, RuleCommitHash, RuleDuplicateEntry,
+	}
+}
+
+// Lint checks entry style on top of Validate: length, capitalization,
+// trailing punctuation, tense, banned patterns, commit message wording and
+// hashes, and duplicate or near-duplicate entries within a version. Issues
+// of fixable rules carry the corrected text; FixLint applies them.
+func Lint(c *Changelog, cfg LintConfig) ([]LintIssue, error) {
+	l, err := newLinter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var issues []LintIssue
+	for i := range c.Versions {
+		entries := c.lintEntries(&c.Versions[i])
+		for _, e := range entries {
+			for _, r := range l.rules {
+				if msg, fix := r.check(e.text()); msg != "" {
+					issues = append(issues, e.issue(r.id, msg, fix))
+				}
+			}
+		}
+		if l.enabled(RuleDuplicateEntry) {
+			issues = append(issues, l.duplicates(entries)...)
+		}
+	}
+	return issues, nil
+}
+
+// FixLint rewrites entries in place with every fixable rule's correction and
+// returns the number of entries changed. Entries overlaid from fragments are
+// left alone, as Save would not write them; use FixLintFragment.
+func FixLint(c *Changelog, cfg LintConfig) (int, error) {
+	l, err := newLinter(cfg)
+	if err != nil {
+		return 0, err
+	}
+	fixed := 0
+	for i := range c.Versions {
+		for _, e := range c.lintEntries(&c.Versions[i]) {
+			if e.file != "" {
+				continue
+			}
+			text := e.text()
+			for _, r := range l.rules {
+				if msg, fix := r.check(text); msg != "" && fix != "" {
+					text = fix
+				}
+			}
+			if text != e.text() {
+				e.cat.Entries[e.index] = text
+				setEntryText(e.node, text)
+				fixed++
+			}
+		}
+	}
+	return fixed, nil
+}
+
+// FixLintFragment applies FixLint to the entries of a fragment; save it with
+// Fragment.Save when the returned count is not zero.
+func FixLintFragment(f *Fragment, cfg LintConfig) (int, error) {
+	c := &Changelog{Versions: []Version{{Version: "unreleased", Public: f.Public, Internal: f.Internal, node: f.node}}}
+	n, err := FixLint(c, cfg)
+	f.Public, f.Internal = c.Versions[0].Public, c.Versions[0].Internal
+	return n, err
+}
+
+// setEntryText updates the node an entry was decoded from, so that saving
+// reuses it and keeps its comments.
+func setEntryText(node *yaml.Node, text string) {
+	if node != nil && node.Kind == yaml.MappingNode {
+		node = mappingValue(node, "text")
+	}
+	if node != nil && node.Kind == yaml.ScalarNode {
+		node.Value = text
+	}
+}
+
+func checkWhitespace(text string) (string, string) {
+	fixed := strings.Join(strings.Fields(text), " ")
+	if fixed == text || fixed == "" {
+		return "", ""
+	}
+	return "leading, trailing or repeated whitespace", fixed
+}
+
+var commitPrefixRegex = regexp.MustCompile(`^(?i:feat|fix|chore|docs|refactor|perf|test|build|ci|style|revert)(\([^)]*\))?!?:\s*`)
+
+func checkCommitPrefix(text string) (string, string) {
+	m := commitPrefixRegex.FindString(text)
+	if m == "" || len(m) == len(text) {
+		return "", ""
+	}
+	return fmt.Sprintf("commit message prefix %q", strings.TrimSpace(m)), capitalize(text[len(m):])
+}
+
+var tokenRegex = regexp.MustCompile(`\S+`)
+
+// checkRepeatedWord flags a word written twice in a row, as in "Added
+// Added"; only tokens made of letters count, so code spans are left alone.
+func checkRepeatedWord(text string) (string, string) {
+	locs := tokenRegex.FindAllStringIndex(text, -1)
+	for i := 1; i < len(locs); i++ {
+		prev, cur := locs[i-1], locs[i]
+		word := text[cur[0]:cur[1]]
+		if strings.IndexFunc(word, func(r rune) bool { return !unicode.IsLetter(r) }) < 0 && strings.EqualFold(text[prev[0]:prev[1]], word) {
+			return fmt.Sprintf("repeated word %q", word), text[:prev[1]] + text[cur[1]:]
+		}
+	}
+	return "", ""
+}
+
+func checkCapitalization(text string) (string, string) {
+	first, _ := utf8.DecodeRuneInString(text)
+	word, _, _ := strings.Cut(text, " ")
+	// Words with capitals or digits inside, like iOS or v2, are left alone.
+	if !unicode.IsLower(first) || strings.IndexFunc(word, func(r rune) bool { return unicode.IsUpper(r) || unicode.IsDigit(r) }) >= 0 {
+		return "", ""
+	}
+	return "must start with a capital letter", capitalize(text)
+}
+
+func (l *linter) checkPunctuation(text string) (string, string) {
+	switch l.cfg.Punctuation {
+	case PunctuationAny:
+		return "", ""
+	case PunctuationPeriod:
+		if strings.HasSuffix(text, ".") || strings.HasSuffix(text, "!") || strings.HasSuffix(text, "?") || strings.HasSuffix(text, "`") {
+			return "", ""
+		}
+		return "must end with a period", text + "."
+	}
+	if !strings.HasSuffix(text, ".") || strings.HasSuffix(text, "..") || strings.HasSuffix(text, "etc.") {
+		return "", ""
+	}
+	return "must not end with a period", strings.TrimSuffix(text, ".")
+}
+
+func (l *linter) checkMaxLength(text string) (string, string) {
+	limit := cmp.Or(l.cfg.MaxLength, DefaultLintMaxLength)
+	if n := utf8.RuneCountInString(text); n > limit {
+		return fmt.Sprintf("%d characters, longer than %d", n, limit), ""
+	}
+	return "", ""
+}
+
+func (l *linter) checkMinLength(text string) (string, string) {
+	limit := cmp.Or(l.cfg.MinLength, DefaultLintMinLength)
+	if n := utf8.RuneCountInString(text); n < limit {
+		return fmt.Sprintf("%d characters, shorter than %d; describe the change for users", n, limit), ""
+	}
+	return "", ""
+}
+
+func (l *linter) checkBanned(text string) (string, string) {
+	for _, re := range l.banned {
+		if re.MatchString(text) {
+			return fmt.Sprintf("matches banned pattern %q", re.String()), ""
+		}
+	}
+	return "", ""
+}
+
+var commitHashRegex = regexp.MustCompile(`\b[0-9a-f]{7,40}\b`)
+
+func checkCommitHash(text string) (string, string) {
+	for _, m := range commitHashRegex.FindAllString(text, -1) {
+		// A hash mixes digits and letters; "decade" or "1234567" alone are not.
+		if strings.ContainsAny(m, "0123456789") && strings.ContainsAny(m, "abcdef") {
+			return fmt.Sprintf("raw commit hash %q; link the pull request with pr instead", m), ""
+		}
+	}
+	return "", ""
+}
+
+func capitalize(s string) string {
+	r, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[n:]
+}
+
+// pastTense maps imperative verbs to their past tense. Verbs whose forms
+// are equal, like "set", are left out as they say nothing about tense.
+var pastTense = map[string]string{
+	"add": "added", "allow": "allowed", "avoid": "avoided", "build": "built", "bump": "bumped",
+	"change": "changed", "clarify": "clarified", "correct": "corrected", "create": "created",
+	"delete": "deleted", "deprecate": "deprecated", "detect": "detected", "disable": "disabled",
+	"document": "documented", "drop": "dropped", "emit": "emitted", "enable": "enabled",
+	"expose": "exposed", "extend": "extended", "fix": "fixed", "generate": "generated",
+	"handle": "handled", "ignore": "ignored", "implement": "implemented", "improve": "improved",
+	"include": "included", "introduce": "introduced", "keep": "kept", "make": "made",
+	"merge": "merged", "migrate": "migrated", "move": "moved", "optimize": "optimized",
+	"prevent": "prevented", "print": "printed", "reduce": "reduced", "refactor": "refactored",
+	"reject": "rejected", "remove": "removed", "rename": "renamed", "render": "rendered",
+	"replace": "replaced", "report": "reported", "require": "required", "resolve": "resolved",
+	"restore": "restored", "return": "returned", "simplify": "simplified", "skip": "skipped",
+	"sort": "sorted", "stop": "stopped", "support": "supported", "update": "updated",
+	"upgrade": "upgraded", "use": "used", "validate": "validated", "write": "wrote",
+}
+
+var imperativeOf = func() map[string]string {
+	m := make(map[string]string, len(pastTense))
+	for base, past := range pastTense {
+		m[past] = base
+	}
+	return m
+}()
+
+// nounFollowers mark the first word as a noun: "Support for X", "Fix to Y".
+var nounFollowers = map[string]bool{"for": true, "of": true, "to": true, "in": true, "on": true, "from": true}
+
+// checkTense flags an entry whose first word is a verb in the wrong tense,
+// including the third person ("Adds"), and fixes it from pastTense.
+func (l *linter) checkTense(text string) (string, string) {
+	if l.cfg.Tense == "" || l.cfg.Tense == TenseAny {
+		return "", ""
+	}
+	word, rest, _ := strings.Cut(text, " ")
+	if next, _, _ := strings.Cut(rest, " "); nounFollowers[strings.ToLower(next)] {
+		return "", ""
+	}
+	base, ok := verbBase(strings.ToLower(word))
+	if !ok {
+		return "", ""
+	}
+	want := base
+	if l.cfg.Tense == TensePast {
+		want = pastTense[base]
+	}
+	if strings.EqualFold(word, want) {
+		return "", ""
+	}
+	if first, _ := utf8.DecodeRuneInString(word); unicode.IsUpper(first) {
+		want = capitalize(want)
+	}
+	return fmt.Sprintf("use the %s tense: %q, not %q", l.cfg.Tense, want, word), want + text[len(word):]
+}
+
+// verbBase returns the imperative form of a known verb in any form.
+func verbBase(word string) (string, bool) {
+	if _, ok := pastTense[word]; ok {
+		return word, true
+	}
+	if base, ok := imperativeOf[word]; ok {
+		return base, true
+	}
+	for _, suffix := range []string{"s", "es"} {
+		if base := strings.TrimSuffix(word, suffix); base != word {
+			if _, ok := pastTense[base]; ok {
+				return base, true
+			}
+		}
+	}
+	if base := strings.TrimSuffix(word, "ies"); base != word {
+		if _, ok := pastTense[base+"y"]; ok {
+			return base + "y", true
+		}
+	}
+	return "", false
+}
+
+// duplicates flags entries equal or near-equal to an earlier entry of the
+// same version, comparing case- and punctuation-insensitively.
+func (l *linter) duplicates(entries []lintEntry) []LintIssue {
+	threshold := cmp.Or(l.cfg.Similarity, DefaultLintSimilarity)
+	norm := make([][]rune, len(entries))
+	for i, e := range entries {
+		norm[i] = normalizeEntry(e.text())
+	}
+	var issues []LintIssue
+	for j := range entries {
+		for i := 0; i < j; i++ {
+			if len(norm[i]) == 0 || len(norm[j]) == 0 {
+				continue
+			}
+			if slices.Equal(norm[i], norm[j]) {
+				issues = append(issues, entries[j].issue(RuleDuplicateEntry, "duplicate of "+entries[i].ref(entries[j]), ""))
+				break
+			}
+			if sim := similarity(norm[i], norm[j]); sim >= threshold {
+				msg := fmt.Sprintf("near-duplicate of %s (%.0f%% similar)", entries[i].ref(entries[j]), sim*100)
+				issues = append(issues, entries[j].issue(RuleDuplicateEntry, msg, ""))
+				break
+			}
+		}
+	}
+	return issues
+}
+
+// normalizeEntry lowercases text and reduces it to words separated by
+// single spaces.
+func normalizeEntry(text string) []rune {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return []rune(strings.Join(words, " "))
+}
+
+// similarity returns 1 minus the Levenshtein distance of a and b over the
+// longer length.
+func similarity(a, b []rune) float64 {
+	longer := max(len(a), len(b))
+	prev, cur := make([]int, len(b)+1), make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return 1 - float64(prev[len(b)])/float64(longer)
+}
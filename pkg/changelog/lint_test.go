@@ -0,0 +1,174 @@
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLint_EntryRules(t *testing.T) {
+	tests := map[string]struct {
+		entry string
+		cfg   LintConfig
+		rule  string
+		fix   string
+	}{
+		"clean":                {entry: "Added the login page"},
+		"whitespace":           {entry: "Added  the login page", rule: RuleWhitespace, fix: "Added the login page"},
+		"commit prefix":        {entry: "Fix(auth): login page", rule: RuleCommitPrefix, fix: "Login page"},
+		"repeated word":        {entry: "Added Added dark mode", rule: RuleRepeatedWord, fix: "Added dark mode"},
+		"code not repeated":    {entry: "Renamed `x` `x` flags", cfg: LintConfig{Disable: []string{RuleTense}}},
+		"past tense":           {entry: "Fix crash on startup", cfg: LintConfig{Tense: TensePast}, rule: RuleTense, fix: "Fixed crash on startup"},
+		"third person":         {entry: "Adds a login page", cfg: LintConfig{Tense: TensePast}, rule: RuleTense, fix: "Added a login page"},
+		"imperative tense":     {entry: "Wrote the docs again", cfg: LintConfig{Tense: TenseImperative}, rule: RuleTense, fix: "Write the docs again"},
+		"noun is not a verb":   {entry: "Support for Windows paths", cfg: LintConfig{Tense: TensePast}},
+		"tense any":            {entry: "Fix crash on startup"},
+		"capitalization":       {entry: "login page for admins", rule: RuleCapitalization, fix: "Login page for admins"},
+		"mixed case word":      {entry: "iOS builds are signed"},
+		"trailing period":      {entry: "Added the login page.", rule: RuleTrailingPunctuation, fix: "Added the login page"},
+		"period required":      {entry: "Added the login page", cfg: LintConfig{Punctuation: PunctuationPeriod}, rule: RuleTrailingPunctuation, fix: "Added the login page."},
+		"too long":             {entry: "Added the login page", cfg: LintConfig{MaxLength: 10}, rule: RuleMaxLength},
+		"too short":            {entry: "Fixed bug", rule: RuleMinLength},
+		"banned":               {entry: "Added WIP login page", cfg: LintConfig{Banned: []string{`(?i)\bwip\b`}}, rule: RuleBannedPattern},
+		"commit hash":          {entry: "Reverted 3f2a9c1d for now", rule: RuleCommitHash},
+		"hex word is no hash":  {entry: "Decoded deadbeef values"},
+		"disabled rule":        {entry: "Fixed bug", cfg: LintConfig{Disable: []string{RuleMinLength}}},
+		"disabled fixable one": {entry: "login page for admins", cfg: LintConfig{Disable: []string{RuleCapitalization}}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &Changelog{Project: "demo", Versions: []Version{{Version: "unreleased"}}}
+			c.Versions[0].Public.Append("added", tt.entry)
+			issues, err := Lint(c, tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.rule == "" {
+				if len(issues) > 0 {
+					t.Errorf("unexpected issues: %+v", issues)
+				}
+				return
+			}
+			if len(issues) != 1 || issues[0].Rule != tt.rule || issues[0].Fix != tt.fix {
+				t.Errorf("got %+v, want one %s issue with fix %q", issues, tt.rule, tt.fix)
+			}
+		})
+	}
+}
+
+func TestLint_Duplicates(t *testing.T) {
+	c := &Changelog{Project: "demo", Versions: []Version{{Version: "unreleased"}, {Version: "1.0.0", Date: "2024-01-01"}}}
+	c.Versions[0].Public.Append("added", "Added the login page")
+	c.Versions[0].Public.Append("fixed", "Fixed crash when saving a file")
+	c.Versions[0].Internal.Append("added", "added the login page!")
+	c.Versions[0].Public.Append("fixed", "Fixed a crash when saving a file")
+	c.Versions[1].Public.Append("added", "Added the login page")
+
+	issues, err := Lint(c, LintConfig{Disable: []string{RuleCapitalization}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, i := range issues {
+		got = append(got, i.Field()+": "+i.Message)
+	}
+	want := []string{
+		"unreleased.fixed[1]: near-duplicate of unreleased.fixed[0] (94% similar)",
+		"unreleased.internal.added[0]: duplicate of unreleased.added[0]",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestFixLint(t *testing.T) {
+	yaml := `project: demo
+versions:
+  unreleased:
+    added:
+      # keep me
+      - "feat: add  the login page."
+      - text: fix crash
+        pr: 12
+      - Already fine entry
+`
+	c, err := LoadFromReader(strings.NewReader(yaml))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := FixLint(c, LintConfig{Tense: TensePast})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("fixed %d entries, want 2", n)
+	}
+	data, err := Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# keep me", `- "Added the login page"`, "- text: Fixed crash\n        pr: 12", "- Already fine entry"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("missing %q in\n%s", want, data)
+		}
+	}
+}
+
+func TestLint_FragmentEntries(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "CHANGELOG.yaml")
+	if err := os.WriteFile(path, []byte("project: demo\nversions:\n  unreleased:\n    added:\n      - Existing entry\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fragDir := filepath.Join(dir, "frags")
+	writeFragment(t, fragDir, "feat.yaml", "added:\n  - Fine entry here\n  - dark mode for the editor\n")
+	c, err := Load(path, &Config{Fragments: true, FragmentDir: fragDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := Lint(c, LintConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	frag := filepath.Join(fragDir, "feat.yaml")
+	if len(issues) != 1 || issues[0].Diagnostic(path).String() != frag+":3:5: added[1]: "+issues[0].Message {
+		t.Fatalf("issues = %+v, want one at %s:3:5 for added[1]", issues, frag)
+	}
+	if n, err := FixLint(c, LintConfig{}); err != nil || n != 0 {
+		t.Errorf("FixLint = %d, %v; want fragment entries left alone", n, err)
+	}
+
+	f, err := LoadFragment(frag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := FixLintFragment(f, LintConfig{}); err != nil || n != 1 {
+		t.Fatalf("FixLintFragment = %d, %v; want 1", n, err)
+	}
+	if got := f.Public.Get("added"); len(got) != 2 || got[1] != "Dark mode for the editor" {
+		t.Errorf("fragment entries = %v", got)
+	}
+}
+
+func TestLintConfig_Validate(t *testing.T) {
+	tests := map[string]struct {
+		cfg     LintConfig
+		wantErr bool
+	}{
+		"zero":          {},
+		"full":          {cfg: LintConfig{Disable: []string{RuleTense}, Punctuation: PunctuationPeriod, Tense: TensePast, Banned: []string{"wip"}, Similarity: 0.8}},
+		"unknown rule":  {cfg: LintConfig{Disable: []string{"spelling"}}, wantErr: true},
+		"bad policy":    {cfg: LintConfig{Punctuation: "semicolon"}, wantErr: true},
+		"bad regex":     {cfg: LintConfig{Banned: []string{"("}}, wantErr: true},
+		"bad threshold": {cfg: LintConfig{Similarity: 2}, wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package changelog
+
+import (
+	"fmt"
+	"slices"
+)
+
+// MergeConflict is a change both sides of a merge made incompatibly, such as
+// releasing the same version with different dates.
+type MergeConflict struct {
+	// Version is empty for document-level conflicts.
+	Version string
+	Message string
+}
+
+func (c MergeConflict) Error() string {
+	if c.Version == "" {
+		return c.Message
+	}
+	return fmt.Sprintf("version %s: %s", c.Version, c.Message)
+}
+
+// Merge performs a three-way merge of two changelogs that diverged from base,
+// as a git merge driver does. Entries are merged per category: additions from
+// both sides are kept, ours first, and an entry removed on either side stays
+// removed. Releasing the unreleased block on one branch while adding entries
+// on another therefore leaves the new entries unreleased. The result keeps
+// ours' file layout; conflicting changes resolve to ours and are reported.
+func Merge(base, ours, theirs *Changelog) (*Changelog, []MergeConflict) {
+	m := &merger{}
+	out := *ours
+	out.Project = m.scalar("", "project", base.Project, ours.Project, theirs.Project)
+	out.Versions = m.versions(base, ours, theirs)
+	return &out, m.conflicts
+}
+
+type merger struct {
+	conflicts []MergeConflict
+}
+
+func (m *merger) conflict(version, format string, a ...any) {
+	m.conflicts = append(m.conflicts, MergeConflict{Version: version, Message: fmt.Sprintf(format, a...)})
+}
+
+// scalar merges a single value, reporting a conflict when both sides changed it.
+func (m *merger) scalar(version, field, base, ours, theirs string) string {
+	switch {
+	case ours == theirs || theirs == base:
+		return ours
+	case ours == base:
+		return theirs
+	}
+	m.conflict(version, "%s changed on both sides (ours %q, theirs %q)", field, ours, theirs)
+	return ours
+}
+
+// versions merges the version lists in ours' order. Versions only theirs has
+// are inserted before the next version they precede in theirs.
+func (m *merger) versions(base, ours, theirs *Changelog) []Version {
+	var out []Version
+	for i := range ours.Versions {
+		o := &ours.Versions[i]
+		if v, ok := m.version(findVersion(base, o.Version), o, findVersion(theirs, o.Version)); ok {
+			out = append(out, v)
+		}
+	}
+	for i := range theirs.Versions {
+		t := &theirs.Versions[i]
+		if findVersion(ours, t.Version) != nil {
+			continue
+		}
+		if v, ok := m.version(findVersion(base, t.Version), nil, t); ok {
+			out = insertBefore(out, v, theirs.Versions[i+1:])
+		}
+	}
+	return out
+}
+
+// version merges one version present on at least one side. It reports false
+// when the version was deleted on one side and left unchanged on the other.
+func (m *merger) version(base, ours, theirs *Version) (Version, bool) {
+	switch {
+	case ours == nil || theirs == nil:
+		kept := ours
+		if kept == nil {
+			kept = theirs
+		}
+		if base == nil {
+			return cloneVersion(kept), true
+		}
+		if base.Equal(kept) {
+			return Version{}, false
+		}
+		m.conflict(kept.Version, "deleted on one side and modified on the other")
+		return cloneVersion(kept), true
+	case base == nil:
+		base = &Version{}
+	}
+	// Start from ours so the YAML nodes, and with them comments and
+	// quoting, carry over to the merged version.
+	out := *ours
+	out.Date = m.scalar(ours.Version, "date", base.Date, ours.Date, theirs.Date)
+	out.Public = m.changes(ours.Version, base.Public, ours.Public, theirs.Public)
+	out.Internal = m.changes(ours.Version, base.Internal, ours.Internal, theirs.Internal)
+	return out, true
+}
+
+// changes merges categories in ours' order followed by theirs' new ones.
+func (m *merger) changes(version string, base, ours, theirs Changes) Changes {
+	names := ours.CategoryNames()
+	for _, name := range theirs.CategoryNames() {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	var out Changes
+	for _, name := range names {
+		b, _ := base.Category(name)
+		o, _ := ours.Category(name)
+		t, _ := theirs.Category(name)
+		m.entries(version, &out, b, o, t)
+	}
+	return out
+}
+
+// entries merges one category by entry text into out.
+func (m *merger) entries(version string, out *Changes, base, ours, theirs CategoryEntry) {
+	for i, text := range ours.Entries {
+		bi, ti := slices.Index(base.Entries, text), slices.Index(theirs.Entries, text)
+		if bi >= 0 && ti < 0 {
+			continue // removed by theirs
+		}
+		meta := ours.MetaAt(i)
+		if ti >= 0 {
+			var baseMeta EntryMeta
+			if bi >= 0 {
+				baseMeta = base.MetaAt(bi)
+			}
+			meta = m.meta(version, text, baseMeta, meta, theirs.MetaAt(ti))
+		}
+		out.AppendWithMeta(ours.Name, text, meta)
+	}
+	for i, text := range theirs.Entries {
+		if !slices.Contains(ours.Entries, text) && !slices.Contains(base.Entries, text) {
+			out.AppendWithMeta(theirs.Name, text, theirs.MetaAt(i))
+		}
+	}
+}
+
+// meta merges the metadata of an entry present on both sides.
+func (m *merger) meta(version, text string, base, ours, theirs EntryMeta) EntryMeta {
+	switch {
+	case ours.Equal(theirs) || theirs.Equal(base):
+		return ours
+	case ours.Equal(base):
+		return theirs
+	}
+	m.conflict(version, "metadata of %q changed on both sides", text)
+	return ours
+}
+
+func findVersion(c *Changelog, version string) *Version {
+	v, err := c.GetVersion(version)
+	if err != nil {
+		return nil
+	}
+	return v
+}
+
+// cloneVersion copies v, keeping the YAML nodes it was decoded from.
+func cloneVersion(v *Version) Version {
+	out := *v
+	out.Public, out.Internal = v.Public.Clone(), v.Internal.Clone()
+	return out
+}
+
+// insertBefore inserts v before the first of following that is in versions,
+// or appends it.
+func insertBefore(versions []Version, v Version, following []Version) []Version {
+	for _, f := range following {
+		i := slices.IndexFunc(versions, func(o Version) bool {
+			return NormalizeVersion(o.Version) == NormalizeVersion(f.Version)
+		})
+		if i >= 0 {
+			return slices.Insert(versions, i, v)
+		}
+	}
+	return append(versions, v)
+}
@@ -0,0 +1,216 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+)
+
+const mergeBase = `project: demo
+versions:
+  unreleased:
+    added:
+      - Base entry
+  1.0.0:
+    date: 2024-01-01
+    fixed:
+      - Old fix
+`
+
+func loadMergeBase(t *testing.T) *Changelog {
+	t.Helper()
+	c, err := LoadFromReader(strings.NewReader(mergeBase))
+	if err != nil {
+		t.Fatalf("loading changelog: %v", err)
+	}
+	return c
+}
+
+func TestMerge(t *testing.T) {
+	tests := map[string]struct {
+		ours, theirs func(*Changelog)
+		version      string
+		category     string
+		want         string
+		conflicts    int
+	}{
+		"both append to unreleased": {
+			ours:    func(c *Changelog) { c.GetUnreleased().Public.Append("added", "Ours") },
+			theirs:  func(c *Changelog) { c.GetUnreleased().Public.Append("added", "Theirs") },
+			version: "unreleased", category: "added", want: "Base entry,Ours,Theirs",
+		},
+		"removal honored": {
+			ours: func(c *Changelog) { _, _ = c.GetUnreleased().Public.Remove("added", "Base entry", false) },
+			theirs: func(c *Changelog) {
+				c.GetUnreleased().Public.Append("added", "Theirs")
+			},
+			version: "unreleased", category: "added", want: "Theirs",
+		},
+		"release on one side keeps new entries unreleased": {
+			ours: func(c *Changelog) { _ = c.Release("1.1.0", "2024-06-01") },
+			theirs: func(c *Changelog) {
+				c.GetUnreleased().Public.Append("added", "Late feature")
+			},
+			version: "unreleased", category: "added", want: "Late feature",
+		},
+		"new category from theirs": {
+			ours:    func(c *Changelog) {},
+			theirs:  func(c *Changelog) { c.GetUnreleased().Internal.Append("changed", "Refactor") },
+			version: "unreleased", category: "internal.changed", want: "Refactor",
+		},
+		"same release with different dates": {
+			ours:    func(c *Changelog) { _ = c.Release("1.1.0", "2024-06-01") },
+			theirs:  func(c *Changelog) { _ = c.Release("1.1.0", "2024-06-02") },
+			version: "1.1.0", category: "added", want: "Base entry", conflicts: 1,
+		},
+		"metadata changed on both sides": {
+			ours: func(c *Changelog) {
+				c.GetUnreleased().Public.Categories[0].Meta = []EntryMeta{{PR: 1}}
+			},
+			theirs: func(c *Changelog) {
+				c.GetUnreleased().Public.Categories[0].Meta = []EntryMeta{{PR: 2}}
+			},
+			version: "unreleased", category: "added", want: "Base entry", conflicts: 1,
+		},
+		"version deleted on one side": {
+			ours:    func(c *Changelog) { c.Versions = c.Versions[:1] },
+			theirs:  func(c *Changelog) {},
+			version: "unreleased", category: "added", want: "Base entry",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ours, theirs := loadMergeBase(t), loadMergeBase(t)
+			tt.ours(ours)
+			tt.theirs(theirs)
+
+			merged, conflicts := Merge(loadMergeBase(t), ours, theirs)
+			if len(conflicts) != tt.conflicts {
+				t.Errorf("conflicts = %v, want %d", conflicts, tt.conflicts)
+			}
+			v, err := merged.GetVersion(tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			changes, category := v.Public, tt.category
+			if name, ok := strings.CutPrefix(category, "internal."); ok {
+				changes, category = v.Internal, name
+			}
+			if got := strings.Join(changes.Get(category), ","); got != tt.want {
+				t.Errorf("%s %s = %q, want %q", tt.version, tt.category, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMerge_VersionOrderAndLayout(t *testing.T) {
+	base := loadMergeBase(t)
+	ours := loadMergeBase(t)
+	ours.GetUnreleased().Public.Append("added", "Ours")
+	theirs := loadMergeBase(t)
+	if err := theirs.Release("1.1.0", "2024-06-01"); err != nil {
+		t.Fatal(err)
+	}
+
+	merged, conflicts := Merge(base, ours, theirs)
+	if len(conflicts) != 0 {
+		t.Fatalf("unexpected conflicts: %v", conflicts)
+	}
+	var names []string
+	for _, v := range merged.Versions {
+		names = append(names, v.Version)
+	}
+	if got := strings.Join(names, ","); got != "unreleased,1.1.0,1.0.0" {
+		t.Errorf("versions = %s", got)
+	}
+	if got := merged.GetUnreleased().Public.Get("added"); len(got) != 1 || got[0] != "Ours" {
+		t.Errorf("unreleased added = %v", got)
+	}
+
+	data, err := Marshal(merged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "  1.1.0:\n    date: 2024-06-01") {
+		t.Errorf("merged YAML lost layout:\n%s", data)
+	}
+}
+
+func TestMerge_KeepsComments(t *testing.T) {
+	const doc = `project: demo
+versions:
+  unreleased:
+    added:
+      - Base entry # inline
+  # old release
+  1.0.0:
+    date: "2024-01-01"
+    fixed:
+      - Old fix
+`
+	load := func() *Changelog {
+		c, err := LoadFromReader(strings.NewReader(doc))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	base, ours, theirs := load(), load(), load()
+	ours.GetUnreleased().Public.Append("added", "Ours")
+	theirs.GetUnreleased().Public.Append("fixed", "Theirs")
+
+	merged, conflicts := Merge(base, ours, theirs)
+	if len(conflicts) != 0 {
+		t.Fatalf("unexpected conflicts: %v", conflicts)
+	}
+	data, err := Marshal(merged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"- Base entry # inline\n", "  # old release\n  1.0.0:\n", `date: "2024-01-01"`, "- Ours\n", "- Theirs\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("merged YAML missing %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestMergeConflict_Error(t *testing.T) {
+	if got := (MergeConflict{Version: "1.0.0", Message: "boom"}).Error(); got != "version 1.0.0: boom" {
+		t.Errorf("Error() = %q", got)
+	}
+	if got := (MergeConflict{Message: "boom"}).Error(); got != "boom" {
+		t.Errorf("Error() = %q", got)
+	}
+}
+
+func TestMerge_DeletedVersionUnchangedAfterLinesMoved(t *testing.T) {
+	base := loadMergeBase(t)
+	ours := loadMergeBase(t)
+	ours.Versions = ours.Versions[:1]
+	theirs, err := LoadFromReader(strings.NewReader(`project: demo
+versions:
+  unreleased:
+    added:
+      - Base entry
+      - Theirs
+    fixed:
+      - Another
+  1.0.0:
+    date: 2024-01-01
+    fixed:
+      - Old fix
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged, conflicts := Merge(base, ours, theirs)
+	if len(conflicts) != 0 {
+		t.Errorf("unexpected conflicts: %v", conflicts)
+	}
+	if _, err := merged.GetVersion("1.0.0"); err == nil {
+		t.Error("1.0.0 deleted by ours was restored")
+	}
+	if got := strings.Join(merged.GetUnreleased().Public.Get("added"), ","); got != "Base entry,Theirs" {
+		t.Errorf("unreleased added = %q", got)
+	}
+}
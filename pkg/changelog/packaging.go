@@ -0,0 +1,282 @@
+package changelog
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	DefaultDebianFile         = "debian/changelog"
+	DefaultDebianDistribution = "unstable"
+	DefaultDebianUrgency      = "medium"
+	DefaultPackageRevision    = "1"
+	// packagingWidth is the line width entries are wrapped at.
+	packagingWidth = 80
+)
+
+// DebianUrgencies are the urgency values dpkg accepts.
+var DebianUrgencies = []string{"low", "medium", "high", "emergency", "critical"}
+
+// PackagingConfig configures the Debian and RPM changelogs of
+// `chlog sync --format debian|rpm`.
+type PackagingConfig struct {
+	// Name is the package name; defaults to the changelog's project.
+	Name string `yaml:"name,omitempty"`
+	// Maintainer is "Full Name <email>", as required by both formats.
+	Maintainer string `yaml:"maintainer,omitempty"`
+	// Revision is the Debian revision and RPM release appended to each
+	// version, e.g. 1.2.0-1.
+	Revision     string `yaml:"revision,omitempty"`
+	Distribution string `yaml:"distribution,omitempty"`
+	Urgency      string `yaml:"urgency,omitempty"`
+	DebianFile   string `yaml:"debian_file,omitempty"`
+	// RPMFile is a spec file whose %changelog section is replaced, or any
+	// other file, which receives the section's entries only. Defaults to
+	// "<name>.spec".
+	RPMFile string `yaml:"rpm_file,omitempty"`
+}
+
+// Validate checks the maintainer address and the urgency.
+func (p PackagingConfig) Validate() error {
+	if p.Maintainer != "" {
+		if _, err := mail.ParseAddress(p.Maintainer); err != nil {
+			return fmt.Errorf("packaging.maintainer: %q is not \"Full Name <email>\"", p.Maintainer)
+		}
+	}
+	if p.Urgency != "" && !slices.Contains(DebianUrgencies, p.Urgency) {
+		return fmt.Errorf("packaging.urgency: invalid value %q (valid: %s)", p.Urgency, strings.Join(DebianUrgencies, ", "))
+	}
+	return nil
+}
+
+// PackageName returns Name, or project when Name is unset.
+func (p PackagingConfig) PackageName(project string) string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return project
+}
+
+// DebianFilePath returns DebianFile if set, otherwise the default.
+func (p PackagingConfig) DebianFilePath() string {
+	if p.DebianFile != "" {
+		return p.DebianFile
+	}
+	return DefaultDebianFile
+}
+
+// RPMFilePath returns RPMFile if set, otherwise "<package>.spec".
+func (p PackagingConfig) RPMFilePath(project string) string {
+	if p.RPMFile != "" {
+		return p.RPMFile
+	}
+	return p.PackageName(project) + ".spec"
+}
+
+// packageRelease is a released version as packaging changelogs render it.
+type packageRelease struct {
+	Version    string // with revision, e.g. "1.2.0~rc.1-1"
+	Date       time.Time
+	Categories []TemplateCategory
+}
+
+// packageReleases returns the released versions of c, newest first as in
+// the file, after checking the settings both formats need. Packages ship to
+// users, so only public entries are included, whatever opt.IncludeInternal
+// says.
+func packageReleases(c *Changelog, opt RenderOptions) (PackagingConfig, []packageRelease, error) {
+	var p PackagingConfig
+	if opt.Config != nil {
+		p = opt.Config.Packaging
+	}
+	if p.Maintainer == "" {
+		return p, nil, fmt.Errorf("packaging.maintainer is required")
+	}
+	if p.PackageName(c.Project) == "" {
+		return p, nil, fmt.Errorf("packaging.name is required when the changelog has no project")
+	}
+	revision := p.Revision
+	if revision == "" {
+		revision = DefaultPackageRevision
+	}
+
+	var releases []packageRelease
+	for i := range c.Versions {
+		v := &c.Versions[i]
+		if v.IsUnreleased() {
+			continue
+		}
+		date, err := time.Parse(time.DateOnly, v.Date)
+		if err != nil {
+			return p, nil, fmt.Errorf("version %s: date %q is not YYYY-MM-DD", v.Version, v.Date)
+		}
+		releases = append(releases, packageRelease{
+			Version:    packageVersion(v.Version) + "-" + revision,
+			Date:       date,
+			Categories: newTemplateVersion(v, false, repoLinks{}).Categories,
+		})
+	}
+	return p, releases, nil
+}
+
+// packageVersion converts a version to the upstream version of a package:
+// no "v" prefix, and "~" before a pre-release so that 2.0.0~rc.1 sorts
+// before 2.0.0 in dpkg and rpm.
+func packageVersion(version string) string {
+	version = strings.TrimPrefix(version, "v")
+	return strings.Replace(version, "-", "~", 1)
+}
+
+// packageEntry renders an entry as plain text with its references; both
+// formats are plain text, so inline markdown is removed.
+func packageEntry(e TemplateEntry) string {
+	var b strings.Builder
+	if e.Breaking {
+		b.WriteString("BREAKING: ")
+	}
+	if e.Scope != "" {
+		b.WriteString(e.Scope + ": ")
+	}
+	b.WriteString(plainText(e.Text))
+	if refs := entryReferences(e.EntryMeta); len(refs) > 0 {
+		fmt.Fprintf(&b, " (%s)", strings.Join(refs, ", "))
+	}
+	return b.String()
+}
+
+// writeWrapped writes text word-wrapped at packagingWidth, the first line
+// after prefix and the others after indent.
+func writeWrapped(b *strings.Builder, prefix, indent, text string) {
+	line := prefix
+	for i, word := range strings.Fields(text) {
+		if i > 0 && utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) > packagingWidth {
+			b.WriteString(line + "\n")
+			line = indent + word
+			continue
+		}
+		if i > 0 {
+			line += " "
+		}
+		line += word
+	}
+	b.WriteString(line + "\n")
+}
+
+// RenderDebian writes the released versions of c in the debian/changelog
+// format, configured by opts.Config.Packaging:
+//
+//	pkg (1.2.0-1) unstable; urgency=medium
+//
+//	  * Added:
+//	    - Entry text
+//
+//	 -- Jane Doe <jane@example.com>  Mon, 02 Mar 2026 00:00:00 +0000
+func RenderDebian(c *Changelog, w io.Writer, opts ...RenderOptions) error {
+	var opt RenderOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	p, releases, err := packageReleases(c, opt)
+	if err != nil {
+		return err
+	}
+	dist := cmp.Or(p.Distribution, DefaultDebianDistribution)
+	urgency := cmp.Or(p.Urgency, DefaultDebianUrgency)
+
+	var b strings.Builder
+	for i, r := range releases {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s (%s) %s; urgency=%s\n\n", p.PackageName(c.Project), r.Version, dist, urgency)
+		if len(r.Categories) == 0 {
+			b.WriteString("  * New upstream release.\n")
+		}
+		for _, cat := range r.Categories {
+			fmt.Fprintf(&b, "  * %s:\n", cat.Title)
+			for _, e := range cat.Entries {
+				writeWrapped(&b, "    - ", "      ", packageEntry(e))
+			}
+		}
+		fmt.Fprintf(&b, "\n -- %s  %s\n", p.Maintainer, r.Date.Format(time.RFC1123Z))
+	}
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// RenderDebianString renders the debian/changelog of c to a string.
+func RenderDebianString(c *Changelog, opts ...RenderOptions) (string, error) {
+	var b strings.Builder
+	if err := RenderDebian(c, &b, opts...); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// RenderRPM writes the released versions of c as the entries of an RPM spec
+// %changelog section, the lines that follow the %changelog line:
+//
+//	%changelog
+//	* Mon Mar 02 2026 Jane Doe <jane@example.com> - 1.2.0-1
+//	- Added: Entry text
+func RenderRPM(c *Changelog, w io.Writer, opts ...RenderOptions) error {
+	var opt RenderOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	p, releases, err := packageReleases(c, opt)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	for i, r := range releases {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "* %s %s - %s\n", r.Date.Format("Mon Jan 02 2006"), p.Maintainer, r.Version)
+		if len(r.Categories) == 0 {
+			b.WriteString("- New upstream release\n")
+		}
+		for _, cat := range r.Categories {
+			for _, e := range cat.Entries {
+				// rpmbuild expands macros in %changelog too.
+				text := strings.ReplaceAll(packageEntry(e), "%", "%%")
+				writeWrapped(&b, "- "+cat.Title+": ", "  ", text)
+			}
+		}
+	}
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// RenderRPMString renders the RPM %changelog entries of c to a string.
+func RenderRPMString(c *Changelog, opts ...RenderOptions) (string, error) {
+	var b strings.Builder
+	if err := RenderRPM(c, &b, opts...); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// ReplaceRPMChangelog replaces everything after the %changelog line of a
+// spec file with entries, the output of RenderRPM.
+func ReplaceRPMChangelog(spec, entries string) (string, error) {
+	lines := strings.SplitAfter(spec, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "%changelog" {
+			head := strings.Join(lines[:i+1], "")
+			if !strings.HasSuffix(head, "\n") {
+				head += "\n"
+			}
+			return head + entries, nil
+		}
+	}
+	return "", fmt.Errorf("no %%changelog section")
+}
@@ -0,0 +1,162 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+)
+
+func packagingChangelog() *Changelog {
+	c := &Changelog{Project: "demo", Versions: []Version{
+		{Version: "unreleased"},
+		{Version: "2.0.0-rc.1", Date: "2026-03-02"},
+		{Version: "1.0.0", Date: "2026-01-05"},
+	}}
+	c.Versions[0].Public.Append("added", "Not released yet")
+	c.Versions[1].Public.AppendWithMeta("removed", "Dropped the v1 API", EntryMeta{Breaking: true, PR: 12})
+	c.Versions[1].Public.Append("fixed", "Progress no longer stops at 99% when the download is retried after a network timeout")
+	c.Versions[1].Public.Append("fixed", "**Crash** in [login](https://e.com/a_(b)) with `--sso`")
+	c.Versions[2].Internal.Append("changed", "Refactored the store")
+	return c
+}
+
+var packagingConfig = &Config{Packaging: PackagingConfig{Maintainer: "Jane Doe <jane@example.com>"}}
+
+func TestRenderDebian(t *testing.T) {
+	got, err := RenderDebianString(packagingChangelog(), RenderOptions{Config: packagingConfig})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `demo (2.0.0~rc.1-1) unstable; urgency=medium
+
+  * Removed:
+    - BREAKING: Dropped the v1 API (#12)
+  * Fixed:
+    - Progress no longer stops at 99% when the download is retried after a
+      network timeout
+    - Crash in login with --sso
+
+ -- Jane Doe <jane@example.com>  Mon, 02 Mar 2026 00:00:00 +0000
+
+demo (1.0.0-1) unstable; urgency=medium
+
+  * New upstream release.
+
+ -- Jane Doe <jane@example.com>  Mon, 05 Jan 2026 00:00:00 +0000
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderDebian_Settings(t *testing.T) {
+	cfg := &Config{Packaging: PackagingConfig{
+		Name: "demo-cli", Maintainer: "Jane Doe <jane@example.com>",
+		Revision: "0ubuntu1", Distribution: "noble", Urgency: "high",
+	}}
+	got, err := RenderDebianString(packagingChangelog(), RenderOptions{Config: cfg, IncludeInternal: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "demo-cli (2.0.0~rc.1-0ubuntu1) noble; urgency=high\n"; !strings.Contains(got, want) {
+		t.Errorf("missing %q in\n%s", want, got)
+	}
+	if strings.Contains(got, "Refactored the store") {
+		t.Errorf("internal entry in packaging changelog:\n%s", got)
+	}
+}
+
+func TestRenderRPM(t *testing.T) {
+	got, err := RenderRPMString(packagingChangelog(), RenderOptions{Config: packagingConfig})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `* Mon Mar 02 2026 Jane Doe <jane@example.com> - 2.0.0~rc.1-1
+- Removed: BREAKING: Dropped the v1 API (#12)
+- Fixed: Progress no longer stops at 99%% when the download is retried after a
+  network timeout
+- Fixed: Crash in login with --sso
+
+* Mon Jan 05 2026 Jane Doe <jane@example.com> - 1.0.0-1
+- New upstream release
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderPackaging_Errors(t *testing.T) {
+	tests := map[string]struct {
+		c       *Changelog
+		cfg     *Config
+		wantErr string
+	}{
+		"no maintainer": {packagingChangelog(), &Config{}, "packaging.maintainer is required"},
+		"no config":     {packagingChangelog(), nil, "packaging.maintainer is required"},
+		"no name":       {&Changelog{}, packagingConfig, "packaging.name is required"},
+		"bad date": {
+			&Changelog{Project: "demo", Versions: []Version{{Version: "1.0.0", Date: "soon"}}},
+			packagingConfig, "version 1.0.0",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			for _, render := range []func(*Changelog, ...RenderOptions) (string, error){RenderDebianString, RenderRPMString} {
+				_, err := render(tt.c, RenderOptions{Config: tt.cfg})
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("err = %v, want %q", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestReplaceRPMChangelog(t *testing.T) {
+	tests := map[string]struct {
+		spec    string
+		want    string
+		wantErr bool
+	}{
+		"replaces section": {
+			spec: "Name: demo\n\n%changelog\n* Old entry\n- stale\n",
+			want: "Name: demo\n\n%changelog\n* New\n",
+		},
+		"no trailing newline": {spec: "Name: demo\n%changelog", want: "Name: demo\n%changelog\n* New\n"},
+		"no section":          {spec: "Name: demo\n", wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ReplaceRPMChangelog(tt.spec, "* New\n")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPackagingConfig_Validate(t *testing.T) {
+	tests := map[string]struct {
+		cfg     PackagingConfig
+		wantErr bool
+	}{
+		"empty":            {PackagingConfig{}, false},
+		"valid":            {PackagingConfig{Maintainer: "Jane Doe <jane@example.com>", Urgency: "low"}, false},
+		"maintainer email": {PackagingConfig{Maintainer: "jane@"}, true},
+		"urgency":          {PackagingConfig{Urgency: "asap"}, true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package changelog
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -13,46 +15,108 @@ import (
 var dateRegex = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
 
 // Load reads and parses a YAML changelog from the given path.
-func Load(path string) (*Changelog, error) {
+// An optional Config is passed on to Validate; when it enables fragments,
+// the fragment files are overlaid onto the unreleased version first.
+func Load(path string, cfg ...*Config) (*Changelog, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("opening changelog: %w", err)
 	}
 	defer func() { _ = f.Close() }()
-	return LoadFromReader(f)
+	c, err := decodeChangelog(f)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg) > 0 && cfg[0] != nil && cfg[0].Fragments {
+		frags, err := LoadFragments(cfg[0].FragmentDirPath())
+		if err != nil {
+			return nil, err
+		}
+		c.ApplyFragments(frags)
+	}
+	if err := validateLoaded(c, cfg...); err != nil {
+		return nil, err
+	}
+	return c, nil
 }
 
 // LoadFromReader parses a YAML changelog from a reader.
-func LoadFromReader(r io.Reader) (*Changelog, error) {
-	var c Changelog
-	dec := yaml.NewDecoder(r)
-	if err := dec.Decode(&c); err != nil {
+// The parsed document is retained so Save can preserve comments and layout.
+func LoadFromReader(r io.Reader, cfg ...*Config) (*Changelog, error) {
+	c, err := decodeChangelog(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateLoaded(c, cfg...); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func decodeChangelog(r io.Reader) (*Changelog, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading changelog: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
 		return nil, fmt.Errorf("decoding YAML: %w", err)
 	}
-	if errs := Validate(&c); len(errs) > 0 {
-		msgs := make([]string, len(errs))
-		for i, e := range errs {
-			msgs[i] = e.Error()
-		}
-		return nil, fmt.Errorf("validation failed:\n  %s", strings.Join(msgs, "\n  "))
+	if doc.Kind == 0 {
+		return nil, fmt.Errorf("decoding YAML: %w", io.EOF)
 	}
+	var c Changelog
+	if err := doc.Decode(&c); err != nil {
+		return nil, positionDecodeError(err)
+	}
+	c.source = newSourceDoc(&doc, data)
 	return &c, nil
 }
 
+// positionDecodeError returns the ValidationErrors of a decoding error that
+// is known to be at a node, so it is reported with its position.
+func positionDecodeError(err error) error {
+	var verrs ValidationErrors
+	if errors.As(err, &verrs) {
+		return verrs
+	}
+	var de *decodeError
+	if errors.As(err, &de) {
+		return ValidationErrors{de.validationError()}
+	}
+	return fmt.Errorf("decoding YAML: %w", err)
+}
+
+func validateLoaded(c *Changelog, cfg ...*Config) error {
+	if errs := Validate(c, cfg...); len(errs) > 0 {
+		return ValidationErrors(errs)
+	}
+	return nil
+}
+
 // Validate checks a Changelog for structural and semantic errors.
-// An optional Config can be passed to control category validation.
+// An optional Config can be passed to control category validation and,
+// through its version scheme, version syntax and ordering. Errors of a
+// changelog loaded from YAML carry the line and column of the offending node.
 func Validate(c *Changelog, cfg ...*Config) []ValidationError {
 	var errs []ValidationError
 
 	if strings.TrimSpace(c.Project) == "" {
-		errs = append(errs, ValidationError{Field: "project", Message: "must not be empty"})
+		root := c.rootNode()
+		errs = append(errs, ValidationError{Rule: RuleEmptyProject, Field: "project", Message: "must not be empty"}.
+			at(cmp.Or(mappingValue(root, "project"), root)))
 	}
 
-	var allowed []string
+	allowed, scheme := DefaultCategories, SchemeNone
 	if len(cfg) > 0 && cfg[0] != nil {
 		allowed = cfg[0].AllowedCategories()
-	} else {
-		allowed = DefaultCategories
+		var err error
+		if scheme, err = cfg[0].Scheme(); err != nil {
+			errs = append(errs, ValidationError{Rule: RuleBadConfig, Field: "version_scheme", Message: err.Error()})
+		}
+		if err := cfg[0].checkCompareTemplate(); err != nil {
+			errs = append(errs, ValidationError{Rule: RuleBadConfig, Field: "compare_url_template", Message: err.Error()})
+		}
 	}
 
 	allowedSet := make(map[string]bool, len(allowed))
@@ -63,20 +127,22 @@ func Validate(c *Changelog, cfg ...*Config) []ValidationError {
 	seen := map[string]bool{}
 	unreleasedCount := 0
 
-	for i, v := range c.Versions {
+	// Fragment entries are checked in their own files below.
+	for i, v := range c.withoutFragments().Versions {
 		prefix := fmt.Sprintf("versions[%d]", i)
 
 		if strings.TrimSpace(v.Version) == "" {
-			errs = append(errs, ValidationError{Field: prefix + ".version", Message: "must not be empty"})
+			errs = append(errs, ValidationError{Rule: RuleEmptyVersion, Field: prefix + ".version", Message: "must not be empty"}.at(v.key))
 			continue
 		}
 
 		normalized := NormalizeVersion(v.Version)
 		if seen[normalized] {
 			errs = append(errs, ValidationError{
+				Rule:    RuleDuplicateVersion,
 				Field:   prefix + ".version",
 				Message: fmt.Sprintf("duplicate version %q", v.Version),
-			})
+			}.at(v.key))
 		}
 		seen[normalized] = true
 
@@ -84,58 +150,105 @@ func Validate(c *Changelog, cfg ...*Config) []ValidationError {
 			unreleasedCount++
 			if unreleasedCount > 1 {
 				errs = append(errs, ValidationError{
+					Rule:    RuleMultipleUnreleased,
 					Field:   prefix + ".version",
 					Message: "only one unreleased version allowed",
-				})
+				}.at(v.key))
 			}
 		} else {
-			if v.Date == "" {
-				errs = append(errs, ValidationError{
-					Field:   prefix + ".date",
-					Message: "date required for released versions",
-				})
-			} else if !dateRegex.MatchString(v.Date) {
-				errs = append(errs, ValidationError{
-					Field:   prefix + ".date",
-					Message: fmt.Sprintf("invalid date format %q, expected YYYY-MM-DD", v.Date),
-				})
-			}
+			errs = append(errs, validateDate(&v, prefix)...)
 		}
 
 		if v.IsEmpty() && v.Internal.IsEmpty() && !v.IsUnreleased() {
 			errs = append(errs, ValidationError{
+				Rule:    RuleEmptyRelease,
 				Field:   prefix,
 				Message: "must have at least one entry",
-			})
+			}.at(v.key))
 		}
 
 		// Validate public categories
-		errs = append(errs, validateChanges(v.Public, prefix, allowed, allowedSet)...)
+		errs = append(errs, validateChanges(v.Public, v.node, prefix, allowed, allowedSet)...)
 
 		// Validate internal categories
-		errs = append(errs, validateChanges(v.Internal, prefix+".internal", allowed, allowedSet)...)
+		errs = append(errs, validateChanges(v.Internal, mappingValue(v.node, "internal"), prefix+".internal", allowed, allowedSet)...)
 	}
 
+	if c.overlay != nil {
+		for _, f := range c.overlay.frags {
+			errs = append(errs, validateFragment(f, allowed, allowedSet)...)
+		}
+	}
+
+	if scheme != SchemeNone && scheme != "" {
+		errs = append(errs, validateOrder(c, scheme)...)
+	}
 	return errs
 }
 
-// validateChanges checks entries within a Changes value.
-func validateChanges(changes Changes, prefix string, allowed []string, allowedSet map[string]bool) []ValidationError {
+// validateFragment checks the entries of a fragment, positioning errors in
+// its file, e.g. "internal.changed[0]".
+func validateFragment(f *Fragment, allowed []string, allowedSet map[string]bool) []ValidationError {
+	errs := validateChanges(f.Public, f.node, "", allowed, allowedSet)
+	errs = append(errs, validateChanges(f.Internal, mappingValue(f.node, "internal"), "internal", allowed, allowedSet)...)
+	for i := range errs {
+		errs[i].File = f.Path
+	}
+	return errs
+}
+
+// validateDate checks that a released version has a YYYY-MM-DD date.
+func validateDate(v *Version, prefix string) []ValidationError {
+	if v.Date == "" {
+		return []ValidationError{ValidationError{
+			Rule:    RuleMissingDate,
+			Field:   prefix + ".date",
+			Message: "date required for released versions",
+		}.at(v.key)}
+	}
+	if !dateRegex.MatchString(v.Date) {
+		return []ValidationError{ValidationError{
+			Rule:    RuleBadDate,
+			Field:   prefix + ".date",
+			Message: fmt.Sprintf("invalid date format %q, expected YYYY-MM-DD", v.Date),
+		}.at(mappingValue(v.node, "date"))}
+	}
+	return nil
+}
+
+// validateChanges checks entries within a Changes value. node is the mapping
+// the changes were decoded from, if any, for error positions.
+func validateChanges(changes Changes, node *yaml.Node, prefix string, allowed []string, allowedSet map[string]bool) []ValidationError {
 	var errs []ValidationError
 	for _, cat := range changes.Categories {
+		field := cat.Name
+		if prefix != "" {
+			field = prefix + "." + cat.Name
+		}
 		// Check category is allowed (only when allowed is non-nil = strict mode)
 		if allowed != nil && !allowedSet[cat.Name] {
 			errs = append(errs, ValidationError{
-				Field:   prefix + "." + cat.Name,
+				Rule:    RuleUnknownCategory,
+				Field:   field,
 				Message: fmt.Sprintf("unknown category %q", cat.Name),
-			})
+			}.at(mappingKey(node, cat.Name)))
 		}
+		items := mappingValue(node, cat.Name)
 		for j, entry := range cat.Entries {
+			item := sequenceItem(items, j)
 			if strings.TrimSpace(entry) == "" {
 				errs = append(errs, ValidationError{
-					Field:   fmt.Sprintf("%s.%s[%d]", prefix, cat.Name, j),
+					Rule:    RuleEmptyEntry,
+					Field:   fmt.Sprintf("%s[%d]", field, j),
 					Message: "entry must not be empty",
-				})
+				}.at(item))
+			}
+			if cat.MetaAt(j).PR < 0 {
+				errs = append(errs, ValidationError{
+					Rule:    RuleBadPR,
+					Field:   fmt.Sprintf("%s[%d].pr", field, j),
+					Message: "pr must be a positive number",
+				}.at(cmp.Or(mappingValue(item, "pr"), item)))
 			}
 		}
 	}
@@ -143,10 +256,13 @@ func validateChanges(changes Changes, prefix string, allowed []string, allowedSe
 }
 
 // Save marshals a Changelog to YAML and writes it to the given path.
+// A changelog obtained from Load or LoadFromReader is written back with only
+// the edited parts changed: comments, blank lines, quoting, indentation and
+// key order of untouched content are preserved.
 func Save(c *Changelog, path string) error {
-	data, err := yaml.Marshal(c)
+	data, err := Marshal(c)
 	if err != nil {
-		return fmt.Errorf("marshaling YAML: %w", err)
+		return err
 	}
 	if err := os.WriteFile(path, data, 0644); err != nil {
 		return fmt.Errorf("writing file: %w", err)
@@ -154,6 +270,24 @@ func Save(c *Changelog, path string) error {
 	return nil
 }
 
+// Marshal encodes a Changelog to YAML, preserving the original layout when
+// the changelog was loaded from a file. Use yaml.Marshal for a canonical rewrite.
+func Marshal(c *Changelog) ([]byte, error) {
+	c = c.withoutFragments()
+	if c.source != nil {
+		data, err := c.source.render(c)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling YAML: %w", err)
+		}
+		return data, nil
+	}
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling YAML: %w", err)
+	}
+	return data, nil
+}
+
 // NormalizeVersion strips a leading "v" and lowercases the version string.
 func NormalizeVersion(version string) string {
 	v := strings.ToLower(version)
@@ -1,6 +1,8 @@
 package changelog
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"strings"
 	"testing"
@@ -244,3 +246,72 @@ func TestValidate_CustomCategories(t *testing.T) {
 		}
 	}
 }
+
+func TestLoadFromReader_WithConfig(t *testing.T) {
+	input := "project: test\nversions:\n  unreleased:\n    perf:\n      - Faster\n"
+	if _, err := LoadFromReader(strings.NewReader(input)); err == nil {
+		t.Error("expected unknown category error with default categories")
+	}
+	strict := false
+	if _, err := LoadFromReader(strings.NewReader(input), &Config{StrictCategories: &strict}); err != nil {
+		t.Errorf("unexpected error with non-strict config: %v", err)
+	}
+}
+
+func TestLoadFromReader_ValidationPositions(t *testing.T) {
+	yaml := `project: demo
+versions:
+  unreleased:
+    bogus:
+      - x
+    internal:
+      added:
+        - ""
+  1.0.0:
+    date: 2024-1-01
+    fixed:
+      - text: Bug
+        pr: -3
+  0.9.0:
+    fixed: [a]
+`
+	_, err := LoadFromReader(strings.NewReader(yaml))
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("expected ValidationErrors, got %T: %v", err, err)
+	}
+	want := []string{
+		"4:5: versions[0].bogus [unknown-category]",
+		"8:11: versions[0].internal.added[0] [empty-entry]",
+		"10:11: versions[1].date [bad-date]",
+		"13:13: versions[1].fixed[0].pr [bad-pr]",
+		"14:3: versions[2].date [missing-date]",
+	}
+	var got []string
+	for _, e := range verrs {
+		got = append(got, fmt.Sprintf("%d:%d: %s [%s]", e.Line, e.Column, e.Field, e.Rule))
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("positions =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	var single ValidationError
+	if !errors.As(err, &single) || single.Field != "versions[0].bogus" {
+		t.Errorf("errors.As to ValidationError = %+v", single)
+	}
+}
+
+func TestValidate_NoPositionWithoutSource(t *testing.T) {
+	errs := Validate(&Changelog{})
+	if len(errs) != 1 || errs[0].Line != 0 || errs[0].Error() != "project: must not be empty" {
+		t.Errorf("errs = %+v", errs)
+	}
+}
+
+func TestValidate_BadCompareTemplate(t *testing.T) {
+	cfg := &Config{CompareURLTemplate: "{{.Repo}}/compare/{{.From}}...{{.To}}"}
+	errs := Validate(&Changelog{Project: "demo"}, cfg)
+	if len(errs) != 1 || errs[0].Rule != RuleBadConfig || errs[0].Field != "compare_url_template" {
+		t.Errorf("errs = %+v, want one bad-config error", errs)
+	}
+}
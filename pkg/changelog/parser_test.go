@@ -1,6 +1,7 @@
 package changelog
 
 import (
+	"errors"
 	"strings"
 	"testing"
 )
@@ -49,6 +50,42 @@ func TestLoadFromReader_InvalidYAML(t *testing.T) {
 	}
 }
 
+func TestLoadFromReader_DecodeErrorPositions(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		want  string
+	}{
+		"duplicate version": {
+			input: "project: demo\nversions:\n  1.0.0:\n    added:\n      - A\n  1.0.0:\n    added:\n      - B\n",
+			want:  `6:3: versions[1].version: duplicate version "1.0.0"`,
+		},
+		"unknown entry field": {
+			input: "project: demo\nversions:\n  unreleased:\n    added:\n      - text: Thing\n        ticket: 12\n",
+			want:  `6:9: versions[0].added[0]: unknown entry field "ticket"`,
+		},
+		"bad metadata type": {
+			input: "project: demo\nversions:\n  unreleased:\n    added:\n      - text: Thing\n        pr: abc\n",
+			want:  "5:9: versions[0].added[0]: cannot unmarshal !!str `abc` into int",
+		},
+		"unknown field": {
+			input: "project: demo\nbogus: 1\n",
+			want:  `2:1: bogus: unknown field "bogus"`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadFromReader(strings.NewReader(tt.input))
+			var verrs ValidationErrors
+			if !errors.As(err, &verrs) || len(verrs) != 1 {
+				t.Fatalf("err = %v, want one ValidationError", err)
+			}
+			if got := verrs[0].Error(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestValidate(t *testing.T) {
 	tests := map[string]struct {
 		fixture    string
@@ -0,0 +1,150 @@
+package changelog
+
+import (
+	"fmt"
+	"slices"
+)
+
+// semverRelease pairs a released version with its parsed semver.
+type semverRelease struct {
+	v  *Version
+	sv SemVer
+}
+
+// semverReleases parses every released version in file order. It reports
+// false if any of them is not a semantic version, in which case callers fall
+// back to file order.
+func (c *Changelog) semverReleases() ([]semverRelease, bool) {
+	var rels []semverRelease
+	for i := range c.Versions {
+		v := &c.Versions[i]
+		if v.IsUnreleased() {
+			continue
+		}
+		sv, err := ParseSemver(v.Version)
+		if err != nil {
+			return nil, false
+		}
+		rels = append(rels, semverRelease{v, sv})
+	}
+	return rels, true
+}
+
+// highestRelease returns the release with the highest precedence among those
+// accepted by keep, preferring the earliest in file order on ties.
+func highestRelease(rels []semverRelease, keep func(SemVer) bool) *Version {
+	var best *semverRelease
+	for i := range rels {
+		if keep(rels[i].sv) && (best == nil || rels[i].sv.Compare(best.sv) > 0) {
+			best = &rels[i]
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	return best.v
+}
+
+// previousRelease returns the release version i is compared against in
+// comparison links. When all releases are semver this is the highest lower
+// version by precedence; a final release skips pre-releases unless there is
+// no earlier final release. Otherwise it is the next version in file order.
+func (c *Changelog) previousRelease(i int) *Version {
+	v := &c.Versions[i]
+	if v.IsUnreleased() {
+		return c.GetLatestRelease()
+	}
+	rels, ok := c.semverReleases()
+	if !ok {
+		if i+1 < len(c.Versions) && !c.Versions[i+1].IsUnreleased() {
+			return &c.Versions[i+1]
+		}
+		return nil
+	}
+	sv, _ := ParseSemver(v.Version)
+	lower := func(o SemVer) bool { return o.Compare(sv) < 0 }
+	if !sv.IsPrerelease() {
+		stable := func(o SemVer) bool { return lower(o) && !o.IsPrerelease() }
+		if prev := highestRelease(rels, stable); prev != nil {
+			return prev
+		}
+	}
+	return highestRelease(rels, lower)
+}
+
+// PrereleasesOf returns the pre-releases of a final semver version, e.g.
+// 2.0.0-rc.2 and 2.0.0-rc.1 for "2.0.0", highest precedence first.
+func (c *Changelog) PrereleasesOf(version string) ([]*Version, error) {
+	target, err := ParseSemver(version)
+	if err != nil {
+		return nil, err
+	}
+	if target.IsPrerelease() {
+		return nil, fmt.Errorf("%q is itself a pre-release", version)
+	}
+	var pres []semverRelease
+	for i := range c.Versions {
+		sv, err := ParseSemver(c.Versions[i].Version)
+		if err != nil || !sv.IsPrerelease() {
+			continue
+		}
+		if sv.Major == target.Major && sv.Minor == target.Minor && sv.Patch == target.Patch {
+			pres = append(pres, semverRelease{&c.Versions[i], sv})
+		}
+	}
+	slices.SortStableFunc(pres, func(a, b semverRelease) int { return b.sv.Compare(a.sv) })
+	out := make([]*Version, len(pres))
+	for i, p := range pres {
+		out[i] = p.v
+	}
+	return out, nil
+}
+
+// WithPrereleases returns a copy of a final release with the entries of its
+// pre-releases appended, newest first and without duplicates. The changelog
+// is not modified.
+func (c *Changelog) WithPrereleases(version string) (*Version, error) {
+	v, err := c.GetVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	pres, err := c.PrereleasesOf(v.Version)
+	if err != nil {
+		return nil, err
+	}
+	merged := &Version{Version: v.Version, Date: v.Date, Public: v.Public.Clone(), Internal: v.Internal.Clone()}
+	foldVersions(merged, pres)
+	return merged, nil
+}
+
+// CollapsePrereleases merges the entries of a final release's pre-releases
+// into it, without duplicates, and removes the pre-release blocks.
+// It returns the number of pre-releases removed.
+func (c *Changelog) CollapsePrereleases(version string) (int, error) {
+	v, err := c.GetVersion(version)
+	if err != nil {
+		return 0, err
+	}
+	pres, err := c.PrereleasesOf(v.Version)
+	if err != nil || len(pres) == 0 {
+		return 0, err
+	}
+	foldVersions(v, pres)
+
+	// Collect names first: deleting shifts the elements pres points into.
+	names := make([]string, len(pres))
+	for i, p := range pres {
+		names[i] = p.Version
+	}
+	c.Versions = slices.DeleteFunc(c.Versions, func(o Version) bool {
+		return slices.Contains(names, o.Version)
+	})
+	return len(names), nil
+}
+
+func foldVersions(dst *Version, srcs []*Version) {
+	for _, src := range srcs {
+		dst.Public.mergeUnique(src.Public)
+		dst.Internal.mergeUnique(src.Internal)
+	}
+}
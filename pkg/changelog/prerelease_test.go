@@ -0,0 +1,125 @@
+package changelog
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// prereleaseChangelog has an unreleased fix, two release candidates of 2.0.0
+// sharing an entry, and a final 1.9.0.
+func prereleaseChangelog() *Changelog {
+	c := bumpChangelog("fixed", "2.0.0-rc.2", "2.0.0-rc.1", "1.9.0")
+	c.Versions[1].Public.Append("fixed", "Shared fix")
+	c.Versions[2].Public.Append("fixed", "Shared fix")
+	c.Versions[2].Internal.Append("changed", "Refactor")
+	return c
+}
+
+func TestPrereleasesOf(t *testing.T) {
+	c := bumpChangelog("", "1.9.0", "2.0.0-rc.1", "2.0.0-rc.2", "2.0.1-rc.1")
+
+	pres, err := c.PrereleasesOf("v2.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pres) != 2 || pres[0].Version != "2.0.0-rc.2" || pres[1].Version != "2.0.0-rc.1" {
+		t.Errorf("PrereleasesOf = %v", pres)
+	}
+	if _, err := c.PrereleasesOf("2.0.0-rc.1"); err == nil {
+		t.Error("expected error for a pre-release target")
+	}
+	if _, err := c.PrereleasesOf("next"); err == nil {
+		t.Error("expected error for a non-semver target")
+	}
+}
+
+func TestRelease_CollapsePrereleases(t *testing.T) {
+	c := prereleaseChangelog()
+	if err := c.Release("2.0.0", "2024-06-01", ReleaseOptions{Scheme: SchemeSemver, CollapsePrereleases: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.ListVersions(); !slices.Equal(got, []string{"unreleased", "2.0.0", "1.9.0"}) {
+		t.Fatalf("versions = %v", got)
+	}
+	v, _ := c.GetVersion("2.0.0")
+	if got := v.Public.Get("fixed"); !slices.Equal(got, []string{"Change", "Shared fix"}) {
+		t.Errorf("fixed = %v, want de-duplicated entries", got)
+	}
+	if got := v.Public.Get("added"); !slices.Equal(got, []string{"Entry"}) {
+		t.Errorf("added = %v", got)
+	}
+	if got := v.Internal.Get("changed"); !slices.Equal(got, []string{"Refactor"}) {
+		t.Errorf("internal = %v", got)
+	}
+}
+
+func TestRelease_CollapsePrereleasesRejectsPrerelease(t *testing.T) {
+	c := prereleaseChangelog()
+	err := c.Release("2.0.0-rc.3", "2024-06-01", ReleaseOptions{CollapsePrereleases: true})
+	if err == nil || !strings.Contains(err.Error(), "itself a pre-release") {
+		t.Errorf("error = %v", err)
+	}
+	if c.GetUnreleased() == nil {
+		t.Error("failed release modified the changelog")
+	}
+}
+
+func TestWithPrereleases(t *testing.T) {
+	c := prereleaseChangelog()
+	if err := c.Release("2.0.0", "2024-06-01"); err != nil {
+		t.Fatal(err)
+	}
+	v, err := c.WithPrereleases("2.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := v.Public.Get("fixed"); !slices.Equal(got, []string{"Change", "Shared fix"}) {
+		t.Errorf("fixed = %v", got)
+	}
+	if orig, _ := c.GetVersion("2.0.0"); orig.Public.Count() != 1 || c.GetVersionCount() != 5 {
+		t.Error("WithPrereleases modified the changelog")
+	}
+}
+
+func TestGetLatestRelease_Precedence(t *testing.T) {
+	tests := map[string]struct {
+		versions []string
+		want     string
+	}{
+		"semver out of order": {[]string{"0.9.0", "0.10.0"}, "0.10.0"},
+		"pre-release newest":  {[]string{"2.0.0-rc.1", "1.9.0"}, "2.0.0-rc.1"},
+		"final beats rc":      {[]string{"2.0.0-rc.1", "2.0.0"}, "2.0.0"},
+		"non-semver":          {[]string{"banana", "2.0.0"}, "banana"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := bumpChangelog("", tt.versions...)
+			if got := c.GetLatestRelease().Version; got != tt.want {
+				t.Errorf("GetLatestRelease = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComparisonLinks_Precedence(t *testing.T) {
+	c := bumpChangelog("", "2.0.0", "2.0.0-rc.2", "2.0.0-rc.1", "1.9.0")
+	got := map[string]string{}
+	for _, l := range comparisonLinks(c, newRepoLinks(nil, "https://github.com/o/r")) {
+		got[l.Label] = strings.TrimPrefix(l.URL, "https://github.com/o/r/compare/")
+	}
+	want := map[string]string{
+		"Unreleased": "v2.0.0...HEAD",
+		"2.0.0":      "v1.9.0...v2.0.0",
+		"2.0.0-rc.2": "v2.0.0-rc.1...v2.0.0-rc.2",
+		"2.0.0-rc.1": "v1.9.0...v2.0.0-rc.1",
+	}
+	for label, url := range want {
+		if got[label] != url {
+			t.Errorf("link %s = %q, want %q", label, got[label], url)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("links = %v", got)
+	}
+}
@@ -0,0 +1,411 @@
+package changelog
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultIndent matches the indentation yaml.Marshal uses for fresh files.
+const defaultIndent = 4
+
+// sourceDoc is the parsed YAML document a Changelog was loaded from.
+// Save reconciles the node tree with the in-memory Changelog and re-encodes
+// it, so comments, quoting and key order survive edits.
+type sourceDoc struct {
+	doc    *yaml.Node
+	raw    []byte
+	indent int
+}
+
+func newSourceDoc(doc *yaml.Node, raw []byte) *sourceDoc {
+	return &sourceDoc{doc: doc, raw: raw, indent: detectIndent(raw)}
+}
+
+// rootNode returns the top-level mapping c was loaded from, or nil.
+func (c *Changelog) rootNode() *yaml.Node {
+	if c.source == nil || len(c.source.doc.Content) == 0 {
+		return nil
+	}
+	return c.source.doc.Content[0]
+}
+
+// render applies c to the retained node tree and returns the new file contents.
+func (s *sourceDoc) render(c *Changelog) ([]byte, error) {
+	if len(s.doc.Content) == 0 || s.doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("source document is not a mapping")
+	}
+	root := s.doc.Content[0]
+	setScalarValue(root, "project", c.Project)
+
+	versions := mappingValue(root, "versions")
+	if versions == nil {
+		versions = &yaml.Node{Kind: yaml.MappingNode}
+		root.Content = append(root.Content, scalarNode("versions"), versions)
+	}
+	if err := syncVersionsNode(versions, c.Versions); err != nil {
+		return nil, err
+	}
+	return s.encode()
+}
+
+// renderVersion applies v to a document whose root is a version mapping, as
+// in a fragment, and returns the new file contents.
+func (s *sourceDoc) renderVersion(v *Version) ([]byte, error) {
+	if len(s.doc.Content) == 0 || s.doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("source document is not a mapping")
+	}
+	if err := syncVersionNode(s.doc.Content[0], v); err != nil {
+		return nil, err
+	}
+	return s.encode()
+}
+
+// encode re-encodes the node tree, restoring the layout of the last contents.
+func (s *sourceDoc) encode() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(s.indent)
+	if err := enc.Encode(s.doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	out := restoreLayout(s.raw, buf.Bytes())
+	s.raw = out
+	return out, nil
+}
+
+// syncVersionsNode rebuilds the versions mapping in slice order, reusing the
+// original key and value nodes of versions that were loaded from the file.
+// A version whose identifier changed (e.g. unreleased → 1.2.0) keeps its nodes
+// and only has its key renamed.
+func syncVersionsNode(node *yaml.Node, versions []Version) error {
+	content := make([]*yaml.Node, 0, 2*len(versions))
+	for i := range versions {
+		v := &versions[i]
+		key, val := v.key, v.node
+		if key == nil {
+			key = versionKeyNode(v.Version)
+		} else if key.Value != v.Version {
+			key.Value = v.Version
+			key.Tag = versionKeyNode(v.Version).Tag
+		}
+		if val == nil || val.Kind != yaml.MappingNode {
+			val = &yaml.Node{Kind: yaml.MappingNode}
+		}
+		if err := syncVersionNode(val, v); err != nil {
+			return fmt.Errorf("version %s: %w", v.Version, err)
+		}
+		v.key, v.node = key, val
+		content = append(content, key, val)
+	}
+	setContent(node, content)
+	return nil
+}
+
+// syncVersionNode updates a version mapping in place: existing keys keep their
+// position, new categories go before "internal", and a new date goes first.
+func syncVersionNode(node *yaml.Node, v *Version) error {
+	var content []*yaml.Node
+	seen := map[string]bool{}
+	internalAt := -1
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, val := node.Content[i], node.Content[i+1]
+		switch key.Value {
+		case "date":
+			if v.Date == "" {
+				continue
+			}
+			setScalar(val, v.Date)
+		case "internal":
+			if v.Internal.IsEmpty() {
+				continue
+			}
+			if err := syncChangesNode(val, v.Internal); err != nil {
+				return err
+			}
+			internalAt = len(content)
+		default:
+			cat, _ := v.Public.Category(key.Value)
+			if len(cat.Entries) == 0 {
+				continue
+			}
+			if err := syncEntriesNode(val, cat); err != nil {
+				return err
+			}
+		}
+		seen[key.Value] = true
+		content = append(content, key, val)
+	}
+
+	added, err := newCategoryNodes(v.Public, seen)
+	if err != nil {
+		return err
+	}
+	if internalAt >= 0 {
+		content = append(content[:internalAt], append(added, content[internalAt:]...)...)
+	} else {
+		content = append(content, added...)
+	}
+
+	if v.Date != "" && !seen["date"] {
+		content = append([]*yaml.Node{scalarNode("date"), scalarNode(v.Date)}, content...)
+	}
+	if !v.Internal.IsEmpty() && !seen["internal"] {
+		internal := &yaml.Node{Kind: yaml.MappingNode}
+		if err := syncChangesNode(internal, v.Internal); err != nil {
+			return err
+		}
+		content = append(content, scalarNode("internal"), internal)
+	}
+
+	setContent(node, content)
+	return nil
+}
+
+// syncChangesNode updates a category mapping (e.g. "internal") in place.
+func syncChangesNode(node *yaml.Node, changes Changes) error {
+	if node.Kind != yaml.MappingNode {
+		*node = yaml.Node{Kind: yaml.MappingNode}
+	}
+	var content []*yaml.Node
+	seen := map[string]bool{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, val := node.Content[i], node.Content[i+1]
+		cat, _ := changes.Category(key.Value)
+		if len(cat.Entries) == 0 {
+			continue
+		}
+		if err := syncEntriesNode(val, cat); err != nil {
+			return err
+		}
+		seen[key.Value] = true
+		content = append(content, key, val)
+	}
+
+	added, err := newCategoryNodes(changes, seen)
+	if err != nil {
+		return err
+	}
+	setContent(node, append(content, added...))
+	return nil
+}
+
+// newCategoryNodes encodes the non-empty categories of changes not yet in seen.
+func newCategoryNodes(changes Changes, seen map[string]bool) ([]*yaml.Node, error) {
+	var nodes []*yaml.Node
+	for _, cat := range changes.Categories {
+		if len(cat.Entries) == 0 || seen[cat.Name] {
+			continue
+		}
+		seq := &yaml.Node{Kind: yaml.SequenceNode}
+		if err := syncEntriesNode(seq, cat); err != nil {
+			return nil, err
+		}
+		seen[cat.Name] = true
+		nodes = append(nodes, scalarNode(cat.Name), seq)
+	}
+	return nodes, nil
+}
+
+// syncEntriesNode rewrites a sequence to hold the category's entries, reusing
+// the original item nodes (and so their comments and quoting) for entries
+// whose text and metadata are unchanged.
+func syncEntriesNode(node *yaml.Node, cat CategoryEntry) error {
+	if node.Kind != yaml.SequenceNode {
+		*node = yaml.Node{Kind: yaml.SequenceNode}
+	}
+	type item struct {
+		node *yaml.Node
+		meta EntryMeta
+	}
+	pool := map[string][]item{}
+	for _, n := range node.Content {
+		if text, meta, err := decodeEntry(n); err == nil {
+			pool[text] = append(pool[text], item{n, meta})
+		}
+	}
+
+	content := make([]*yaml.Node, 0, len(cat.Entries))
+	for i, text := range cat.Entries {
+		meta := cat.MetaAt(i)
+		candidates := pool[text]
+		if n := slices.IndexFunc(candidates, func(c item) bool { return c.meta.Equal(meta) }); n >= 0 {
+			content = append(content, candidates[n].node)
+			pool[text] = slices.Delete(candidates, n, n+1)
+			continue
+		}
+		n, err := encodeEntry(text, meta)
+		if err != nil {
+			return fmt.Errorf("encoding entry %q: %w", text, err)
+		}
+		content = append(content, n)
+	}
+	setContent(node, content)
+	return nil
+}
+
+// setContent replaces a collection's children, switching an empty flow
+// collection ({} or []) to block style once it gains content.
+func setContent(node *yaml.Node, content []*yaml.Node) {
+	if len(node.Content) == 0 && len(content) > 0 {
+		node.Style &^= yaml.FlowStyle
+	}
+	node.Content = content
+}
+
+// mappingValue returns the value node for key in a mapping, or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if i := mappingIndex(node, key); i >= 0 {
+		return node.Content[i+1]
+	}
+	return nil
+}
+
+// mappingKey returns the key node for key in a mapping, or nil.
+func mappingKey(node *yaml.Node, key string) *yaml.Node {
+	if i := mappingIndex(node, key); i >= 0 {
+		return node.Content[i]
+	}
+	return nil
+}
+
+// mappingIndex returns the index of key's node in a mapping's Content, or -1,
+// also when node is nil or not a mapping.
+func mappingIndex(node *yaml.Node, key string) int {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// sequenceItem returns the i-th item of a sequence node, or nil.
+func sequenceItem(node *yaml.Node, i int) *yaml.Node {
+	if node == nil || node.Kind != yaml.SequenceNode || i >= len(node.Content) {
+		return nil
+	}
+	return node.Content[i]
+}
+
+// setScalarValue sets key to value in a mapping, inserting the key first if missing.
+func setScalarValue(node *yaml.Node, key, value string) {
+	if val := mappingValue(node, key); val != nil {
+		setScalar(val, value)
+		return
+	}
+	node.Content = append([]*yaml.Node{scalarNode(key), scalarNode(value)}, node.Content...)
+}
+
+// setScalar changes a scalar's value, keeping its style unless it changed.
+func setScalar(node *yaml.Node, value string) {
+	if node.Kind == yaml.ScalarNode && node.Value == value {
+		return
+	}
+	*node = *scalarNode(value)
+}
+
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+}
+
+// detectIndent returns the indentation width of the first indented line.
+func detectIndent(raw []byte) int {
+	for _, line := range strings.Split(string(raw), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || trimmed == line || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if n := len(line) - len(trimmed); n >= 2 {
+			return n
+		}
+	}
+	return defaultIndent
+}
+
+var commentGap = regexp.MustCompile(`[ \t]+#`)
+
+// layoutKey normalizes a line for matching: trailing whitespace and the
+// spacing before inline comments are not significant.
+func layoutKey(line string) string {
+	return commentGap.ReplaceAllString(strings.TrimRight(line, " \t"), " #")
+}
+
+// restoreLayout re-applies presentation details the YAML encoder drops.
+// Lines of out that are unchanged from raw are emitted verbatim from raw,
+// along with any blank lines that preceded them.
+func restoreLayout(raw, out []byte) []byte {
+	src := splitLines(raw)
+	dst := splitLines(out)
+
+	srcIdx, srcKeys := nonBlank(src)
+	dstIdx, dstKeys := nonBlank(dst)
+
+	var b strings.Builder
+	next := 0 // next dst line to emit
+	for _, op := range diffLines(srcKeys, dstKeys) {
+		if op.Kind == '-' {
+			continue
+		}
+		line := dstIdx[op.B]
+		blankBefore := next < line
+		for ; next < line; next++ {
+			b.WriteString(dst[next] + "\n")
+		}
+		next = line + 1
+
+		if op.Kind == '+' {
+			b.WriteString(dst[line] + "\n")
+			continue
+		}
+		j := srcIdx[op.A]
+		start := j
+		for start > 0 && strings.TrimSpace(src[start-1]) == "" {
+			start--
+		}
+		if blankBefore || j == 0 || start == 0 {
+			start = j
+		}
+		for _, l := range src[start:j] {
+			b.WriteString(strings.TrimRight(l, " \t") + "\n")
+		}
+		b.WriteString(src[j] + "\n")
+	}
+	for ; next < len(dst); next++ {
+		b.WriteString(dst[next] + "\n")
+	}
+	return []byte(b.String())
+}
+
+func splitLines(data []byte) []string {
+	s := strings.TrimRight(string(data), "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+func nonBlank(lines []string) ([]int, []string) {
+	var idx []int
+	var keys []string
+	for i, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		idx = append(idx, i)
+		keys = append(keys, layoutKey(l))
+	}
+	return idx, keys
+}
@@ -0,0 +1,163 @@
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const handEditedYAML = `# Team changelog
+project: demo   # display name
+
+versions:
+  # Work in progress
+  unreleased:
+    added:
+      - "Quoted entry"   # keep this
+      - plain entry
+
+  1.0.0:
+    date: "2024-01-01"
+    fixed:
+      - 'single quoted'
+
+    internal:
+      changed:
+        - Refactor
+`
+
+func saveAndRead(t *testing.T, c *Changelog, path string) string {
+	t.Helper()
+	if err := Save(c, path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func loadHandEdited(t *testing.T) (*Changelog, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "CHANGELOG.yaml")
+	if err := os.WriteFile(path, []byte(handEditedYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return c, path
+}
+
+func TestSave_UnchangedIsByteIdentical(t *testing.T) {
+	c, path := loadHandEdited(t)
+	if got := saveAndRead(t, c, path); got != handEditedYAML {
+		t.Errorf("unchanged save rewrote file:\n%s", got)
+	}
+}
+
+func TestSave_AppendPreservesLayout(t *testing.T) {
+	c, path := loadHandEdited(t)
+	c.GetUnreleased().Public.Append("added", "New: thing")
+	c.GetUnreleased().Public.Append("security", "Patch CVE")
+
+	want := strings.Replace(handEditedYAML, "      - plain entry\n",
+		"      - plain entry\n      - 'New: thing'\n    security:\n      - Patch CVE\n", 1)
+	if got := saveAndRead(t, c, path); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSave_ReleaseRenamesKey(t *testing.T) {
+	c, path := loadHandEdited(t)
+	if err := c.Release("1.1.0", "2024-02-01"); err != nil {
+		t.Fatal(err)
+	}
+	got := saveAndRead(t, c, path)
+
+	for _, want := range []string{
+		"  unreleased: {}\n  # Work in progress\n  1.1.0:\n    date: 2024-02-01\n    added:\n",
+		`      - "Quoted entry"   # keep this`,
+		"\n\n  1.0.0:\n",
+		"\n\n    internal:\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+	if _, err := LoadFromReader(strings.NewReader(got)); err != nil {
+		t.Errorf("result does not reload: %v", err)
+	}
+}
+
+func TestSave_RemoveDropsEmptyCategory(t *testing.T) {
+	c, path := loadHandEdited(t)
+	v, _ := c.GetVersion("1.0.0")
+	if _, err := v.Internal.Remove("changed", "Refactor", false); err != nil {
+		t.Fatal(err)
+	}
+	got := saveAndRead(t, c, path)
+	if strings.Contains(got, "internal") {
+		t.Errorf("expected empty internal block to be dropped:\n%s", got)
+	}
+	if !strings.Contains(got, "# Team changelog\n") {
+		t.Errorf("expected header comment to survive:\n%s", got)
+	}
+}
+
+func TestSave_KeepsTwoSpaceIndent(t *testing.T) {
+	c, path := loadHandEdited(t)
+	c.GetUnreleased().Public.Append("fixed", "Bug")
+	got := saveAndRead(t, c, path)
+	if !strings.Contains(got, "\n    fixed:\n      - Bug\n") {
+		t.Errorf("expected 2-space indentation for new category:\n%s", got)
+	}
+}
+
+func TestSave_NewChangelogUsesCanonicalMarshal(t *testing.T) {
+	c := &Changelog{Project: "fresh", Versions: []Version{{Version: "unreleased"}}}
+	got := saveAndRead(t, c, filepath.Join(t.TempDir(), "c.yaml"))
+	if got != "project: fresh\nversions:\n    unreleased: {}\n" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestDetectIndent(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		want  int
+	}{
+		"two spaces":  {"a:\n  b: 1\n", 2},
+		"four spaces": {"a:\n    b: 1\n", 4},
+		"comments":    {"# x\na:\n  # y\n   b: 1\n", 3},
+		"flat":        {"a: 1\n", defaultIndent},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := detectIndent([]byte(tt.input)); got != tt.want {
+				t.Errorf("detectIndent = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSave_StructuredEntryRoundTrip(t *testing.T) {
+	input := "project: demo\nversions:\n  unreleased:\n    added:\n      # linked\n      - text: Pagination\n        pr: 42\n"
+	path := filepath.Join(t.TempDir(), "CHANGELOG.yaml")
+	if err := os.WriteFile(path, []byte(input), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.GetUnreleased().Public.AppendWithMeta("added", "Sorting", EntryMeta{Issues: []string{"9"}})
+
+	want := input + "      - text: Sorting\n        issues:\n          - \"9\"\n"
+	if got := saveAndRead(t, c, path); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
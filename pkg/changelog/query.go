@@ -22,7 +22,12 @@ func (c *Changelog) GetUnreleased() *Version {
 }
 
 // GetLatestRelease returns the most recent released version (skipping unreleased).
+// When every release is a semantic version, the one with the highest
+// precedence wins regardless of file order; otherwise the first in the file.
 func (c *Changelog) GetLatestRelease() *Version {
+	if rels, ok := c.semverReleases(); ok && len(rels) > 0 {
+		return highestRelease(rels, func(SemVer) bool { return true })
+	}
 	for i := range c.Versions {
 		if !c.Versions[i].IsUnreleased() {
 			return &c.Versions[i]
@@ -102,11 +107,12 @@ func flattenChanges(v *Version, includeInternal bool) []Entry {
 	}
 	var entries []Entry
 	for _, cat := range changes.Categories {
-		for _, text := range cat.Entries {
+		for i, text := range cat.Entries {
 			entries = append(entries, Entry{
-				Text:     text,
-				Category: cat.Name,
-				Version:  v.Version,
+				Text:      text,
+				Category:  cat.Name,
+				Version:   v.Version,
+				EntryMeta: cat.MetaAt(i),
 			})
 		}
 	}
@@ -2,9 +2,25 @@ package changelog
 
 import "fmt"
 
+// ReleaseOptions controls how Release checks the new version.
+type ReleaseOptions struct {
+	// Scheme, when not SchemeNone, requires the version to parse under the
+	// scheme and to be greater than the latest release.
+	Scheme VersionScheme
+	// CollapsePrereleases folds the entries of the release's pre-releases
+	// (e.g. 2.0.0-rc.1 for 2.0.0) into it and removes their blocks.
+	// The version must be a final semantic version.
+	CollapsePrereleases bool
+}
+
 // Release promotes the unreleased version to a named release with the given
 // version string and date (YYYY-MM-DD), then prepends a fresh unreleased block.
-func (c *Changelog) Release(version, date string) error {
+func (c *Changelog) Release(version, date string, opts ...ReleaseOptions) error {
+	var opt ReleaseOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
 	unreleased := c.GetUnreleased()
 	if unreleased == nil {
 		return fmt.Errorf("no unreleased version found")
@@ -18,12 +34,51 @@ func (c *Changelog) Release(version, date string) error {
 		return fmt.Errorf("version %q already exists", version)
 	}
 
+	if err := c.checkReleaseOrder(version, opt.Scheme); err != nil {
+		return err
+	}
+	if opt.CollapsePrereleases {
+		// Fail before modifying anything if the version cannot be collapsed into.
+		if _, err := c.PrereleasesOf(version); err != nil {
+			return fmt.Errorf("collapsing pre-releases: %w", err)
+		}
+	}
+
 	unreleased.Version = version
 	unreleased.Date = date
+	c.consumeFragments()
 
 	c.Versions = append([]Version{{
 		Version: "unreleased",
 	}}, c.Versions...)
 
+	if opt.CollapsePrereleases {
+		if _, err := c.CollapsePrereleases(version); err != nil {
+			return fmt.Errorf("collapsing pre-releases: %w", err)
+		}
+	}
+	return nil
+}
+
+// checkReleaseOrder rejects a version that is invalid under the scheme or not
+// greater than the latest release.
+func (c *Changelog) checkReleaseOrder(version string, scheme VersionScheme) error {
+	if scheme == SchemeNone || scheme == "" {
+		return nil
+	}
+	if err := scheme.Check(version); err != nil {
+		return err
+	}
+	latest := c.GetLatestRelease()
+	if latest == nil {
+		return nil
+	}
+	order, err := scheme.Compare(version, latest.Version)
+	if err != nil {
+		return fmt.Errorf("comparing with latest release: %w", err)
+	}
+	if order <= 0 {
+		return fmt.Errorf("version %q is not greater than the latest release %q", version, latest.Version)
+	}
 	return nil
 }
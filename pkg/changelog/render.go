@@ -3,6 +3,7 @@ package changelog
 import (
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 	"unicode"
 )
@@ -11,36 +12,25 @@ import (
 type RenderOptions struct {
 	IncludeInternal bool
 	Config          *Config
+	// Template overrides the built-in markdown template.
+	Template *Template
 }
 
-// RenderMarkdown writes a full Keep a Changelog-compliant markdown document.
+func (o RenderOptions) template() *Template {
+	if o.Template != nil {
+		return o.Template
+	}
+	return DefaultTemplate()
+}
+
+// RenderMarkdown writes a full Keep a Changelog-compliant markdown document,
+// or the output of opts.Template when one is set.
 func RenderMarkdown(c *Changelog, w io.Writer, opts ...RenderOptions) error {
 	var opt RenderOptions
 	if len(opts) > 0 {
 		opt = opts[0]
 	}
-
-	if _, err := fmt.Fprintf(w, "# Changelog\n\n"); err != nil {
-		return err
-	}
-	if _, err := fmt.Fprintf(w, "All notable changes to %s will be documented in this file.\n\n", c.Project); err != nil {
-		return err
-	}
-	if _, err := fmt.Fprintf(w, "The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/).\n\n"); err != nil {
-		return err
-	}
-
-	for _, v := range c.Versions {
-		if err := RenderVersionMarkdown(&v, w, opt); err != nil {
-			return err
-		}
-	}
-
-	if repoURL := ResolveRepoURL(opt.Config); repoURL != "" {
-		renderComparisonLinks(c, w, repoURL)
-	}
-
-	return nil
+	return opt.template().Render(c, w, opt)
 }
 
 // RenderMarkdownString renders the changelog to a string.
@@ -53,45 +43,66 @@ func RenderMarkdownString(c *Changelog, opts ...RenderOptions) (string, error) {
 }
 
 // RenderVersionMarkdown writes a single version as markdown.
+// PR and issue references are linked when opts include a Config.
 func RenderVersionMarkdown(v *Version, w io.Writer, opts ...RenderOptions) error {
 	var opt RenderOptions
 	if len(opts) > 0 {
 		opt = opts[0]
 	}
+	return opt.template().RenderVersion(v, w, opt)
+}
 
-	if v.IsUnreleased() {
-		if _, err := fmt.Fprintf(w, "## [Unreleased]\n\n"); err != nil {
-			return err
-		}
-	} else {
-		if _, err := fmt.Fprintf(w, "## [%s] - %s\n\n", v.Version, v.Date); err != nil {
-			return err
-		}
+// formatEntryMarkdown renders entry text with its metadata, e.g.
+// "**BREAKING:** **api:** Drop v1 ([#12](…/pull/12), [#7](…/issues/7)) by alice".
+func formatEntryMarkdown(text string, meta EntryMeta, links repoLinks) string {
+	var b strings.Builder
+	if meta.Breaking {
+		b.WriteString("**BREAKING:** ")
+	}
+	if meta.Scope != "" {
+		fmt.Fprintf(&b, "**%s:** ", meta.Scope)
 	}
+	b.WriteString(text)
 
-	changes := v.Public
-	if opt.IncludeInternal {
-		changes = v.MergedChanges()
+	var refs []string
+	if meta.PR > 0 {
+		refs = append(refs, referenceLink(links, "pull", strconv.Itoa(meta.PR)))
 	}
+	for _, issue := range meta.Issues {
+		refs = append(refs, referenceLink(links, "issues", issue))
+	}
+	if len(refs) > 0 {
+		fmt.Fprintf(&b, " (%s)", strings.Join(refs, ", "))
+	}
+	if len(meta.Authors) > 0 {
+		fmt.Fprintf(&b, " by %s", strings.Join(meta.Authors, ", "))
+	}
+	return b.String()
+}
 
-	for _, cat := range changes.Categories {
-		if len(cat.Entries) == 0 {
-			continue
-		}
-		if _, err := fmt.Fprintf(w, "### %s\n\n", titleCase(cat.Name)); err != nil {
-			return err
-		}
-		for _, entry := range cat.Entries {
-			if _, err := fmt.Fprintf(w, "- %s\n", entry); err != nil {
-				return err
-			}
-		}
-		if _, err := fmt.Fprintln(w); err != nil {
-			return err
-		}
+// referenceLink renders a PR or issue reference. Numeric references become
+// "#N", linked when a repo URL is known; other keys (e.g. JIRA-12) are kept as-is.
+func referenceLink(links repoLinks, kind, ref string) string {
+	num := strings.TrimPrefix(ref, "#")
+	if _, err := strconv.Atoi(num); err != nil {
+		return ref
+	}
+	if links.repoURL == "" {
+		return "#" + num
 	}
+	return fmt.Sprintf("[#%s](%s)", num, links.reference(kind, num))
+}
 
-	return nil
+// entryReferences returns the plain PR and issue references of an entry.
+func entryReferences(meta EntryMeta) []string {
+	var refs []string
+	if meta.PR > 0 {
+		refs = append(refs, "#"+strconv.Itoa(meta.PR))
+	}
+	for _, issue := range meta.Issues {
+		refs = append(refs, referenceLink(repoLinks{}, "issues", issue))
+	}
+	return refs
 }
 
 func titleCase(s string) string {
@@ -104,25 +115,61 @@ func titleCase(s string) string {
 }
 
 func renderComparisonLinks(c *Changelog, w io.Writer, repoURL string) {
-	if len(c.Versions) == 0 {
-		return
-	}
-
-	isGitLab := strings.Contains(repoURL, "gitlab")
-	comparePath := "/compare/"
-	if isGitLab {
-		comparePath = "/-/compare/"
+	for _, l := range comparisonLinks(c, newRepoLinks(nil, repoURL)) {
+		_, _ = fmt.Fprintf(w, "[%s]: %s\n", l.Label, l.URL)
 	}
+}
 
+// comparisonLinks builds the compare link definitions for each version that
+// has a released predecessor, using the forge's URL shape and tag names.
+func comparisonLinks(c *Changelog, links repoLinks) []CompareLink {
+	var out []CompareLink
 	for i, v := range c.Versions {
+		prev := c.previousRelease(i)
+		if prev == nil {
+			continue
+		}
+		to := v.Version
 		if v.IsUnreleased() {
-			if i+1 < len(c.Versions) {
-				prev := c.Versions[i+1].Version
-				_, _ = fmt.Fprintf(w, "[Unreleased]: %s%sv%s...HEAD\n", repoURL, comparePath, prev)
+			to = ""
+		}
+		if url := links.compare(prev.Version, to); url != "" {
+			out = append(out, CompareLink{Label: versionLabel(&v), URL: url})
+		}
+	}
+	return out
+}
+
+// RenderDiffMarkdown renders a changelog diff as Markdown, e.g. for a pull
+// request comment: a section per version and a bullet per change.
+func RenderDiffMarkdown(d *ChangelogDiff) string {
+	if d.IsEmpty() {
+		return "No changelog changes.\n"
+	}
+	var b strings.Builder
+	for i, vd := range d.Versions {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "### %s (%s)\n\n", vd.Version, vd.Status)
+		if vd.OldDate != vd.NewDate {
+			fmt.Fprintf(&b, "- **date** changed: %s\n", describeChange(vd.OldDate, vd.NewDate))
+		}
+		for _, cd := range vd.Categories {
+			for _, e := range cd.Added {
+				fmt.Fprintf(&b, "- **%s** added: %s\n", cd.label(), e.Text)
+			}
+			for _, e := range cd.Removed {
+				fmt.Fprintf(&b, "- **%s** removed: ~~%s~~\n", cd.label(), e.Text)
+			}
+			for _, c := range cd.Modified {
+				if c.Old.Text == c.New.Text {
+					fmt.Fprintf(&b, "- **%s** metadata changed: %s\n", cd.label(), c.New.Text)
+				} else {
+					fmt.Fprintf(&b, "- **%s** changed: ~~%s~~ → %s\n", cd.label(), c.Old.Text, c.New.Text)
+				}
 			}
-		} else if i+1 < len(c.Versions) && !c.Versions[i+1].IsUnreleased() {
-			prev := c.Versions[i+1].Version
-			_, _ = fmt.Fprintf(w, "[%s]: %s%sv%s...v%s\n", v.Version, repoURL, comparePath, prev, v.Version)
 		}
 	}
+	return b.String()
 }
@@ -191,3 +191,51 @@ func TestRenderVersionMarkdown_EmptyChanges(t *testing.T) {
 		t.Error("should not render any category sections for empty changes")
 	}
 }
+
+func TestFormatEntryMarkdown(t *testing.T) {
+	tests := map[string]struct {
+		meta    EntryMeta
+		repoURL string
+		want    string
+	}{
+		"plain": {want: "Fix bug"},
+		"pr without repo": {
+			meta: EntryMeta{PR: 12},
+			want: "Fix bug (#12)",
+		},
+		"github links": {
+			meta:    EntryMeta{PR: 12, Issues: []string{"#7", "JIRA-3"}},
+			repoURL: "https://github.com/o/r",
+			want:    "Fix bug ([#12](https://github.com/o/r/pull/12), [#7](https://github.com/o/r/issues/7), JIRA-3)",
+		},
+		"gitlab links": {
+			meta:    EntryMeta{PR: 5, Issues: []string{"9"}},
+			repoURL: "https://gitlab.com/o/r",
+			want:    "Fix bug ([#5](https://gitlab.com/o/r/-/merge_requests/5), [#9](https://gitlab.com/o/r/-/issues/9))",
+		},
+		"scope breaking authors": {
+			meta: EntryMeta{Scope: "api", Breaking: true, Authors: []string{"alice", "bob"}},
+			want: "**BREAKING:** **api:** Fix bug by alice, bob",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := formatEntryMarkdown("Fix bug", tt.meta, newRepoLinks(nil, tt.repoURL)); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderVersionMarkdown_LinksWithConfig(t *testing.T) {
+	v := &Version{Version: "1.0.0", Date: "2024-01-01"}
+	v.Public.AppendWithMeta("fixed", "Crash", EntryMeta{PR: 8})
+	var b strings.Builder
+	cfg := &Config{RepoURL: "https://github.com/o/r"}
+	if err := RenderVersionMarkdown(v, &b, RenderOptions{Config: cfg}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "- Crash ([#8](https://github.com/o/r/pull/8))\n") {
+		t.Errorf("unexpected output:\n%s", b.String())
+	}
+}
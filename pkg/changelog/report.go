@@ -0,0 +1,315 @@
+package changelog
+
+import (
+	"cmp"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Rule IDs name each validation and check in reports. They are stable
+// across releases, so CI baselines and suppressions keep working.
+const (
+	RuleParseError         = "parse-error"
+	RuleEmptyProject       = "empty-project"
+	RuleEmptyVersion       = "empty-version"
+	RuleDuplicateVersion   = "duplicate-version"
+	RuleMultipleUnreleased = "multiple-unreleased"
+	RuleMissingDate        = "missing-date"
+	RuleBadDate            = "bad-date"
+	RuleEmptyRelease       = "empty-release"
+	RuleUnknownCategory    = "unknown-category"
+	RuleEmptyEntry         = "empty-entry"
+	RuleBadPR              = "bad-pr"
+	RuleBadConfig          = "bad-config"
+	RuleBadVersion         = "bad-version"
+	RuleVersionOrder       = "version-order"
+	RuleDateOrder          = "date-order"
+	RuleOutOfSync          = "out-of-sync"
+	RuleMissingOutput      = "missing-output"
+
+	// Lint rules, see Lint.
+	RuleWhitespace          = "whitespace"
+	RuleCommitPrefix        = "commit-prefix"
+	RuleRepeatedWord        = "repeated-word"
+	RuleTense               = "tense"
+	RuleCapitalization      = "capitalization"
+	RuleTrailingPunctuation = "trailing-punctuation"
+	RuleMaxLength           = "max-length"
+	RuleMinLength           = "min-length"
+	RuleBannedPattern       = "banned-pattern"
+	RuleCommitHash          = "commit-hash"
+	RuleDuplicateEntry      = "duplicate-entry"
+)
+
+var ruleDescriptions = map[string]string{
+	RuleParseError:         "CHANGELOG.yaml must be valid YAML in the chlog schema",
+	RuleEmptyProject:       "project must not be empty",
+	RuleEmptyVersion:       "version keys must not be empty",
+	RuleDuplicateVersion:   "each version may appear only once",
+	RuleMultipleUnreleased: "only one unreleased version is allowed",
+	RuleMissingDate:        "released versions need a date",
+	RuleBadDate:            "dates must be YYYY-MM-DD",
+	RuleEmptyRelease:       "released versions need at least one entry",
+	RuleUnknownCategory:    "categories must be in the allowed list",
+	RuleEmptyEntry:         "entries must not be empty",
+	RuleBadPR:              "pr must be a positive number",
+	RuleBadConfig:          ".chlog.yaml settings must be valid",
+	RuleBadVersion:         "versions must follow the configured version scheme",
+	RuleVersionOrder:       "versions must be listed newest first",
+	RuleDateOrder:          "dates must not go backwards",
+	RuleOutOfSync:          "rendered markdown must match CHANGELOG.yaml",
+	RuleMissingOutput:      "rendered markdown must exist",
+
+	RuleWhitespace:          "entries must not have leading, trailing or repeated whitespace",
+	RuleCommitPrefix:        "entries must not start with a commit message prefix like \"feat:\"",
+	RuleRepeatedWord:        "entries must not repeat a word, as in \"Added Added\"",
+	RuleTense:               "entries must start with a verb in the configured tense",
+	RuleCapitalization:      "entries must start with a capital letter",
+	RuleTrailingPunctuation: "entries must follow the trailing punctuation policy",
+	RuleMaxLength:           "entries must not exceed the maximum length",
+	RuleMinLength:           "entries must be long enough to describe the change",
+	RuleBannedPattern:       "entries must not match a banned pattern",
+	RuleCommitHash:          "entries must not contain raw commit hashes",
+	RuleDuplicateEntry:      "a version must not repeat an entry",
+}
+
+// RuleDescription returns a one-line description of a rule ID.
+func RuleDescription(id string) string {
+	return ruleDescriptions[id]
+}
+
+// Diagnostic is one finding of validate or check, located in a file.
+type Diagnostic struct {
+	Rule    string `json:"rule"`
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// Location returns "file:line:col", or less when the position is unknown.
+func (d Diagnostic) Location() string {
+	switch {
+	case d.Line > 0 && d.Column > 0:
+		return fmt.Sprintf("%s:%d:%d", d.File, d.Line, d.Column)
+	case d.Line > 0:
+		return fmt.Sprintf("%s:%d", d.File, d.Line)
+	}
+	return d.File
+}
+
+func (d Diagnostic) String() string {
+	return d.Location() + ": " + d.text()
+}
+
+// text is the message prefixed with the field, if any.
+func (d Diagnostic) text() string {
+	if d.Field != "" {
+		return d.Field + ": " + d.Message
+	}
+	return d.Message
+}
+
+var yamlErrorLine = regexp.MustCompile(`line (\d+)`)
+
+// DiagnosticsFromError converts the error of loading file into diagnostics:
+// one per ValidationError, or a single parse-error for anything else. A nil
+// error yields none.
+func DiagnosticsFromError(file string, err error) []Diagnostic {
+	if err == nil {
+		return nil
+	}
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) {
+		d := Diagnostic{Rule: RuleParseError, File: file, Message: err.Error()}
+		if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+			d.Line, _ = strconv.Atoi(m[1])
+		}
+		return []Diagnostic{d}
+	}
+	diags := make([]Diagnostic, len(verrs))
+	for i, e := range verrs {
+		diags[i] = Diagnostic{Rule: e.Rule, File: cmp.Or(e.File, file), Line: e.Line, Column: e.Column, Field: e.Field, Message: e.Message}
+	}
+	return diags
+}
+
+// ReportFormat selects how WriteReport renders diagnostics.
+type ReportFormat string
+
+const (
+	ReportText   ReportFormat = "text"
+	ReportJSON   ReportFormat = "json"
+	ReportSARIF  ReportFormat = "sarif"
+	ReportJUnit  ReportFormat = "junit"
+	ReportGitHub ReportFormat = "github"
+)
+
+// ParseReportFormat validates a --format value.
+func ParseReportFormat(s string) (ReportFormat, error) {
+	f := ReportFormat(s)
+	if !slices.Contains([]ReportFormat{ReportText, ReportJSON, ReportSARIF, ReportJUnit, ReportGitHub}, f) {
+		return "", fmt.Errorf("unknown format %q (valid: text, json, sarif, junit, github)", s)
+	}
+	return f, nil
+}
+
+// WriteReport writes the diagnostics of a chlog command ("validate" or
+// "check") in the given format. An empty list reports a passing run.
+func WriteReport(w io.Writer, format ReportFormat, command string, diags []Diagnostic) error {
+	switch format {
+	case ReportJSON:
+		return writeJSONReport(w, command, diags)
+	case ReportSARIF:
+		return writeSARIF(w, diags)
+	case ReportJUnit:
+		return writeJUnit(w, command, diags)
+	case ReportGitHub:
+		for _, d := range diags {
+			if _, err := fmt.Fprintln(w, GitHubAnnotation("error", d)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, d := range diags {
+		if _, err := fmt.Fprintln(w, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeJSONReport(w io.Writer, command string, diags []Diagnostic) error {
+	if diags == nil {
+		diags = []Diagnostic{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Command     string       `json:"command"`
+		Passed      bool         `json:"passed"`
+		Diagnostics []Diagnostic `json:"diagnostics"`
+	}{command, len(diags) == 0, diags})
+}
+
+// GitHubAnnotation formats d as a GitHub Actions workflow command, e.g.
+// "::error file=CHANGELOG.yaml,line=3,col=5,title=bad-date::message".
+func GitHubAnnotation(level string, d Diagnostic) string {
+	props := "file=" + escapeAnnotation(d.File, true)
+	if d.Line > 0 {
+		props += fmt.Sprintf(",line=%d", d.Line)
+	}
+	if d.Column > 0 {
+		props += fmt.Sprintf(",col=%d", d.Column)
+	}
+	if d.Rule != "" {
+		props += ",title=" + escapeAnnotation(d.Rule, true)
+	}
+	return fmt.Sprintf("::%s %s::%s", level, props, escapeAnnotation(d.text(), false))
+}
+
+// escapeAnnotation escapes workflow command data; properties also escape
+// ":" and ",".
+func escapeAnnotation(s string, property bool) string {
+	s = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+	if property {
+		s = strings.NewReplacer(":", "%3A", ",", "%2C").Replace(s)
+	}
+	return s
+}
+
+// writeSARIF writes a SARIF 2.1.0 log with one result per diagnostic.
+func writeSARIF(w io.Writer, diags []Diagnostic) error {
+	type object = map[string]any
+	rules, results := []object{}, []object{}
+	seen := map[string]bool{}
+	for _, d := range diags {
+		if !seen[d.Rule] {
+			seen[d.Rule] = true
+			rules = append(rules, object{"id": d.Rule, "shortDescription": object{"text": RuleDescription(d.Rule)}})
+		}
+		loc := object{"artifactLocation": object{"uri": d.File}}
+		if d.Line > 0 {
+			loc["region"] = object{"startLine": d.Line, "startColumn": max(d.Column, 1)}
+		}
+		results = append(results, object{
+			"ruleId":    d.Rule,
+			"level":     "error",
+			"message":   object{"text": d.text()},
+			"locations": []object{{"physicalLocation": loc}},
+		})
+	}
+	driver := object{"name": "chlog", "informationUri": "https://github.com/ariel-frischer/chlog", "rules": rules}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(object{
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"version": "2.1.0",
+		"runs":    []object{{"tool": object{"driver": driver}, "results": results}},
+	})
+}
+
+type junitSuites struct {
+	XMLName  xml.Name   `xml:"testsuites"`
+	Name     string     `xml:"name,attr"`
+	Tests    int        `xml:"tests,attr"`
+	Failures int        `xml:"failures,attr"`
+	Suites   []junitRun `xml:"testsuite"`
+}
+
+type junitRun struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Type    string `xml:"type,attr"`
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit writes a JUnit XML report with a failing test case per
+// diagnostic, or a single passing case when there are none.
+func writeJUnit(w io.Writer, command string, diags []Diagnostic) error {
+	run := junitRun{Name: "chlog " + command, Failures: len(diags)}
+	for _, d := range diags {
+		run.Cases = append(run.Cases, junitCase{
+			Name:      d.Rule + " " + d.Location(),
+			ClassName: "chlog." + command,
+			Failure:   &junitFailure{Type: d.Rule, Message: d.Message, Text: d.String()},
+		})
+	}
+	if len(diags) == 0 {
+		run.Cases = []junitCase{{Name: command, ClassName: "chlog." + command}}
+	}
+	run.Tests = len(run.Cases)
+	out := junitSuites{Name: "chlog", Tests: run.Tests, Failures: run.Failures, Suites: []junitRun{run}}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return fmt.Errorf("encoding JUnit report: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
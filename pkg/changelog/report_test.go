@@ -0,0 +1,187 @@
+package changelog
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var testDiagnostics = []Diagnostic{
+	{Rule: RuleBadDate, File: "CHANGELOG.yaml", Line: 10, Column: 11, Field: "versions[1].date", Message: "must be YYYY-MM-DD"},
+	{Rule: RuleOutOfSync, File: "CHANGELOG.md", Line: 4, Message: "CHANGELOG.md is out of sync"},
+}
+
+func TestDiagnosticsFromError(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want []string
+	}{
+		"nil": {},
+		"validation errors": {
+			err: ValidationErrors{
+				{Rule: RuleEmptyProject, Field: "project", Message: "must not be empty", Line: 1, Column: 10},
+				{Rule: RuleMissingDate, Field: "versions[1].date", Message: "required"},
+			},
+			want: []string{"empty-project CHANGELOG.yaml:1:10: project: must not be empty", "missing-date CHANGELOG.yaml: versions[1].date: required"},
+		},
+		"yaml error": {
+			err:  errors.New("parsing YAML: yaml: line 7: did not find expected key"),
+			want: []string{"parse-error CHANGELOG.yaml:7: parsing YAML: yaml: line 7: did not find expected key"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for _, d := range DiagnosticsFromError("CHANGELOG.yaml", tt.err) {
+				got = append(got, d.Rule+" "+d.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestDiagnosticsFromError_DuplicateKey(t *testing.T) {
+	_, err := LoadFromReader(strings.NewReader("project: demo\nversions:\n  1.0.0:\n    date: 2024-01-01\n    added:\n      - A\n  1.0.0:\n    date: 2024-01-01\n    added:\n      - B\n"))
+	diags := DiagnosticsFromError("CHANGELOG.yaml", err)
+	want := Diagnostic{Rule: RuleDuplicateVersion, File: "CHANGELOG.yaml", Line: 7, Column: 3, Field: "versions[1].version", Message: `duplicate version "1.0.0"`}
+	if len(diags) != 1 || diags[0] != want {
+		t.Fatalf("diagnostics = %+v, want %+v", diags, want)
+	}
+
+	var b bytes.Buffer
+	if err := WriteReport(&b, ReportGitHub, "validate", diags); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.String(); !strings.HasPrefix(got, "::error file=CHANGELOG.yaml,line=7,col=3,title=duplicate-version::") {
+		t.Errorf("annotation = %q", got)
+	}
+}
+
+func TestParseReportFormat(t *testing.T) {
+	if f, err := ParseReportFormat("sarif"); err != nil || f != ReportSARIF {
+		t.Errorf("ParseReportFormat(sarif) = %q, %v", f, err)
+	}
+	if _, err := ParseReportFormat("xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestGitHubAnnotation(t *testing.T) {
+	tests := map[string]struct {
+		diag Diagnostic
+		want string
+	}{
+		"positioned": {
+			diag: testDiagnostics[0],
+			want: "::error file=CHANGELOG.yaml,line=10,col=11,title=bad-date::versions[1].date: must be YYYY-MM-DD",
+		},
+		"escaped": {
+			diag: Diagnostic{File: "a,b:c.md", Message: "50% done\nnext"},
+			want: "::error file=a%2Cb%3Ac.md::50%25 done%0Anext",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := GitHubAnnotation("error", tt.diag); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteReport_JSON(t *testing.T) {
+	for _, diags := range [][]Diagnostic{nil, testDiagnostics} {
+		var buf bytes.Buffer
+		if err := WriteReport(&buf, ReportJSON, "check", diags); err != nil {
+			t.Fatal(err)
+		}
+		var got struct {
+			Command     string       `json:"command"`
+			Passed      bool         `json:"passed"`
+			Diagnostics []Diagnostic `json:"diagnostics"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+		}
+		if got.Command != "check" || got.Passed != (len(diags) == 0) || len(got.Diagnostics) != len(diags) {
+			t.Errorf("unexpected report: %+v", got)
+		}
+	}
+}
+
+func TestWriteReport_SARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteReport(&buf, ReportSARIF, "validate", testDiagnostics); err != nil {
+		t.Fatal(err)
+	}
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine   int `json:"startLine"`
+							StartColumn int `json:"startColumn"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log: %s", buf.String())
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 2 || len(run.Results) != 2 {
+		t.Fatalf("want 2 rules and results, got %s", buf.String())
+	}
+	loc := run.Results[1].Locations[0].PhysicalLocation
+	if run.Results[1].RuleID != RuleOutOfSync || loc.ArtifactLocation.URI != "CHANGELOG.md" || loc.Region.StartLine != 4 || loc.Region.StartColumn != 1 {
+		t.Errorf("unexpected result: %+v", run.Results[1])
+	}
+}
+
+func TestWriteReport_JUnit(t *testing.T) {
+	tests := map[string]struct {
+		diags          []Diagnostic
+		tests, failing int
+	}{
+		"passing": {tests: 1},
+		"failing": {diags: testDiagnostics, tests: 2, failing: 2},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteReport(&buf, ReportJUnit, "check", tt.diags); err != nil {
+				t.Fatal(err)
+			}
+			var got junitSuites
+			if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+			}
+			if got.Tests != tt.tests || got.Failures != tt.failing || len(got.Suites) != 1 || len(got.Suites[0].Cases) != tt.tests {
+				t.Errorf("unexpected report:\n%s", buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package changelog
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DefaultSkipLabel is the pull request label, and the commit trailer key,
+// that waives the changelog requirement.
+const DefaultSkipLabel = "skip-changelog"
+
+// RequireConfig selects which changed paths require a changelog entry in
+// `chlog check --changed-since`.
+type RequireConfig struct {
+	// Include lists globs of paths that need an entry; empty means all paths.
+	Include []string `yaml:"include,omitempty"`
+	// Exclude lists globs of exempt paths, e.g. "docs/**" or "*_test.go".
+	Exclude []string `yaml:"exclude,omitempty"`
+	// SkipLabel overrides DefaultSkipLabel.
+	SkipLabel string `yaml:"skip_label,omitempty"`
+}
+
+// SkipLabelName returns SkipLabel if set, otherwise the default.
+func (r RequireConfig) SkipLabelName() string {
+	if r.SkipLabel != "" {
+		return r.SkipLabel
+	}
+	return DefaultSkipLabel
+}
+
+// RequiresEntry reports whether a change to path needs a changelog entry:
+// it matches an Include glob (or Include is empty) and no Exclude glob.
+func (r RequireConfig) RequiresEntry(path string) bool {
+	if len(r.Include) > 0 && !matchAny(r.Include, path) {
+		return false
+	}
+	return !matchAny(r.Exclude, path)
+}
+
+func matchAny(patterns []string, path string) bool {
+	for _, p := range patterns {
+		if MatchGlob(p, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchGlob matches a slash-separated path against a glob where "*" and "?"
+// stay within a path segment and "**" spans segments. As in .gitignore, a
+// pattern without a slash matches the file name at any depth.
+func MatchGlob(pattern, path string) bool {
+	if !strings.Contains(pattern, "/") {
+		pattern = "**/" + pattern
+	}
+	var re strings.Builder
+	re.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			re.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			re.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			re.WriteString("[^/]*")
+		case pattern[i] == '?':
+			re.WriteString("[^/]")
+		default:
+			re.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	re.WriteString("$")
+	return regexp.MustCompile(re.String()).MatchString(path)
+}
+
+// HasSkipTrailer reports whether any commit message carries a trailer such as
+// "Skip-Changelog: true" for the given key, matched case-insensitively.
+// A value of "false" or "no" does not count.
+func HasSkipTrailer(messages, key string) bool {
+	for _, line := range strings.Split(messages, "\n") {
+		k, v, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok || !strings.EqualFold(strings.TrimSpace(k), key) {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "false", "no":
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// UnreleasedAdditions returns the public and internal entries of head's
+// unreleased version whose category and text are not in base's.
+func UnreleasedAdditions(base, head *Changelog) []Entry {
+	u := head.GetUnreleased()
+	if u == nil {
+		return nil
+	}
+	seen := map[[2]string]bool{}
+	if bu := base.GetUnreleased(); bu != nil {
+		for _, e := range flattenChanges(bu, true) {
+			seen[[2]string{e.Category, e.Text}] = true
+		}
+	}
+	var added []Entry
+	for _, e := range flattenChanges(u, true) {
+		if !seen[[2]string{e.Category, e.Text}] {
+			added = append(added, e)
+		}
+	}
+	return added
+}
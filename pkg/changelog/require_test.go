@@ -0,0 +1,93 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := map[string]struct {
+		pattern, path string
+		want          bool
+	}{
+		"basename at root":        {"*.md", "README.md", true},
+		"basename at depth":       {"*.md", "docs/guide/intro.md", true},
+		"star stays in segment":   {"docs/*", "docs/a/b.md", false},
+		"double star spans":       {"docs/**", "docs/a/b.md", true},
+		"double star middle":      {"pkg/**/testdata/*", "pkg/x/y/testdata/f.txt", true},
+		"double star zero dirs":   {"pkg/**/*.go", "pkg/main.go", true},
+		"anchored path":           {"cmd/*.go", "pkg/cmd/main.go", false},
+		"test suffix":             {"*_test.go", "pkg/changelog/require_test.go", true},
+		"question mark":           {"v?.txt", "v1.txt", true},
+		"dot is literal":          {"*.md", "READMEXmd", false},
+		"question mark not slash": {"a?b", "a/b", false},
+		"no match":                {"docs/**", "src/docs/a.md", false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := MatchGlob(tt.pattern, tt.path); got != tt.want {
+				t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireConfig_RequiresEntry(t *testing.T) {
+	r := RequireConfig{Include: []string{"pkg/**", "cmd/**"}, Exclude: []string{"*_test.go"}}
+	tests := map[string]bool{
+		"pkg/changelog/types.go":      true,
+		"pkg/changelog/types_test.go": false,
+		"docs/guide.md":               false,
+	}
+	for path, want := range tests {
+		if got := r.RequiresEntry(path); got != want {
+			t.Errorf("RequiresEntry(%q) = %v, want %v", path, got, want)
+		}
+	}
+	if !(RequireConfig{}).RequiresEntry("anything") {
+		t.Error("empty config should require an entry for every path")
+	}
+}
+
+func TestHasSkipTrailer(t *testing.T) {
+	tests := map[string]struct {
+		messages string
+		want     bool
+	}{
+		"trailer":         {"Fix typo\n\nSkip-Changelog: true\n", true},
+		"lowercase key":   {"Fix typo\n\nskip-changelog: yes\n", true},
+		"disabled":        {"Fix typo\n\nSkip-Changelog: false\n", false},
+		"no trailer":      {"Fix typo\n\nRefs: #12\n", false},
+		"mention in body": {"Do not add skip-changelog here\n", false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := HasSkipTrailer(tt.messages, DefaultSkipLabel); got != tt.want {
+				t.Errorf("HasSkipTrailer = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnreleasedAdditions(t *testing.T) {
+	load := func(yaml string) *Changelog {
+		c, err := LoadFromReader(strings.NewReader(yaml))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	base := load("project: demo\nversions:\n  unreleased:\n    added:\n      - Old\n")
+	head := load("project: demo\nversions:\n  unreleased:\n    added:\n      - Old\n    internal:\n      changed:\n        - Refactor\n")
+
+	added := UnreleasedAdditions(base, head)
+	if len(added) != 1 || added[0].Text != "Refactor" || added[0].Category != "changed" {
+		t.Errorf("added = %+v", added)
+	}
+	if got := UnreleasedAdditions(head, base); len(got) != 0 {
+		t.Errorf("removal reported as addition: %+v", got)
+	}
+	if got := UnreleasedAdditions(&Changelog{}, base); len(got) != 1 {
+		t.Errorf("from empty base = %+v", got)
+	}
+}
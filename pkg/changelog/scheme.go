@@ -0,0 +1,175 @@
+package changelog
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// VersionScheme selects how version keys are parsed and ordered.
+type VersionScheme string
+
+const (
+	// SchemeNone accepts any version string and does not enforce ordering.
+	SchemeNone VersionScheme = "none"
+	// SchemeSemver requires Semantic Versioning 2.0.0 keys, e.g. "1.2.3".
+	SchemeSemver VersionScheme = "semver"
+	// SchemeCalver requires calendar versions, e.g. "2024.06" or "24.6.1".
+	SchemeCalver VersionScheme = "calver"
+)
+
+// ParseVersionScheme converts a config value to a VersionScheme.
+// An empty string means SchemeNone.
+func ParseVersionScheme(s string) (VersionScheme, error) {
+	switch VersionScheme(s) {
+	case "", SchemeNone:
+		return SchemeNone, nil
+	case SchemeSemver, SchemeCalver:
+		return VersionScheme(s), nil
+	}
+	return "", fmt.Errorf("unknown version scheme %q (valid: semver, calver, none)", s)
+}
+
+// calverRegex matches a 2- or 4-digit year followed by one to three numeric
+// components and an optional "-modifier".
+var calverRegex = regexp.MustCompile(`^v?(\d{2}|\d{4})((?:\.\d+){1,3})(?:-([0-9A-Za-z.-]+))?$`)
+
+// calVer is a parsed calendar version.
+type calVer struct {
+	parts    []int
+	modifier string
+}
+
+func parseCalver(s string) (calVer, error) {
+	m := calverRegex.FindStringSubmatch(s)
+	if m == nil {
+		return calVer{}, fmt.Errorf("invalid calver version %q", s)
+	}
+	var cv calVer
+	for _, p := range strings.Split(m[1]+m[2], ".") {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return calVer{}, fmt.Errorf("invalid calver version %q: %w", s, err)
+		}
+		cv.parts = append(cv.parts, n)
+	}
+	cv.modifier = m[3]
+	return cv, nil
+}
+
+func (v calVer) compare(o calVer) int {
+	if c := slices.Compare(v.parts, o.parts); c != 0 {
+		return c
+	}
+	return comparePrerelease(v.modifier, o.modifier)
+}
+
+// Check returns an error if version is not valid under the scheme.
+// SchemeNone accepts anything.
+func (s VersionScheme) Check(version string) error {
+	switch s {
+	case SchemeSemver:
+		_, err := ParseSemver(version)
+		return err
+	case SchemeCalver:
+		_, err := parseCalver(version)
+		return err
+	}
+	return nil
+}
+
+// Compare orders two versions under the scheme, returning -1, 0 or +1.
+// It fails for SchemeNone and for versions the scheme cannot parse.
+func (s VersionScheme) Compare(a, b string) (int, error) {
+	switch s {
+	case SchemeSemver:
+		va, err := ParseSemver(a)
+		if err != nil {
+			return 0, err
+		}
+		vb, err := ParseSemver(b)
+		if err != nil {
+			return 0, err
+		}
+		return va.Compare(vb), nil
+	case SchemeCalver:
+		va, err := parseCalver(a)
+		if err != nil {
+			return 0, err
+		}
+		vb, err := parseCalver(b)
+		if err != nil {
+			return 0, err
+		}
+		return va.compare(vb), nil
+	}
+	return 0, fmt.Errorf("version scheme %q does not define an order", s)
+}
+
+// validateOrder checks that released versions parse under the scheme, are
+// listed newest first, and have dates that do not go backwards.
+func validateOrder(c *Changelog, scheme VersionScheme) []ValidationError {
+	var errs []ValidationError
+	var prev *Version
+	for i := range c.Versions {
+		v := &c.Versions[i]
+		if v.IsUnreleased() || strings.TrimSpace(v.Version) == "" {
+			continue
+		}
+		prefix := fmt.Sprintf("versions[%d]", i)
+		if err := scheme.Check(v.Version); err != nil {
+			errs = append(errs, ValidationError{Rule: RuleBadVersion, Field: prefix + ".version", Message: err.Error()}.at(v.key))
+			continue
+		}
+		if prev != nil {
+			if order, _ := scheme.Compare(prev.Version, v.Version); order <= 0 {
+				errs = append(errs, ValidationError{
+					Rule:    RuleVersionOrder,
+					Field:   prefix + ".version",
+					Message: fmt.Sprintf("%q must be listed above %q (versions must be in descending order)", v.Version, prev.Version),
+				}.at(v.key))
+			}
+			if dateRegex.MatchString(v.Date) && dateRegex.MatchString(prev.Date) && v.Date > prev.Date {
+				errs = append(errs, ValidationError{
+					Rule:    RuleDateOrder,
+					Field:   prefix + ".date",
+					Message: fmt.Sprintf("date %s is later than %s of newer version %q", v.Date, prev.Date, prev.Version),
+				}.at(mappingValue(v.node, "date")))
+			}
+		}
+		prev = v
+	}
+	return errs
+}
+
+// Sort reorders Versions newest first under the given scheme, keeping the
+// unreleased block at the top. It fails without changes if any released
+// version cannot be parsed.
+func (c *Changelog) Sort(scheme VersionScheme) error {
+	if scheme == SchemeNone || scheme == "" {
+		return fmt.Errorf("cannot sort with version scheme %q", SchemeNone)
+	}
+	for _, v := range c.Versions {
+		if err := scheme.Check(v.Version); err != nil && !v.IsUnreleased() {
+			return err
+		}
+	}
+	slices.SortStableFunc(c.Versions, func(a, b Version) int {
+		if a.IsUnreleased() || b.IsUnreleased() {
+			return cmp.Compare(boolRank(b.IsUnreleased()), boolRank(a.IsUnreleased()))
+		}
+		order, _ := scheme.Compare(b.Version, a.Version)
+		return order
+	})
+	return nil
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
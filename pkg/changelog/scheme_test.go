@@ -0,0 +1,175 @@
+package changelog
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// schemeChangelog builds a changelog with one entry per released version and
+// dates that go back one month per version.
+func schemeChangelog(versions ...string) *Changelog {
+	c := &Changelog{Project: "test", Versions: []Version{{Version: "unreleased"}}}
+	for i, name := range versions {
+		v := Version{Version: name, Date: fmt.Sprintf("2024-%02d-01", 12-i)}
+		v.Public.Append("added", "Entry "+name)
+		c.Versions = append(c.Versions, v)
+	}
+	return c
+}
+
+func TestVersionScheme_Compare(t *testing.T) {
+	tests := map[string]struct {
+		scheme VersionScheme
+		a, b   string
+		want   int
+	}{
+		"semver minor":     {SchemeSemver, "0.10.0", "0.2.0", 1},
+		"semver pre":       {SchemeSemver, "1.0.0-rc.1", "1.0.0", -1},
+		"calver month":     {SchemeCalver, "2024.10", "2024.9", 1},
+		"calver micro":     {SchemeCalver, "24.06.1", "24.6", 1},
+		"calver modifier":  {SchemeCalver, "2024.06-beta", "2024.06", -1},
+		"calver equal pad": {SchemeCalver, "2024.06", "2024.6", 0},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := tt.scheme.Compare(tt.a, tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := SchemeNone.Compare("1", "2"); err == nil {
+		t.Error("expected SchemeNone.Compare to fail")
+	}
+	if _, err := SchemeCalver.Compare("1.0.0", "2024.01"); err == nil {
+		t.Error("expected a 1-digit year to be rejected as calver")
+	}
+}
+
+func TestParseVersionScheme(t *testing.T) {
+	for _, s := range []string{"", "none", "semver", "calver"} {
+		if _, err := ParseVersionScheme(s); err != nil {
+			t.Errorf("ParseVersionScheme(%q) = %v", s, err)
+		}
+	}
+	if _, err := ParseVersionScheme("romver"); err == nil {
+		t.Error("expected error for unknown scheme")
+	}
+}
+
+func TestValidate_VersionScheme(t *testing.T) {
+	tests := map[string]struct {
+		changelog *Changelog
+		scheme    string
+		wantErrs  []string
+	}{
+		"ordered":       {changelog: schemeChangelog("0.10.0", "0.2.0", "0.1.0"), scheme: "semver"},
+		"no scheme":     {changelog: schemeChangelog("0.2.0", "banana", "0.10.0"), scheme: ""},
+		"unparsable":    {changelog: schemeChangelog("1.0.0", "banana"), scheme: "semver", wantErrs: []string{`invalid semver version "banana"`}},
+		"out of order":  {changelog: schemeChangelog("0.2.0", "0.10.0"), scheme: "semver", wantErrs: []string{"descending order"}},
+		"unknown":       {changelog: schemeChangelog("1.0.0"), scheme: "romver", wantErrs: []string{"unknown version scheme"}},
+		"calver":        {changelog: schemeChangelog("2024.10", "2024.9.1"), scheme: "calver"},
+		"calver semver": {changelog: schemeChangelog("1.0.0"), scheme: "calver", wantErrs: []string{"invalid calver"}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			errs := Validate(tt.changelog, &Config{VersionScheme: tt.scheme})
+			if len(errs) != len(tt.wantErrs) {
+				t.Fatalf("got %d errors %v, want %d", len(errs), errs, len(tt.wantErrs))
+			}
+			for i, want := range tt.wantErrs {
+				if !strings.Contains(errs[i].Error(), want) {
+					t.Errorf("error %d = %q, want containing %q", i, errs[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidate_DatesMustNotDecrease(t *testing.T) {
+	c := schemeChangelog("1.1.0", "1.0.0")
+	c.Versions[2].Date = "2025-01-01"
+	errs := Validate(c, &Config{VersionScheme: "semver"})
+	if len(errs) != 1 || errs[0].Field != "versions[2].date" {
+		t.Errorf("expected a single date ordering error, got %v", errs)
+	}
+}
+
+func TestRelease_VersionScheme(t *testing.T) {
+	tests := map[string]struct {
+		version string
+		wantErr string
+	}{
+		"greater":     {version: "1.3.0"},
+		"lower":       {version: "1.1.0", wantErr: `"1.1.0" is not greater than the latest release "1.2.0"`},
+		"prerelease":  {version: "1.2.0-rc.1", wantErr: "not greater"},
+		"unparsable":  {version: "next", wantErr: "invalid semver"},
+		"build equal": {version: "1.2.0+2", wantErr: "not greater"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := schemeChangelog("1.2.0")
+			c.Versions[0].Public.Append("added", "New")
+			err := c.Release(tt.version, "2024-06-01", ReleaseOptions{Scheme: SchemeSemver})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSort(t *testing.T) {
+	c := schemeChangelog("0.2.0", "1.0.0", "0.10.0", "1.0.0-rc.1")
+	c.Versions = append(c.Versions[1:], c.Versions[0]) // unreleased last
+	if err := c.Sort(SchemeSemver); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"unreleased", "1.0.0", "1.0.0-rc.1", "0.10.0", "0.2.0"}
+	if got := c.ListVersions(); !slices.Equal(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
+
+func TestSort_Errors(t *testing.T) {
+	c := schemeChangelog("1.0.0", "banana")
+	if err := c.Sort(SchemeSemver); err == nil {
+		t.Error("expected error for unparsable version")
+	}
+	if got := c.ListVersions(); got[2] != "banana" {
+		t.Errorf("failed sort modified versions: %v", got)
+	}
+	if err := c.Sort(SchemeNone); err == nil {
+		t.Error("expected error for SchemeNone")
+	}
+}
+
+func TestSort_SavePreservesComments(t *testing.T) {
+	input := "project: demo\nversions:\n  # old\n  0.1.0:\n    date: \"2024-01-01\"\n    added: [a]\n  0.2.0:\n    date: \"2024-02-01\"\n    added: [b]\n"
+	c, err := LoadFromReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Sort(SchemeSemver); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "project: demo\nversions:\n  0.2.0:\n    date: \"2024-02-01\"\n    added: [b]\n  # old\n  0.1.0:\n    date: \"2024-01-01\"\n    added: [a]\n"
+	if string(got) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
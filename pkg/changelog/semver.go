@@ -0,0 +1,109 @@
+package changelog
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// semverRegex is the official SemVer 2.0.0 pattern, with an optional "v" prefix.
+var semverRegex = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+// SemVer is a parsed semantic version.
+type SemVer struct {
+	Major, Minor, Patch int
+	// Prerelease holds the dot-separated identifiers after "-", e.g. "rc.1".
+	Prerelease string
+	// Build holds the metadata after "+"; it does not affect precedence.
+	Build string
+}
+
+// ParseSemver parses a semantic version such as "1.2.3", "v2.0.0-rc.1" or
+// "1.0.0+build.5".
+func ParseSemver(s string) (SemVer, error) {
+	m := semverRegex.FindStringSubmatch(s)
+	if m == nil {
+		return SemVer{}, fmt.Errorf("invalid semver version %q", s)
+	}
+	var nums [3]int
+	for i := range nums {
+		n, err := strconv.Atoi(m[i+1])
+		if err != nil {
+			return SemVer{}, fmt.Errorf("invalid semver version %q: %w", s, err)
+		}
+		nums[i] = n
+	}
+	return SemVer{Major: nums[0], Minor: nums[1], Patch: nums[2], Prerelease: m[4], Build: m[5]}, nil
+}
+
+// String formats the version without a "v" prefix.
+func (v SemVer) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// IsPrerelease reports whether the version has pre-release identifiers.
+func (v SemVer) IsPrerelease() bool {
+	return v.Prerelease != ""
+}
+
+// Compare returns -1, 0 or +1 following SemVer precedence rules: build
+// metadata is ignored and a pre-release sorts before its release.
+func (v SemVer) Compare(o SemVer) int {
+	if c := cmp.Compare(v.Major, o.Major); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(v.Minor, o.Minor); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(v.Patch, o.Patch); c != 0 {
+		return c
+	}
+	return comparePrerelease(v.Prerelease, o.Prerelease)
+}
+
+// comparePrerelease compares dot-separated pre-release identifiers. An empty
+// string (a release) has higher precedence than any pre-release.
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if c := compareIdentifier(as[i], bs[i]); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(as), len(bs))
+}
+
+// compareIdentifier compares numeric identifiers numerically and others
+// lexically; numeric identifiers sort before alphanumeric ones.
+func compareIdentifier(a, b string) int {
+	an, aErr := strconv.Atoi(a)
+	bn, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return cmp.Compare(an, bn)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,61 @@
+package changelog
+
+import "testing"
+
+func TestParseSemver(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    SemVer
+		wantErr bool
+	}{
+		"plain":         {input: "1.2.3", want: SemVer{Major: 1, Minor: 2, Patch: 3}},
+		"v prefix":      {input: "v0.1.0", want: SemVer{Minor: 1}},
+		"prerelease":    {input: "2.0.0-rc.1", want: SemVer{Major: 2, Prerelease: "rc.1"}},
+		"build":         {input: "1.0.0+build.5", want: SemVer{Major: 1, Build: "build.5"}},
+		"two parts":     {input: "1.2", wantErr: true},
+		"leading zero":  {input: "01.2.3", wantErr: true},
+		"word":          {input: "banana", wantErr: true},
+		"empty pre-id":  {input: "1.0.0-rc..1", wantErr: true},
+		"huge number":   {input: "99999999999999999999.0.0", wantErr: true},
+		"trailing junk": {input: "1.0.0 beta", wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseSemver(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSemver(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseSemver(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSemVer_Compare(t *testing.T) {
+	// Each version has lower precedence than the next (semver.org §11).
+	ordered := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2",
+		"1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.2.0", "1.10.0", "2.0.0",
+	}
+	for i := 0; i+1 < len(ordered); i++ {
+		a, _ := ParseSemver(ordered[i])
+		b, _ := ParseSemver(ordered[i+1])
+		if a.Compare(b) != -1 || b.Compare(a) != 1 {
+			t.Errorf("expected %s < %s", ordered[i], ordered[i+1])
+		}
+	}
+
+	a, _ := ParseSemver("1.0.0+build.1")
+	b, _ := ParseSemver("1.0.0+build.2")
+	if a.Compare(b) != 0 {
+		t.Errorf("build metadata should not affect precedence")
+	}
+}
+
+func TestSemVer_String(t *testing.T) {
+	v := SemVer{Major: 1, Minor: 2, Patch: 3, Prerelease: "rc.1", Build: "sha.abc"}
+	if got := v.String(); got != "1.2.3-rc.1+sha.abc" {
+		t.Errorf("String() = %q", got)
+	}
+}
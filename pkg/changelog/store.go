@@ -0,0 +1,173 @@
+package changelog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	// DefaultStoreMaxChars is the App Store limit for "What's New" text.
+	DefaultStoreMaxChars = 4000
+	// PlayStoreMaxChars is the Google Play limit for release notes per locale.
+	PlayStoreMaxChars = 500
+	// DefaultFastlaneLocale is written when no locale is configured or found.
+	DefaultFastlaneLocale = "en-US"
+	// DefaultFastlaneBuild names changelogs/default.txt, which fastlane uses
+	// for builds without their own file.
+	DefaultFastlaneBuild = "default"
+)
+
+// StoreConfig configures the plain-text release notes of
+// `chlog extract --format store` and `chlog export fastlane`.
+type StoreConfig struct {
+	// Order lists categories by priority: when notes are truncated, the
+	// entries of earlier categories are kept. Defaults to Categories, or
+	// DefaultCategories; unlisted categories follow in file order.
+	Order []string `yaml:"order,omitempty"`
+	// MaxChars overrides the length limit of extract --format store.
+	MaxChars int `yaml:"max_chars,omitempty"`
+	// Locales are the fastlane metadata locales to write, e.g. en-US.
+	Locales []string `yaml:"locales,omitempty"`
+}
+
+// Validate checks the length limit and locale names.
+func (s StoreConfig) Validate() error {
+	if s.MaxChars < 0 {
+		return fmt.Errorf("store.max_chars must not be negative")
+	}
+	for _, l := range s.Locales {
+		if !localePattern.MatchString(l) {
+			return fmt.Errorf("store.locales: %q is not a locale like en-US", l)
+		}
+	}
+	return nil
+}
+
+// StoreOptions controls RenderStoreNotes.
+type StoreOptions struct {
+	// MaxChars limits the notes to that many characters; 0 means no limit.
+	MaxChars int
+	// Order lists categories by priority; see StoreConfig.Order.
+	Order []string
+}
+
+// StoreOptions returns the options for store notes of at most maxChars
+// characters, or MaxChars (or DefaultStoreMaxChars) when maxChars is 0.
+func (c *Config) StoreOptions(maxChars int) StoreOptions {
+	if maxChars == 0 {
+		maxChars = c.Store.MaxChars
+	}
+	if maxChars == 0 {
+		maxChars = DefaultStoreMaxChars
+	}
+	order := c.Store.Order
+	if len(order) == 0 {
+		order = c.Categories
+	}
+	if len(order) == 0 {
+		order = DefaultCategories
+	}
+	return StoreOptions{MaxChars: maxChars, Order: order}
+}
+
+// RenderStoreNotes flattens the public entries of v into plain text for an
+// app store, one "• entry" line each with markdown removed, categories in
+// opts.Order. When the notes are longer than opts.MaxChars, whole entries
+// are dropped from the end and an ellipsis line marks the cut.
+func RenderStoreNotes(v *Version, opts StoreOptions) string {
+	cats := slices.Clone(v.Public.Categories)
+	rank := func(name string) int {
+		if i := slices.Index(opts.Order, name); i >= 0 {
+			return i
+		}
+		return len(opts.Order)
+	}
+	slices.SortStableFunc(cats, func(a, b CategoryEntry) int { return rank(a.Name) - rank(b.Name) })
+
+	var lines []string
+	for _, cat := range cats {
+		for _, text := range cat.Entries {
+			lines = append(lines, "• "+plainText(text))
+		}
+	}
+	return fitStoreLines(lines, opts.MaxChars)
+}
+
+// fitStoreLines joins lines, keeping as many leading lines as fit in limit
+// together with a final "…" line when any are dropped. A first line that
+// does not fit on its own is shortened instead.
+func fitStoreLines(lines []string, limit int) string {
+	text := strings.Join(lines, "\n")
+	if limit <= 0 || runeLen(text) <= limit {
+		return text
+	}
+	kept, n := 0, 0
+	for _, l := range lines {
+		if n+runeLen(l)+runeLen("\n…") > limit {
+			break
+		}
+		n += runeLen(l) + 1
+		kept++
+	}
+	if kept == 0 {
+		return truncateText(lines[0], limit)
+	}
+	return strings.Join(append(lines[:kept:kept], "…"), "\n")
+}
+
+// plainText removes the inline markdown of an entry: links keep their text,
+// and bold and code markers are dropped.
+func plainText(md string) string {
+	s := markdownLink.ReplaceAllString(md, "$1")
+	s = markdownBold.ReplaceAllString(s, "$1")
+	return strings.ReplaceAll(s, "`", "")
+}
+
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// FastlaneLocales returns the locale directories in a fastlane metadata
+// directory, e.g. en-US and de-DE, ignoring other entries such as
+// review_information.
+func FastlaneLocales(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading %s: %w", dir, err)
+	}
+	var locales []string
+	for _, e := range entries {
+		if e.IsDir() && localePattern.MatchString(e.Name()) {
+			locales = append(locales, e.Name())
+		}
+	}
+	return locales, nil
+}
+
+// WriteFastlaneChangelogs writes notes to <dir>/<locale>/changelogs/<build>.txt
+// for each locale and returns the paths written.
+func WriteFastlaneChangelogs(dir, build string, locales []string, notes string) ([]string, error) {
+	if build == "" || strings.ContainsAny(build, `/\`) {
+		return nil, fmt.Errorf("invalid build %q", build)
+	}
+	paths := make([]string, 0, len(locales))
+	for _, locale := range locales {
+		if !localePattern.MatchString(locale) {
+			return paths, fmt.Errorf("invalid locale %q", locale)
+		}
+		path := filepath.Join(dir, locale, "changelogs", build+".txt")
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return paths, fmt.Errorf("creating %s: %w", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(notes), 0644); err != nil {
+			return paths, fmt.Errorf("writing %s: %w", path, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
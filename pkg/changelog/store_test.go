@@ -0,0 +1,122 @@
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func storeVersion() *Version {
+	v := &Version{Version: "1.2.0", Date: "2026-03-02"}
+	v.Public.Append("fixed", "Crash on **empty** input")
+	v.Public.Append("added", "Export to `CSV`")
+	v.Public.Append("added", "Dark mode, see [docs](https://acme.dev/dark)")
+	v.Public.Append("docs", "New guide")
+	v.Internal.Append("changed", "Refactored the store")
+	return v
+}
+
+func TestRenderStoreNotes(t *testing.T) {
+	tests := map[string]struct {
+		opts StoreOptions
+		want string
+	}{
+		"default order": {
+			opts: StoreOptions{Order: DefaultCategories},
+			want: "• Export to CSV\n• Dark mode, see docs\n• Crash on empty input\n• New guide",
+		},
+		"custom order": {
+			opts: StoreOptions{Order: []string{"fixed"}},
+			want: "• Crash on empty input\n• Export to CSV\n• Dark mode, see docs\n• New guide",
+		},
+		"cut at entries": {
+			opts: StoreOptions{Order: DefaultCategories, MaxChars: 40},
+			want: "• Export to CSV\n• Dark mode, see docs\n…",
+		},
+		"exact fit": {
+			opts: StoreOptions{Order: DefaultCategories, MaxChars: 78},
+			want: "• Export to CSV\n• Dark mode, see docs\n• Crash on empty input\n• New guide",
+		},
+		"first entry too long": {
+			opts: StoreOptions{Order: DefaultCategories, MaxChars: 10},
+			want: "• Export …",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := RenderStoreNotes(storeVersion(), tt.opts)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if tt.opts.MaxChars > 0 && runeLen(got) > tt.opts.MaxChars {
+				t.Errorf("%d characters, limit %d", runeLen(got), tt.opts.MaxChars)
+			}
+		})
+	}
+}
+
+func TestConfig_StoreOptions(t *testing.T) {
+	tests := map[string]struct {
+		cfg      Config
+		maxChars int
+		want     StoreOptions
+	}{
+		"defaults":   {Config{}, 0, StoreOptions{MaxChars: DefaultStoreMaxChars, Order: DefaultCategories}},
+		"categories": {Config{Categories: []string{"fixed", "added"}}, 0, StoreOptions{MaxChars: DefaultStoreMaxChars, Order: []string{"fixed", "added"}}},
+		"store":      {Config{Store: StoreConfig{Order: []string{"security"}, MaxChars: 300}}, 0, StoreOptions{MaxChars: 300, Order: []string{"security"}}},
+		"flag":       {Config{Store: StoreConfig{MaxChars: 300}}, 500, StoreOptions{MaxChars: 500, Order: DefaultCategories}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := tt.cfg.StoreOptions(tt.maxChars)
+			if got.MaxChars != tt.want.MaxChars || !slices.Equal(got.Order, tt.want.Order) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFastlaneLocales(t *testing.T) {
+	dir := t.TempDir()
+	for _, d := range []string{"en-US", "de-DE", "review_information", "images"} {
+		if err := os.Mkdir(filepath.Join(dir, d), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "copyright.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := FastlaneLocales(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, ",") != "de-DE,en-US" {
+		t.Errorf("got %v", got)
+	}
+	if got, err := FastlaneLocales(filepath.Join(dir, "missing")); err != nil || got != nil {
+		t.Errorf("missing dir: %v, %v", got, err)
+	}
+}
+
+func TestWriteFastlaneChangelogs(t *testing.T) {
+	dir := t.TempDir()
+	paths, err := WriteFastlaneChangelogs(dir, "42", []string{"en-US", "fr-FR"}, "• Notes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 2 {
+		t.Fatalf("paths = %v", paths)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "fr-FR", "changelogs", "42.txt"))
+	if err != nil || string(data) != "• Notes" {
+		t.Errorf("got %q, %v", data, err)
+	}
+
+	for _, bad := range [][2]string{{"../42", "en-US"}, {"42", "../en-US"}} {
+		if _, err := WriteFastlaneChangelogs(dir, bad[0], []string{bad[1]}, ""); err == nil {
+			t.Errorf("build %q, locale %q: expected error", bad[0], bad[1])
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package changelog
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/changelog.md.tmpl
+var defaultTemplateText string
+
+// VersionTemplateName is the template block used to render a single version.
+const VersionTemplateName = "version"
+
+// TemplateData is the data model passed to changelog templates.
+type TemplateData struct {
+	Project string
+	// RepoURL is the resolved repository URL, empty if unknown.
+	RepoURL string
+	// IncludeInternal reports whether internal entries are part of the output.
+	IncludeInternal bool
+	Versions        []TemplateVersion
+	// Links are the comparison link definitions, newest first.
+	Links []CompareLink
+}
+
+// TemplateVersion is a single version in TemplateData.
+type TemplateVersion struct {
+	Version    string
+	Date       string
+	Unreleased bool
+	// CompareURL links to the diff against the previous version, if known.
+	CompareURL string
+	// Categories holds only non-empty categories, in file order.
+	Categories []TemplateCategory
+}
+
+// TemplateCategory is a category of entries in TemplateVersion.
+type TemplateCategory struct {
+	Name    string // e.g. "added"
+	Title   string // e.g. "Added"
+	Entries []TemplateEntry
+}
+
+// TemplateEntry is a single entry in TemplateCategory.
+type TemplateEntry struct {
+	Text string
+	// Markdown is Text with metadata rendered as in the default output.
+	Markdown string
+	// Internal reports whether the entry comes from the internal tier.
+	Internal bool
+	EntryMeta
+}
+
+// CompareLink is a reference-style link definition, e.g. "[1.0.0]: https://…".
+type CompareLink struct {
+	Label string
+	URL   string
+}
+
+// Template renders changelogs with Go text/template.
+type Template struct {
+	tmpl *template.Template
+}
+
+var templateFuncs = template.FuncMap{
+	"title": titleCase,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"join":  strings.Join,
+	"trim":  strings.TrimSpace,
+}
+
+var defaultTemplate = template.Must(template.New("changelog").Funcs(templateFuncs).Parse(defaultTemplateText))
+
+// DefaultTemplate returns the built-in template that produces the standard
+// Keep a Changelog output of RenderMarkdown.
+func DefaultTemplate() *Template {
+	return &Template{tmpl: defaultTemplate}
+}
+
+// DefaultTemplateText returns the source of the built-in template.
+func DefaultTemplateText() string {
+	return defaultTemplateText
+}
+
+// ParseTemplate parses a changelog template. The main template renders the
+// whole document; a "version" block, if defined, renders a single version.
+func ParseTemplate(text string) (*Template, error) {
+	tmpl, err := template.New("changelog").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parsing template: %w", err)
+	}
+	return &Template{tmpl: tmpl}, nil
+}
+
+// LoadTemplate reads and parses a changelog template file.
+func LoadTemplate(path string) (*Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading template: %w", err)
+	}
+	t, err := ParseTemplate(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return t, nil
+}
+
+// Render executes the template for a whole changelog.
+func (t *Template) Render(c *Changelog, w io.Writer, opts ...RenderOptions) error {
+	var opt RenderOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	return t.tmpl.Execute(w, NewTemplateData(c, opt, ResolveRepoURL(opt.Config)))
+}
+
+// RenderVersion executes the template's "version" block for a single version.
+func (t *Template) RenderVersion(v *Version, w io.Writer, opts ...RenderOptions) error {
+	var opt RenderOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	block := t.tmpl.Lookup(VersionTemplateName)
+	if block == nil {
+		return fmt.Errorf("template does not define a %q block", VersionTemplateName)
+	}
+	var repoURL string
+	if opt.Config != nil {
+		repoURL = ResolveRepoURL(opt.Config)
+	}
+	links := newRepoLinks(opt.Config, repoURL)
+	return block.Execute(w, newTemplateVersion(v, opt.IncludeInternal, links))
+}
+
+// NewTemplateData builds the template data model for a changelog.
+func NewTemplateData(c *Changelog, opt RenderOptions, repoURL string) TemplateData {
+	data := TemplateData{
+		Project:         c.Project,
+		RepoURL:         repoURL,
+		IncludeInternal: opt.IncludeInternal,
+	}
+	links := newRepoLinks(opt.Config, repoURL)
+	if repoURL != "" {
+		data.Links = comparisonLinks(c, links)
+	}
+	compare := make(map[string]string, len(data.Links))
+	for _, l := range data.Links {
+		compare[l.Label] = l.URL
+	}
+	for i := range c.Versions {
+		tv := newTemplateVersion(&c.Versions[i], opt.IncludeInternal, links)
+		tv.CompareURL = compare[versionLabel(&c.Versions[i])]
+		data.Versions = append(data.Versions, tv)
+	}
+	return data
+}
+
+// newTemplateVersion flattens a version into template data. Internal entries
+// are merged into same-named categories, as MergedChanges does.
+func newTemplateVersion(v *Version, includeInternal bool, links repoLinks) TemplateVersion {
+	tv := TemplateVersion{Version: v.Version, Date: v.Date, Unreleased: v.IsUnreleased()}
+	index := map[string]int{}
+	add := func(changes Changes, internal bool) {
+		for _, cat := range changes.Categories {
+			if len(cat.Entries) == 0 {
+				continue
+			}
+			i, ok := index[cat.Name]
+			if !ok {
+				i = len(tv.Categories)
+				index[cat.Name] = i
+				tv.Categories = append(tv.Categories, TemplateCategory{Name: cat.Name, Title: titleCase(cat.Name)})
+			}
+			for j, text := range cat.Entries {
+				meta := cat.MetaAt(j)
+				tv.Categories[i].Entries = append(tv.Categories[i].Entries, TemplateEntry{
+					Text:      text,
+					Markdown:  formatEntryMarkdown(text, meta, links),
+					Internal:  internal,
+					EntryMeta: meta,
+				})
+			}
+		}
+	}
+	add(v.Public, false)
+	if includeInternal {
+		add(v.Internal, true)
+	}
+	return tv
+}
+
+// versionLabel returns the link label used for a version heading.
+func versionLabel(v *Version) string {
+	if v.IsUnreleased() {
+		return "Unreleased"
+	}
+	return v.Version
+}
@@ -0,0 +1,130 @@
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func templateTestChangelog() *Changelog {
+	unreleased := Version{Version: "unreleased"}
+	unreleased.Public.Append("added", "WIP")
+	v2 := Version{Version: "2.0.0", Date: "2024-06-01"}
+	v2.Public.Append("added", "Feature")
+	v2.Internal.Append("added", "Test harness")
+	v2.Internal.Append("changed", "Refactor")
+	v1 := Version{Version: "1.0.0", Date: "2024-01-01"}
+	v1.Public.Append("fixed", "Init")
+	return &Changelog{Project: "demo", Versions: []Version{unreleased, v2, v1}}
+}
+
+func TestDefaultTemplate_Golden(t *testing.T) {
+	got, err := RenderMarkdownString(templateTestChangelog(), RenderOptions{
+		IncludeInternal: true,
+		Config:          &Config{RepoURL: "https://github.com/o/r"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# Changelog
+
+All notable changes to demo will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/).
+
+## [Unreleased]
+
+### Added
+
+- WIP
+
+## [2.0.0] - 2024-06-01
+
+### Added
+
+- Feature
+- Test harness
+
+### Changed
+
+- Refactor
+
+## [1.0.0] - 2024-01-01
+
+### Fixed
+
+- Init
+
+[Unreleased]: https://github.com/o/r/compare/v2.0.0...HEAD
+[2.0.0]: https://github.com/o/r/compare/v1.0.0...v2.0.0
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestParseTemplate_Custom(t *testing.T) {
+	tmpl, err := ParseTemplate(`{{ .Project | upper }}
+{{ range .Versions }}{{ template "version" . }}{{ end }}
+{{- define "version" }}* {{ .Version }}{{ if .CompareURL }} <{{ .CompareURL }}>{{ end }}
+{{ range .Categories }}{{ range .Entries }}  {{ if .Internal }}(internal) {{ end }}{{ .Text }}
+{{ end }}{{ end }}{{ end }}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := RenderMarkdownString(templateTestChangelog(), RenderOptions{
+		IncludeInternal: true,
+		Config:          &Config{RepoURL: "https://github.com/o/r"},
+		Template:        tmpl,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `DEMO
+* unreleased <https://github.com/o/r/compare/v2.0.0...HEAD>
+  WIP
+* 2.0.0 <https://github.com/o/r/compare/v1.0.0...v2.0.0>
+  Feature
+  (internal) Test harness
+  (internal) Refactor
+* 1.0.0
+  Init
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	var b strings.Builder
+	if err := RenderVersionMarkdown(&templateTestChangelog().Versions[2], &b, RenderOptions{Template: tmpl}); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != "* 1.0.0\n  Init\n" {
+		t.Errorf("version render = %q", b.String())
+	}
+}
+
+func TestTemplate_RenderVersionWithoutBlock(t *testing.T) {
+	tmpl, err := ParseTemplate("{{ .Project }}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	err = tmpl.RenderVersion(&Version{Version: "1.0.0"}, &b)
+	if err == nil || !strings.Contains(err.Error(), `"version" block`) {
+		t.Errorf("err = %v, want missing block error", err)
+	}
+}
+
+func TestLoadTemplate_Errors(t *testing.T) {
+	if _, err := LoadTemplate(filepath.Join(t.TempDir(), "missing.tmpl")); err == nil {
+		t.Error("expected error for missing file")
+	}
+	path := filepath.Join(t.TempDir(), "bad.tmpl")
+	if err := os.WriteFile(path, []byte("{{ .Project "), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTemplate(path); err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("err = %v, want parse error naming the file", err)
+	}
+}
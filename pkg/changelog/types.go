@@ -1,7 +1,10 @@
 package changelog
 
 import (
+	"errors"
 	"fmt"
+	"regexp"
+	"slices"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -14,12 +17,18 @@ var DefaultCategories = []string{"added", "changed", "deprecated", "removed", "f
 type Changelog struct {
 	Project  string    `yaml:"project"`
 	Versions []Version `yaml:"-"`
+
+	// source is the document this changelog was loaded from, kept so Save
+	// can apply minimal edits instead of rewriting the whole file.
+	source *sourceDoc
+	// overlay tracks entries applied from fragment files.
+	overlay *fragmentOverlay
 }
 
 // UnmarshalYAML implements custom YAML unmarshaling for map-keyed versions format.
 func (c *Changelog) UnmarshalYAML(value *yaml.Node) error {
 	if value.Kind != yaml.MappingNode {
-		return fmt.Errorf("expected mapping node, got %d", value.Kind)
+		return errorAt(value, "expected mapping node, got %d", value.Kind)
 	}
 
 	for i := 0; i < len(value.Content)-1; i += 2 {
@@ -31,32 +40,49 @@ func (c *Changelog) UnmarshalYAML(value *yaml.Node) error {
 			c.Project = val.Value
 		case "versions":
 			if val.Kind != yaml.MappingNode {
-				return fmt.Errorf("versions: expected mapping, got %d", val.Kind)
+				return inField("versions", errorAt(val, "expected mapping, got %d", val.Kind))
+			}
+			if errs := duplicateVersions(val); len(errs) > 0 {
+				return errs
 			}
-			seen := map[string]bool{}
 			for j := 0; j < len(val.Content)-1; j += 2 {
 				versionKey := val.Content[j].Value
 				versionVal := val.Content[j+1]
 
-				if seen[versionKey] {
-					return fmt.Errorf("versions: duplicate key %q", versionKey)
-				}
-				seen[versionKey] = true
-
 				var v Version
 				if err := versionVal.Decode(&v); err != nil {
-					return fmt.Errorf("versions.%s: %w", versionKey, err)
+					return inField(fmt.Sprintf("versions[%d]", j/2), err)
 				}
 				v.Version = versionKey
+				v.key, v.node = val.Content[j], versionVal
 				c.Versions = append(c.Versions, v)
 			}
 		default:
-			return fmt.Errorf("unknown field %q", key)
+			return inField(key, errorAt(value.Content[i], "unknown field %q", key))
 		}
 	}
 	return nil
 }
 
+// duplicateVersions reports each key of the versions mapping that repeats an
+// earlier one.
+func duplicateVersions(node *yaml.Node) ValidationErrors {
+	var errs ValidationErrors
+	seen := map[string]bool{}
+	for j := 0; j < len(node.Content)-1; j += 2 {
+		key := node.Content[j]
+		if seen[key.Value] {
+			errs = append(errs, ValidationError{
+				Rule:    RuleDuplicateVersion,
+				Field:   fmt.Sprintf("versions[%d].version", j/2),
+				Message: fmt.Sprintf("duplicate version %q", key.Value),
+			}.at(key))
+		}
+		seen[key.Value] = true
+	}
+	return errs
+}
+
 // MarshalYAML implements custom YAML marshaling for map-keyed versions format.
 func (c Changelog) MarshalYAML() (interface{}, error) {
 	root := &yaml.Node{Kind: yaml.MappingNode}
@@ -116,10 +142,53 @@ func versionKeyNode(version string) *yaml.Node {
 	return node
 }
 
+// EntryMeta holds optional structured metadata attached to an entry.
+// In CHANGELOG.yaml an entry with metadata is written as a mapping with a
+// "text" key instead of a plain string.
+type EntryMeta struct {
+	PR       int      `yaml:"pr,omitempty" json:"pr,omitempty"`
+	Issues   []string `yaml:"issues,omitempty" json:"issues,omitempty"`
+	Authors  []string `yaml:"authors,omitempty" json:"authors,omitempty"`
+	Scope    string   `yaml:"scope,omitempty" json:"scope,omitempty"`
+	Breaking bool     `yaml:"breaking,omitempty" json:"breaking,omitempty"`
+}
+
+// IsZero returns true if no metadata is set.
+func (m EntryMeta) IsZero() bool {
+	return m.PR == 0 && len(m.Issues) == 0 && len(m.Authors) == 0 && m.Scope == "" && !m.Breaking
+}
+
+// Equal reports whether two metadata values are identical.
+func (m EntryMeta) Equal(o EntryMeta) bool {
+	return m.PR == o.PR && m.Scope == o.Scope && m.Breaking == o.Breaking &&
+		slices.Equal(m.Issues, o.Issues) && slices.Equal(m.Authors, o.Authors)
+}
+
 // CategoryEntry holds entries for a single changelog category.
 type CategoryEntry struct {
 	Name    string
 	Entries []string
+	// Meta is parallel to Entries; it is nil when no entry carries metadata.
+	Meta []EntryMeta
+}
+
+// MetaAt returns the metadata for the entry at index i.
+func (c CategoryEntry) MetaAt(i int) EntryMeta {
+	if i < len(c.Meta) {
+		return c.Meta[i]
+	}
+	return EntryMeta{}
+}
+
+// add appends an entry, allocating Meta only once metadata is needed.
+func (c *CategoryEntry) add(entry string, meta EntryMeta) {
+	if c.Meta == nil && !meta.IsZero() {
+		c.Meta = make([]EntryMeta, len(c.Entries), len(c.Entries)+1)
+	}
+	c.Entries = append(c.Entries, entry)
+	if c.Meta != nil {
+		c.Meta = append(c.Meta, meta)
+	}
 }
 
 // Changes is an ordered collection of changelog categories.
@@ -129,23 +198,38 @@ type Changes struct {
 
 // Get returns the entries for a category, or nil if not found.
 func (c Changes) Get(category string) []string {
+	if cat, ok := c.Category(category); ok {
+		return cat.Entries
+	}
+	return nil
+}
+
+// Category returns the named category and whether it exists.
+func (c Changes) Category(category string) (CategoryEntry, bool) {
 	for _, cat := range c.Categories {
 		if cat.Name == category {
-			return cat.Entries
+			return cat, true
 		}
 	}
-	return nil
+	return CategoryEntry{}, false
 }
 
 // Append adds an entry to a category, creating the category if needed.
 func (c *Changes) Append(category, entry string) {
+	c.AppendWithMeta(category, entry, EntryMeta{})
+}
+
+// AppendWithMeta adds an entry with metadata to a category, creating the category if needed.
+func (c *Changes) AppendWithMeta(category, entry string, meta EntryMeta) {
 	for i := range c.Categories {
 		if c.Categories[i].Name == category {
-			c.Categories[i].Entries = append(c.Categories[i].Entries, entry)
+			c.Categories[i].add(entry, meta)
 			return
 		}
 	}
-	c.Categories = append(c.Categories, CategoryEntry{Name: category, Entries: []string{entry}})
+	cat := CategoryEntry{Name: category}
+	cat.add(entry, meta)
+	c.Categories = append(c.Categories, cat)
 }
 
 // Remove removes an entry from the given category.
@@ -175,6 +259,10 @@ func (c *Changes) removeExact(catIdx int, entries []string, category, text strin
 	for i, e := range entries {
 		if e == text {
 			c.Categories[catIdx].Entries = append(entries[:i], entries[i+1:]...)
+			// Meta may be shorter than Entries, as MetaAt allows.
+			if meta := c.Categories[catIdx].Meta; i < len(meta) {
+				c.Categories[catIdx].Meta = append(meta[:i], meta[i+1:]...)
+			}
 			c.cleanupEmpty(catIdx)
 			return e, nil
 		}
@@ -210,8 +298,20 @@ func (c *Changes) cleanupEmpty(catIdx int) {
 // Merge appends all entries from other into c, preserving order.
 func (c *Changes) Merge(other Changes) {
 	for _, cat := range other.Categories {
-		for _, entry := range cat.Entries {
-			c.Append(cat.Name, entry)
+		for i, entry := range cat.Entries {
+			c.AppendWithMeta(cat.Name, entry, cat.MetaAt(i))
+		}
+	}
+}
+
+// mergeUnique appends entries from other, skipping entries whose text is
+// already present in the same category.
+func (c *Changes) mergeUnique(other Changes) {
+	for _, cat := range other.Categories {
+		for i, entry := range cat.Entries {
+			if !slices.Contains(c.Get(cat.Name), entry) {
+				c.AppendWithMeta(cat.Name, entry, cat.MetaAt(i))
+			}
 		}
 	}
 }
@@ -223,6 +323,15 @@ func (c Changes) Clone() Changes {
 		entries := make([]string, len(cat.Entries))
 		copy(entries, cat.Entries)
 		clone.Categories[i] = CategoryEntry{Name: cat.Name, Entries: entries}
+		if cat.Meta != nil {
+			meta := make([]EntryMeta, len(cat.Meta))
+			for j, m := range cat.Meta {
+				m.Issues = slices.Clone(m.Issues)
+				m.Authors = slices.Clone(m.Authors)
+				meta[j] = m
+			}
+			clone.Categories[i].Meta = meta
+		}
 	}
 	return clone
 }
@@ -253,15 +362,15 @@ func (c Changes) CategoryNames() []string {
 // UnmarshalYAML parses a YAML mapping where each key is a category name.
 func (c *Changes) UnmarshalYAML(value *yaml.Node) error {
 	if value.Kind != yaml.MappingNode {
-		return fmt.Errorf("changes: expected mapping, got %d", value.Kind)
+		return errorAt(value, "expected mapping, got %d", value.Kind)
 	}
 	for i := 0; i < len(value.Content)-1; i += 2 {
 		key := value.Content[i].Value
-		var entries []string
-		if err := value.Content[i+1].Decode(&entries); err != nil {
-			return fmt.Errorf("changes.%s: %w", key, err)
+		cat, err := decodeCategory(key, value.Content[i+1])
+		if err != nil {
+			return inField(key, err)
 		}
-		c.Categories = append(c.Categories, CategoryEntry{Name: key, Entries: entries})
+		c.Categories = append(c.Categories, cat)
 	}
 	return nil
 }
@@ -273,24 +382,108 @@ func (c Changes) MarshalYAML() (interface{}, error) {
 		if len(cat.Entries) == 0 {
 			continue
 		}
-		var entriesNode yaml.Node
-		if err := entriesNode.Encode(cat.Entries); err != nil {
+		entriesNode, err := encodeCategory(cat)
+		if err != nil {
 			return nil, fmt.Errorf("encoding %s: %w", cat.Name, err)
 		}
 		node.Content = append(node.Content,
 			&yaml.Node{Kind: yaml.ScalarNode, Value: cat.Name},
-			&entriesNode,
+			entriesNode,
 		)
 	}
 	return node, nil
 }
 
+// entryFields are the keys allowed in the mapping form of an entry.
+var entryFields = map[string]bool{
+	"text": true, "pr": true, "issues": true, "authors": true, "scope": true, "breaking": true,
+}
+
+// entryNode is the mapping form of an entry in CHANGELOG.yaml.
+type entryNode struct {
+	Text      string `yaml:"text"`
+	EntryMeta `yaml:",inline"`
+}
+
+// decodeEntry parses a single entry, which is either a string or a mapping.
+func decodeEntry(node *yaml.Node) (string, EntryMeta, error) {
+	if node.Kind != yaml.MappingNode {
+		var text string
+		err := node.Decode(&text)
+		return text, EntryMeta{}, decodeErrorAt(node, err)
+	}
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		if key := node.Content[i].Value; !entryFields[key] {
+			return "", EntryMeta{}, errorAt(node.Content[i], "unknown entry field %q", key)
+		}
+	}
+	var e entryNode
+	if err := node.Decode(&e); err != nil {
+		return "", EntryMeta{}, decodeErrorAt(node, err)
+	}
+	return e.Text, e.EntryMeta, nil
+}
+
+// decodeCategory parses the entry list of a single category.
+func decodeCategory(name string, node *yaml.Node) (CategoryEntry, error) {
+	cat := CategoryEntry{Name: name}
+	if node.Kind != yaml.SequenceNode {
+		// Let the decoder handle null values and report type errors.
+		err := node.Decode(&cat.Entries)
+		return cat, decodeErrorAt(node, err)
+	}
+	for i, item := range node.Content {
+		text, meta, err := decodeEntry(item)
+		if err != nil {
+			return cat, inField(fmt.Sprintf("[%d]", i), err)
+		}
+		cat.add(text, meta)
+	}
+	return cat, nil
+}
+
+// encodeEntry emits an entry as a plain string, or as a mapping if it has metadata.
+func encodeEntry(text string, meta EntryMeta) (*yaml.Node, error) {
+	var node yaml.Node
+	var err error
+	if meta.IsZero() {
+		err = node.Encode(text)
+	} else {
+		err = node.Encode(entryNode{Text: text, EntryMeta: meta})
+	}
+	return &node, err
+}
+
+// encodeCategory emits the entry list of a single category.
+func encodeCategory(cat CategoryEntry) (*yaml.Node, error) {
+	seq := &yaml.Node{Kind: yaml.SequenceNode}
+	for i, text := range cat.Entries {
+		item, err := encodeEntry(text, cat.MetaAt(i))
+		if err != nil {
+			return nil, err
+		}
+		seq.Content = append(seq.Content, item)
+	}
+	return seq, nil
+}
+
 // Version represents a single version entry in the changelog.
 type Version struct {
 	Version  string  `yaml:"-"`
 	Date     string  `yaml:"-"`
 	Public   Changes `yaml:"-"`
 	Internal Changes `yaml:"-"`
+
+	// key and node are the YAML nodes this version was decoded from, if any.
+	key  *yaml.Node
+	node *yaml.Node
+}
+
+// Equal reports whether two versions have the same name, date and entries,
+// including metadata, regardless of the YAML they were decoded from.
+func (v *Version) Equal(o *Version) bool {
+	vd := DiffVersions(v, o)
+	return v.Version == o.Version && v.Date == o.Date && len(vd.Categories) == 0
 }
 
 // MergedChanges returns Changes with internal entries merged into a clone of public.
@@ -319,7 +512,7 @@ func (v *Version) IsUnreleased() bool {
 // and everything else is a public category.
 func (v *Version) UnmarshalYAML(value *yaml.Node) error {
 	if value.Kind != yaml.MappingNode {
-		return fmt.Errorf("version: expected mapping, got %d", value.Kind)
+		return errorAt(value, "expected mapping, got %d", value.Kind)
 	}
 
 	for i := 0; i < len(value.Content)-1; i += 2 {
@@ -331,18 +524,15 @@ func (v *Version) UnmarshalYAML(value *yaml.Node) error {
 			v.Date = val.Value
 		case "internal":
 			if err := val.Decode(&v.Internal); err != nil {
-				return fmt.Errorf("version.internal: %w", err)
+				return inField("internal", err)
 			}
 		default:
 			// Everything else is a public category
-			var entries []string
-			if err := val.Decode(&entries); err != nil {
-				return fmt.Errorf("version.%s: %w", key, err)
+			cat, err := decodeCategory(key, val)
+			if err != nil {
+				return inField(key, err)
 			}
-			v.Public.Categories = append(v.Public.Categories, CategoryEntry{
-				Name:    key,
-				Entries: entries,
-			})
+			v.Public.Categories = append(v.Public.Categories, cat)
 		}
 	}
 	return nil
@@ -364,13 +554,13 @@ func (v Version) MarshalYAML() (interface{}, error) {
 		if len(cat.Entries) == 0 {
 			continue
 		}
-		var entriesNode yaml.Node
-		if err := entriesNode.Encode(cat.Entries); err != nil {
+		entriesNode, err := encodeCategory(cat)
+		if err != nil {
 			return nil, fmt.Errorf("encoding %s: %w", cat.Name, err)
 		}
 		node.Content = append(node.Content,
 			&yaml.Node{Kind: yaml.ScalarNode, Value: cat.Name},
-			&entriesNode,
+			entriesNode,
 		)
 	}
 
@@ -391,19 +581,124 @@ func (v Version) MarshalYAML() (interface{}, error) {
 
 // Entry is a flattened view of a single changelog entry with metadata.
 type Entry struct {
-	Text     string
-	Category string
-	Version  string
+	Text     string `json:"text"`
+	Category string `json:"category"`
+	Version  string `json:"version"`
+	EntryMeta
 }
 
-// ValidationError describes a validation failure with context.
+// ValidationError describes a validation failure with context. Rule is the
+// stable ID of the failed check. Line and Column locate it in the YAML source
+// (1-based), or are 0 when unknown, as for changelogs built in code. File is
+// set when the error is in a fragment rather than the changelog itself.
 type ValidationError struct {
+	Rule    string
 	Field   string
 	Message string
+	File    string
+	Line    int
+	Column  int
 }
 
 func (e ValidationError) Error() string {
-	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+	var pos string
+	if e.File != "" {
+		pos = e.File + ":"
+	}
+	if e.Line > 0 {
+		pos += fmt.Sprintf("%d:%d:", e.Line, e.Column)
+	}
+	if pos != "" {
+		pos += " "
+	}
+	return fmt.Sprintf("%s%s: %s", pos, e.Field, e.Message)
+}
+
+// at positions e at node, if the node is known.
+func (e ValidationError) at(node *yaml.Node) ValidationError {
+	if node != nil {
+		e.Line, e.Column = node.Line, node.Column
+	}
+	return e
+}
+
+// decodeError is a schema error at a YAML node, found while decoding. Load
+// reports it as a positioned parse-error.
+type decodeError struct {
+	node  *yaml.Node
+	field string
+	msg   string
+}
+
+func (e *decodeError) Error() string {
+	if e.field == "" {
+		return e.msg
+	}
+	return e.field + ": " + e.msg
+}
+
+// validationError converts e into the ValidationError Load reports.
+func (e *decodeError) validationError() ValidationError {
+	return ValidationError{Rule: RuleParseError, Field: e.field, Message: e.msg}.at(e.node)
+}
+
+func errorAt(node *yaml.Node, format string, a ...any) error {
+	return &decodeError{node: node, msg: fmt.Sprintf(format, a...)}
+}
+
+var typeErrorLine = regexp.MustCompile(`^line \d+: `)
+
+// decodeErrorAt positions an error of decoding node at the node, replacing
+// the bare line numbers of a yaml.TypeError. Other errors pass through.
+func decodeErrorAt(node *yaml.Node, err error) error {
+	var te *yaml.TypeError
+	if !errors.As(err, &te) {
+		return err
+	}
+	msgs := make([]string, len(te.Errors))
+	for i, m := range te.Errors {
+		msgs[i] = typeErrorLine.ReplaceAllString(m, "")
+	}
+	return &decodeError{node: node, msg: strings.Join(msgs, "; ")}
+}
+
+// inField places a decoding error under field, e.g. "added" and "[0]" give
+// "added[0]".
+func inField(field string, err error) error {
+	var de *decodeError
+	if !errors.As(err, &de) {
+		return fmt.Errorf("%s: %w", field, err)
+	}
+	switch {
+	case de.field == "":
+		de.field = field
+	case strings.HasPrefix(de.field, "["):
+		de.field = field + de.field
+	default:
+		de.field = field + "." + de.field
+	}
+	return de
+}
+
+// ValidationErrors is the error Load and LoadFromReader return when a
+// changelog fails validation. Use errors.As to reach the individual errors.
+type ValidationErrors []ValidationError
+
+func (errs ValidationErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
+	}
+	return "validation failed:\n  " + strings.Join(msgs, "\n  ")
+}
+
+// Unwrap returns the individual errors, for errors.Is and errors.As.
+func (errs ValidationErrors) Unwrap() []error {
+	out := make([]error, len(errs))
+	for i, e := range errs {
+		out[i] = e
+	}
+	return out
 }
 
 // VersionNotFoundError indicates a requested version does not exist.
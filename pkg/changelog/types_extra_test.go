@@ -2,6 +2,7 @@ package changelog
 
 import (
 	"errors"
+	"strings"
 	"testing"
 )
 
@@ -150,3 +151,125 @@ func TestChanges_CategoryNames(t *testing.T) {
 		t.Fatalf("CategoryNames() = %d, want 2", len(names))
 	}
 }
+
+func TestLoadFromReader_StructuredEntries(t *testing.T) {
+	input := `project: meta
+versions:
+  1.0.0:
+    date: "2024-01-01"
+    added:
+      - Plain entry
+      - text: Add pagination
+        pr: 42
+        issues: [40, JIRA-7]
+        authors: [alice]
+        scope: api
+        breaking: true
+`
+	c, err := LoadFromReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cat, ok := c.Versions[0].Public.Category("added")
+	if !ok {
+		t.Fatal("expected added category")
+	}
+	if len(cat.Entries) != 2 || cat.Entries[1] != "Add pagination" {
+		t.Fatalf("entries = %v", cat.Entries)
+	}
+	if !cat.MetaAt(0).IsZero() {
+		t.Errorf("plain entry meta = %+v, want zero", cat.MetaAt(0))
+	}
+	want := EntryMeta{PR: 42, Issues: []string{"40", "JIRA-7"}, Authors: []string{"alice"}, Scope: "api", Breaking: true}
+	if got := cat.MetaAt(1); !got.Equal(want) {
+		t.Errorf("meta = %+v, want %+v", got, want)
+	}
+
+	entries := c.AllEntries()
+	if entries[1].PR != 42 || entries[1].Scope != "api" {
+		t.Errorf("flattened entry = %+v", entries[1])
+	}
+}
+
+func TestLoadFromReader_UnknownEntryField(t *testing.T) {
+	input := `project: meta
+versions:
+  unreleased:
+    added:
+      - text: Thing
+        ticket: 12
+`
+	_, err := LoadFromReader(strings.NewReader(input))
+	if err == nil || !strings.Contains(err.Error(), `unknown entry field "ticket"`) {
+		t.Errorf("err = %v, want unknown entry field", err)
+	}
+}
+
+func TestChanges_MetaFollowsEntries(t *testing.T) {
+	var c Changes
+	c.Append("added", "A")
+	c.AppendWithMeta("added", "B", EntryMeta{PR: 2})
+	c.Append("added", "C")
+
+	cat, _ := c.Category("added")
+	if len(cat.Meta) != 3 || cat.MetaAt(1).PR != 2 || !cat.MetaAt(2).IsZero() {
+		t.Fatalf("meta = %+v", cat.Meta)
+	}
+
+	clone := c.Clone()
+	if _, err := c.Remove("added", "A", false); err != nil {
+		t.Fatal(err)
+	}
+	cat, _ = c.Category("added")
+	if cat.MetaAt(0).PR != 2 {
+		t.Errorf("after remove, meta[0] = %+v, want PR 2", cat.MetaAt(0))
+	}
+	if cloned, _ := clone.Category("added"); cloned.MetaAt(1).PR != 2 {
+		t.Errorf("clone affected by remove: %+v", cloned.Meta)
+	}
+
+	var merged Changes
+	merged.Merge(c)
+	if m, _ := merged.Category("added"); m.MetaAt(0).PR != 2 {
+		t.Errorf("merge dropped metadata: %+v", m.Meta)
+	}
+}
+
+func TestChanges_RemoveWithShortMeta(t *testing.T) {
+	c := Changes{Categories: []CategoryEntry{{Name: "added", Entries: []string{"A", "B", "C"}, Meta: []EntryMeta{{PR: 1}}}}}
+	if _, err := c.Remove("added", "C", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Remove("added", "A", false); err != nil {
+		t.Fatal(err)
+	}
+	cat, _ := c.Category("added")
+	if len(cat.Entries) != 1 || cat.Entries[0] != "B" || !cat.MetaAt(0).IsZero() {
+		t.Errorf("after remove: %+v", cat)
+	}
+}
+
+func TestMarshalYAML_StructuredEntries(t *testing.T) {
+	v := Version{Version: "1.0.0", Date: "2024-01-01"}
+	v.Public.Append("added", "Plain")
+	v.Public.AppendWithMeta("added", "Linked", EntryMeta{PR: 3})
+	data, err := MarshalVersionEntry(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	if !strings.Contains(out, "- Plain\n") || !strings.Contains(out, "- text: Linked\n") || !strings.Contains(out, "pr: 3") {
+		t.Errorf("unexpected YAML:\n%s", out)
+	}
+}
+
+func TestValidationErrors_Error(t *testing.T) {
+	errs := ValidationErrors{
+		{Field: "project", Message: "must not be empty", Line: 1, Column: 10},
+		{Field: "version_scheme", Message: "unknown scheme"},
+	}
+	want := "validation failed:\n  1:10: project: must not be empty\n  version_scheme: unknown scheme"
+	if got := errs.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
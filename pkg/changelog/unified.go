@@ -0,0 +1,112 @@
+package changelog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiffHunk is one hunk of a unified diff. Lines keep their " ", "-" or "+"
+// prefix. Starts are 1-based, and Line is the first differing line in the
+// old text, for pointing annotations at it.
+type DiffHunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+	Line               int
+	Lines              []string
+}
+
+// Header returns the "@@ -a,b +c,d @@" line of the hunk.
+func (h DiffHunk) Header() string {
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+}
+
+// UnifiedDiff returns the hunks turning old into updated, each with up to
+// context unchanged lines around its changes. Hunks whose context would
+// overlap are joined.
+func UnifiedDiff(old, updated string, context int) []DiffHunk {
+	a, b := splitTextLines(old), splitTextLines(updated)
+	ops := diffLines(a, b)
+	context = max(context, 0)
+
+	var hunks []DiffHunk
+	for i := 0; i < len(ops); {
+		if ops[i].Kind == '=' {
+			i++
+			continue
+		}
+		last := i
+		for j := i + 1; j < len(ops) && j-last <= 2*context; j++ {
+			if ops[j].Kind != '=' {
+				last = j
+			}
+		}
+		start, end := max(0, i-context), min(len(ops), last+context+1)
+		hunks = append(hunks, buildHunk(ops[:start], ops[start:end], a, b))
+		i = end
+	}
+	return hunks
+}
+
+// buildHunk renders ops as a hunk; before holds the ops preceding it, used
+// to find its start lines.
+func buildHunk(before, ops []lineOp, a, b []string) DiffHunk {
+	var h DiffHunk
+	for _, op := range before {
+		if op.A >= 0 {
+			h.OldStart++
+		}
+		if op.B >= 0 {
+			h.NewStart++
+		}
+	}
+	h.Line = -1
+	for _, op := range ops {
+		if op.Kind != '=' && h.Line < 0 {
+			h.Line = h.OldStart + h.OldLines + 1
+		}
+		switch op.Kind {
+		case '=':
+			h.Lines = append(h.Lines, " "+a[op.A])
+			h.OldLines++
+			h.NewLines++
+		case '-':
+			h.Lines = append(h.Lines, "-"+a[op.A])
+			h.OldLines++
+		default:
+			h.Lines = append(h.Lines, "+"+b[op.B])
+			h.NewLines++
+		}
+	}
+	h.Line = max(1, min(h.Line, len(a)))
+	// As in diff -u, an empty side starts at the line before the hunk.
+	if h.OldLines > 0 {
+		h.OldStart++
+	}
+	if h.NewLines > 0 {
+		h.NewStart++
+	}
+	return h
+}
+
+// FormatUnifiedDiff renders hunks as a unified diff with file headers.
+func FormatUnifiedDiff(oldName, newName string, hunks []DiffHunk) string {
+	if len(hunks) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+	for _, h := range hunks {
+		b.WriteString(h.Header() + "\n")
+		for _, line := range h.Lines {
+			b.WriteString(line + "\n")
+		}
+	}
+	return b.String()
+}
+
+func splitTextLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
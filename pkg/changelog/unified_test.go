@@ -0,0 +1,86 @@
+package changelog
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func numberedLines(n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = string(rune('a' + i))
+	}
+	return lines
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	base := numberedLines(12)
+	tests := map[string]struct {
+		edit    func([]string) []string
+		context int
+		want    string
+		lines   []int
+	}{
+		"single change": {
+			edit:    func(l []string) []string { l[5] = "F"; return l },
+			context: 1,
+			want:    "@@ -5,3 +5,3 @@\n e\n-f\n+F\n g\n",
+			lines:   []int{6},
+		},
+		"separate hunks": {
+			edit:    func(l []string) []string { l[1], l[10] = "B", "K"; return l },
+			context: 1,
+			want:    "@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n@@ -10,3 +10,3 @@\n j\n-k\n+K\n l\n",
+			lines:   []int{2, 11},
+		},
+		"joined when context overlaps": {
+			edit:    func(l []string) []string { l[3], l[6] = "D", "G"; return l },
+			context: 2,
+			want:    "@@ -2,8 +2,8 @@\n b\n c\n-d\n+D\n e\n f\n-g\n+G\n h\n i\n",
+			lines:   []int{4},
+		},
+		"insertion without context": {
+			edit:    func(l []string) []string { return append(l[:2:2], append([]string{"new"}, l[2:]...)...) },
+			context: 0,
+			want:    "@@ -2,0 +3,1 @@\n+new\n",
+			lines:   []int{3},
+		},
+		"append at end": {
+			edit:    func(l []string) []string { return append(l, "m") },
+			context: 0,
+			want:    "@@ -12,0 +13,1 @@\n+m\n",
+			lines:   []int{12},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			updated := tt.edit(append([]string(nil), base...))
+			hunks := UnifiedDiff(strings.Join(base, "\n")+"\n", strings.Join(updated, "\n")+"\n", tt.context)
+
+			var got strings.Builder
+			var lines []int
+			for _, h := range hunks {
+				got.WriteString(h.Header() + "\n" + strings.Join(h.Lines, "\n") + "\n")
+				lines = append(lines, h.Line)
+			}
+			if got.String() != tt.want {
+				t.Errorf("diff =\n%s\nwant\n%s", got.String(), tt.want)
+			}
+			if !slices.Equal(lines, tt.lines) {
+				t.Errorf("lines = %v, want %v", lines, tt.lines)
+			}
+		})
+	}
+}
+
+func TestFormatUnifiedDiff(t *testing.T) {
+	hunks := UnifiedDiff("a\nb\n", "a\nc\n", 3)
+	want := "--- CHANGELOG.md\n+++ CHANGELOG.md (rendered)\n@@ -1,2 +1,2 @@\n a\n-b\n+c\n"
+	if got := FormatUnifiedDiff("CHANGELOG.md", "CHANGELOG.md (rendered)", hunks); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if got := FormatUnifiedDiff("a", "b", UnifiedDiff("x\n", "x\n", 3)); got != "" {
+		t.Errorf("identical input should give no diff, got %q", got)
+	}
+}
@@ -34,18 +34,8 @@ if ! ./bin/chlog show unreleased | grep -q .; then
   exit 1
 fi
 
-echo "==> Stamping changelog: ${BARE_VERSION}..."
-./bin/chlog release "${BARE_VERSION}"
-
-echo "==> Syncing CHANGELOG.md..."
-./bin/chlog sync
-
-echo "==> Committing changelog..."
-git add CHANGELOG.yaml CHANGELOG.md
-git commit -m "release: ${TAG}"
-
-echo "==> Tagging ${TAG}..."
-git tag -a "${TAG}" -m "Release ${TAG}"
+echo "==> Releasing ${BARE_VERSION}: stamp, sync, commit and tag ${TAG}..."
+./bin/chlog release "${BARE_VERSION}" --tag
 
 echo "==> Pushing to origin..."
 git push origin main